@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/consulta-ruc-scraper/pkg/ciiu"
+)
+
+// Consulta del catálogo CIIU embebido en pkg/ciiu.
+//
+//	go run ./cmd/ciiu buscar contabilidad
+//	go run ./cmd/ciiu -rev 3 ver 74120
+//	go run ./cmd/ciiu equivalencias 74120
+//	go run ./cmd/ciiu filtro-sql 69
+//
+// filtro-sql imprime la condición que usa main.sh (CIIU_FILTRO) para elegir de
+// empresas_sunat los RUC de una sección o división Rev.4, incluidas las clases
+// Rev.3 que corresponden a ella.
+func main() {
+	rev := flag.Int("rev", 4, "revisión CIIU (3 o 4)")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "uso: ciiu [-rev 3|4] buscar <texto> | ver <código> | clases <sección o división> | equivalencias <clase Rev.3> | filtro-sql <sección o división Rev.4>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	revision := ciiu.Revision(*rev)
+	argumento := strings.Join(flag.Args()[1:], " ")
+
+	var (
+		codigos []ciiu.Codigo
+		err     error
+	)
+	switch flag.Arg(0) {
+	case "buscar":
+		codigos, err = ciiu.Buscar(revision, argumento)
+	case "ver":
+		var c ciiu.Codigo
+		if c, err = ciiu.Obtener(revision, argumento); err == nil {
+			fmt.Printf("%s %s (sección %s, división %s)\n", revision, c, c.Seccion, c.Division)
+			return
+		}
+	case "clases":
+		codigos, err = ciiu.Clases(revision, argumento)
+	case "equivalencias":
+		codigos, err = ciiu.Rev3ARev4(argumento)
+	case "filtro-sql":
+		var filtro string
+		if filtro, err = filtroSQL(argumento); err == nil {
+			fmt.Println(filtro)
+			return
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	for _, c := range codigos {
+		fmt.Println(c)
+	}
+}
+
+// filtroSQL arma la condición sobre las columnas de actividad de empresas_sunat.
+// Esas columnas traen la descripción de la actividad (a veces precedida del
+// código), por lo que se compara tanto el código al inicio como la descripción.
+func filtroSQL(objetivo string) (string, error) {
+	rev4, err := ciiu.Clases(ciiu.Rev4, objetivo)
+	if err != nil {
+		return "", err
+	}
+
+	vistos := map[string]bool{}
+	var rev3 []ciiu.Codigo
+	for _, c := range rev4 {
+		origenes, err := ciiu.Rev4ARev3(c.Codigo)
+		if err != nil {
+			return "", err
+		}
+		for _, o := range origenes {
+			if !vistos[o.Codigo] {
+				vistos[o.Codigo] = true
+				rev3 = append(rev3, o)
+			}
+		}
+	}
+
+	condiciones := []string{condicionColumna("es.actividad_economica_ciiu_rev4_principal", rev4)}
+	if len(rev3) > 0 {
+		condiciones = append(condiciones,
+			condicionColumna("es.actividad_economica_ciiu_rev3_principal", rev3),
+			condicionColumna("es.actividad_economica_ciiu_rev3_secundaria", rev3))
+	}
+	return "(" + strings.Join(condiciones, "\n OR ") + ")", nil
+}
+
+func condicionColumna(columna string, codigos []ciiu.Codigo) string {
+	prefijos := make([]string, len(codigos))
+	descripciones := make([]string, len(codigos))
+	for i, c := range codigos {
+		prefijos[i] = c.Codigo
+		descripciones[i] = "'" + strings.ReplaceAll(c.Descripcion, "'", "''") + "'"
+	}
+	return fmt.Sprintf("%s ~ '^(%s)' OR upper(%s) IN (%s)",
+		columna, strings.Join(prefijos, "|"), columna, strings.Join(descripciones, ", "))
+}
@@ -1,21 +1,76 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/consulta-ruc-scraper/pkg/database"
 	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/ruc"
 	"github.com/consulta-ruc-scraper/pkg/scraper"
+	"github.com/consulta-ruc-scraper/pkg/secciones"
 )
 
-// Función main modificada
+// main termina con código 1 si algún RUC falló; main.sh usa el código de salida
+// y la línea "🛑 Terminando programa debido a falla en:" para clasificar la consulta.
+// La línea "🏷️ Estado de la consulta:" informa el estado de log_consultas según scraper.Estado.
+// Si ningún RUC falló pero alguno es inválido o no existe en SUNAT, termina con
+// codigoInvalido o codigoInexistente.
 func main() {
+	os.Exit(run())
+}
+
+// Códigos de salida con que main.sh marca el RUC como inexistente o inválido
+const (
+	codigoInexistente = 3
+	codigoInvalido    = 4
+)
+
+func run() int {
+	sections := flag.String("sections", "", "secciones a consultar separadas por coma, p. ej. basic,deuda,representantes (por defecto todas: "+strings.Join(secciones.Nombres(), ",")+")")
+	timeout := flag.Duration("timeout", 15*time.Minute, "tiempo máximo por RUC; al vencer se pasa al siguiente")
+	flag.Parse()
+
+	// Ctrl+C cancela el RUC en curso y termina el lote
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	rucs := []string{"20606316977"}
-	if len(os.Args) > 1 {
-		rucs = os.Args[1:]
+	if flag.NArg() > 0 {
+		rucs = flag.Args()
+	}
+
+	// Los números inválidos se reportan sin abrir el navegador
+	var validos []string
+	for _, numero := range rucs {
+		if _, err := ruc.Parse(numero); err != nil {
+			log.Printf("🚫 RUC inválido %v", err)
+			fmt.Printf("🏷️ Estado de la consulta: %s\n", scraper.EstadoInvalido)
+			continue
+		}
+		validos = append(validos, numero)
+	}
+	invalidos := len(rucs) - len(validos)
+	if len(validos) == 0 {
+		return codigoInvalido
+	}
+	rucs = validos
+
+	var opts scraper.OpcionesConsulta
+	if *sections != "" {
+		opts.Secciones = strings.Split(*sections, ",")
+		if _, err := secciones.Seleccionar(opts.Secciones); err != nil {
+			log.Print(err)
+			return 2
+		}
 	}
 
 	// Configuración de la base de datos
@@ -28,80 +83,126 @@ func main() {
 	// Crear conexión a la base de datos
 	dbService, err := database.NewDatabaseService(dbConnectionString)
 	if err != nil {
-		log.Fatal("Error conectando a la base de datos:", err)
+		log.Print("Error conectando a la base de datos:", err)
+		return 1
 	}
 	defer dbService.Close()
 
-	// Crear scraper extendido
-	scraperExt, err := scraper.NewScraperExtendido()
+	// Crear scraper (SUNAT_BASE_URL permite apuntar al mock)
+	var sunat scraper.Scraper
+	sunat, err = scraper.NewScraper(scraper.DefaultConfig())
 	if err != nil {
-		log.Fatal("Error creating extended scraper:", err)
+		log.Print("Error creating scraper:", err)
+		return 1
 	}
-	defer scraperExt.Close()
+	defer sunat.Close()
 
-	for i, ruc := range rucs {
-		log.Printf("[%d/%d] Procesando RUC: %s", i+1, len(rucs), ruc)
+	fallidos, inexistentes := 0, 0
+	for i, numero := range rucs {
+		if ctx.Err() != nil {
+			log.Println("🛑 Proceso interrumpido")
+			break
+		}
+		log.Printf("[%d/%d] Procesando RUC: %s", i+1, len(rucs), numero)
+
+		// Obtener información completa del RUC; si falla una sección se recibe lo obtenido hasta entonces
+		rucCtx, cancel := context.WithTimeout(ctx, *timeout)
+		rucCompleto, err := sunat.Consultar(rucCtx, numero, opts)
+		cancel()
+
+		// SUNAT no tiene registrado el RUC: se guarda para no volver a consultarlo
+		if errors.Is(err, scraper.ErrRUCNoEncontrado) {
+			inexistentes++
+			log.Printf("🚫 RUC %s no existe en SUNAT", numero)
+			fmt.Printf("🏷️ Estado de la consulta: %s\n", scraper.Estado(err))
+			if dbErr := dbService.RegistrarRUCInexistente(numero); dbErr != nil {
+				log.Printf("❌ %v", dbErr)
+				fallidos++
+			}
+			continue
+		}
 
-		// Obtener información completa del RUC (ahora pasa dbService)
-		rucCompleto, err := scraperExt.ScrapeRUCCompleto(ruc, dbService)
+		if err != nil {
+			fallidos++
+			var scrapeErr *scraper.ScrapeError
+			if errors.As(err, &scrapeErr) {
+				fmt.Printf("🛑 Terminando programa debido a falla en: %s\n", scrapeErr.Seccion)
+			}
+			fmt.Printf("🏷️ Estado de la consulta: %s\n", scraper.Estado(err))
+		}
 
 		// Guardar en la base de datos incluso si hay errores parciales
 		if rucCompleto != nil {
 			// Insertar en la base de datos
 			dbErr := dbService.InsertRUCCompleto(rucCompleto)
 			if dbErr != nil {
-				log.Printf("❌ Error guardando RUC %s en la base de datos: %v", ruc, dbErr)
+				log.Printf("❌ Error guardando RUC %s en la base de datos: %v", numero, dbErr)
+				fallidos++
 				continue
 			}
 
 			// Determinar el tipo de resultado
 			if err != nil {
 				log.Printf("⚠️ RUC %s (%s) guardado con datos parciales - Error: %v",
-					ruc, getValueOrDefault(rucCompleto.InformacionBasica.RazonSocial), err)
+					numero, getValueOrDefault(rucCompleto.InformacionBasica.RazonSocial), err)
 			} else {
 				log.Printf("✅ RUC %s (%s) guardado exitosamente",
-					ruc, getValueOrDefault(rucCompleto.InformacionBasica.RazonSocial))
+					numero, getValueOrDefault(rucCompleto.InformacionBasica.RazonSocial))
 			}
 
 			showSummary(rucCompleto)
 		} else {
-			log.Printf("❌ Error crítico obteniendo información del RUC %s: %v", ruc, err)
-			continue
+			log.Printf("❌ Error crítico obteniendo información del RUC %s: %v", numero, err)
 		}
 	}
 	log.Println("Proceso completado.")
+
+	if fallidos > 0 {
+		return 1
+	}
+	if invalidos > 0 {
+		return codigoInvalido
+	}
+	if inexistentes > 0 {
+		return codigoInexistente
+	}
+	return 0
 }
 
-func showSummary(ruc *models.RUCCompleto) {
+func showSummary(rc *models.RUCCompleto) {
 	log.Printf("   Estado: %s | Condición: %s",
-		getValueOrDefault(ruc.InformacionBasica.Estado),
-		getValueOrDefault(ruc.InformacionBasica.Condicion))
+		getValueOrDefault(rc.InformacionBasica.Estado),
+		getValueOrDefault(rc.InformacionBasica.Condicion))
 
 	// Información adicional disponible
 	var info []string
-	if ruc.DeudaCoactiva != nil && ruc.DeudaCoactiva.CantidadDocumentos > 0 {
+	if rc.DeudaCoactiva != nil && rc.DeudaCoactiva.CantidadDocumentos > 0 {
 		info = append(info, "Deuda Coactiva")
 	}
-	if ruc.RepresentantesLegales != nil {
-		vigentes := countActiveRepresentatives(ruc.RepresentantesLegales.Representantes)
+	if rc.DeudaCoactiva != nil && len(rc.DeudaCoactiva.FilasInvalidas) > 0 {
+		log.Printf("   ⚠️  Deuda coactiva: %d fila(s) con monto ilegible no suman en el total (%s)",
+			len(rc.DeudaCoactiva.FilasInvalidas), rc.DeudaCoactiva.TotalDeuda)
+	}
+	if rc.RepresentantesLegales != nil {
+		vigentes := countActiveRepresentatives(rc.RepresentantesLegales.Representantes)
 		if vigentes > 0 {
 			info = append(info, "Representantes")
 		}
 	}
-	if ruc.EstablecimientosAnexos != nil && ruc.EstablecimientosAnexos.CantidadAnexos > 0 {
+	if rc.EstablecimientosAnexos != nil && rc.EstablecimientosAnexos.CantidadAnexos > 0 {
 		info = append(info, "Establecimientos")
 	}
-	if ruc.OmisionesTributarias != nil && ruc.OmisionesTributarias.TieneOmisiones {
+	if rc.OmisionesTributarias != nil && rc.OmisionesTributarias.TieneOmisiones {
 		info = append(info, "Omisiones")
 	}
-	if ruc.ActasProbatorias != nil && ruc.ActasProbatorias.TieneActas {
+	if rc.ActasProbatorias != nil && rc.ActasProbatorias.TieneActas {
 		info = append(info, "Actas")
 	}
 
 	if len(info) > 0 {
 		log.Printf("   Datos adicionales: %s", strings.Join(info, ", "))
 	}
-	showPaginationSummary(ruc)
+	showPaginationSummary(rc)
 }
 
 func showPaginationSummary(rucCompleto *models.RUCCompleto) {
@@ -111,22 +212,16 @@ func showPaginationSummary(rucCompleto *models.RUCCompleto) {
 
 	fmt.Println("\n📄 DETECCIÓN DE PAGINACIÓN:")
 
-	secciones := []string{
-		"Información Histórica",
-		"Deuda Coactiva",
-		"Omisiones Tributarias",
-		"Cantidad de Trabajadores",
-		"Actas Probatorias",
-		"Facturas Físicas",
-		"Representantes Legales",
-		"Establecimientos Anexos",
-	}
-
 	hayPaginacion := false
-	for _, seccion := range secciones {
+	for _, sec := range secciones.Todas() {
+		seccion := sec.Nombre
 		if tienePag, existe := rucCompleto.DeteccionPaginacion[seccion]; existe {
 			status := "❌ No"
-			if tienePag {
+			if pag, recorrida := rucCompleto.Paginacion[seccion]; tienePag && recorrida && pag.Verificado {
+				// Todas las páginas se recorrieron y cuadran con el contador de SUNAT
+				status = fmt.Sprintf("🔁 Recorrida (%d páginas, %d de %d filas)", pag.Paginas, pag.Filas, pag.TotalReportado)
+			} else if tienePag {
+				// main.sh marca como 'revision' las secciones con "✅ Sí"
 				status = "✅ Sí"
 				hayPaginacion = true
 			}
@@ -135,7 +230,7 @@ func showPaginationSummary(rucCompleto *models.RUCCompleto) {
 	}
 
 	if !hayPaginacion {
-		fmt.Println("   ℹ️  No se detectó paginación en ninguna sección")
+		fmt.Println("   ℹ️  No hay secciones con paginación sin recorrer")
 	}
 }
 
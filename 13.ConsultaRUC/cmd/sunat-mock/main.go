@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/consulta-ruc-scraper/pkg/sunatmock"
+)
+
+// Servidor local que imita la consulta RUC de SUNAT con páginas guardadas.
+//
+//	go run ./cmd/sunat-mock -addr :8089 -fixtures pkg/parser/testdata
+//	SUNAT_BASE_URL=http://localhost:8089/cl-ti-itmrconsruc/FrameCriterioBusquedaWeb.jsp go run ./cmd/scraper-completo 20606316977
+func main() {
+	addr := flag.String("addr", ":8089", "dirección de escucha")
+	dir := flag.String("fixtures", "pkg/parser/testdata", "directorio con un subdirectorio de páginas por RUC")
+	flag.Parse()
+
+	fixtures, err := sunatmock.CargarFixtures(*dir)
+	if err != nil {
+		log.Fatal("Error cargando fixtures:", err)
+	}
+
+	for _, f := range fixtures {
+		log.Printf("📄 RUC %s (%d secciones)", f.RUC, len(f.Paginas))
+	}
+
+	log.Printf("🚀 Mock SUNAT escuchando en %s%s", *addr, sunatmock.RutaFormulario)
+	log.Fatal(http.ListenAndServe(*addr, sunatmock.New(fixtures...)))
+}
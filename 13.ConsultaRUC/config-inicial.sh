@@ -12,7 +12,6 @@ chmod +x scripts/*.sh
 # Llamar a cada script
 ./scripts/eliminar_tablas.sh
 ./scripts/crear_tablas.sh
-./scripts/run-schema.sh
 ./scripts/datos-iniciales.sh
 
 echo "=== Todos los scripts se ejecutaron correctamente ==="
\ No newline at end of file
@@ -26,6 +26,12 @@ PAUSE_BETWEEN_BATCHES=10  # 10 segundos entre lotes (aumentado para limpieza)
 MAX_REINTENTOS=2          # Máximo 2 intentos por RUC
 BATCH_SIZE=100            # FIJO: 100 RUCs por lote
 DEEP_CLEAN_ENABLED=true   # Habilitar limpieza profunda
+CIIU_FILTRO="${CIIU_FILTRO:-}" # Sección o división CIIU Rev.4 a consultar (ej: 69); vacío = texto "contabilidad"
+
+# Filtro de actividad económica sobre empresas_sunat (ver init_actividad_filter)
+ACTIVIDAD_FILTER="(es.actividad_economica_ciiu_rev3_principal ILIKE '%contabilidad%' 
+                    OR es.actividad_economica_ciiu_rev3_secundaria ILIKE '%contabilidad%' 
+                    OR es.actividad_economica_ciiu_rev4_principal ILIKE '%contabilidad%')"
 
 # Contadores globales (con archivos para sincronización)
 STATS_FILE="/tmp/scraper_stats_$$"
@@ -351,6 +357,29 @@ init_go_dependencies() {
     print_success "Dependencias de Go inicializadas correctamente"
 }
 
+# Reemplazar el filtro de texto por los códigos CIIU de CIIU_FILTRO (catálogo pkg/ciiu)
+init_actividad_filter() {
+    [ -z "$CIIU_FILTRO" ] && return 0
+
+    local filtro
+    filtro=$(cd "$GO_PROJECT_DIR/../.." && go run ./cmd/ciiu filtro-sql "$CIIU_FILTRO") || {
+        print_error "CIIU_FILTRO inválido: $CIIU_FILTRO"
+        exit 1
+    }
+    ACTIVIDAD_FILTER="$filtro"
+    print_success "Filtro de actividad: CIIU Rev.4 $CIIU_FILTRO (y clases Rev.3 equivalentes)"
+}
+
+# Aplicar las migraciones pendientes; scraper-completo no arranca si el esquema no está al día
+init_schema() {
+    print_info "Verificando esquema de la base de datos..."
+
+    (cd "$GO_PROJECT_DIR/../.." && DATABASE_URL="$DATABASE_URL" go run ./cmd/migrate up) || {
+        print_error "Error aplicando las migraciones (go run ./cmd/migrate status)"
+        exit 1
+    }
+}
+
 # Obtener exactamente BATCH_SIZE RUCs no exitosos
 get_ruc_batch() {
     local batch_size="$1"
@@ -369,10 +398,9 @@ get_ruc_batch() {
         query="SELECT es.ruc 
                FROM empresas_sunat es
                LEFT JOIN log_consultas lc ON es.ruc::text = lc.ruc
-               WHERE (lc.estado IS NULL OR lc.estado NOT IN ('exitoso', 'revision'))
-               AND (es.actividad_economica_ciiu_rev3_principal ILIKE '%contabilidad%' 
-                    OR es.actividad_economica_ciiu_rev3_secundaria ILIKE '%contabilidad%' 
-                    OR es.actividad_economica_ciiu_rev4_principal ILIKE '%contabilidad%')
+               WHERE (lc.estado IS NULL OR lc.estado NOT IN ('exitoso', 'revision', 'invalido'))
+               AND NOT EXISTS (SELECT 1 FROM ruc_inexistentes ri WHERE ri.ruc = es.ruc::text)
+               AND $ACTIVIDAD_FILTER
                ORDER BY es.ruc DESC LIMIT $batch_size;"
     else
         # query="SELECT es.ruc 
@@ -387,11 +415,10 @@ get_ruc_batch() {
         query="SELECT es.ruc 
                FROM empresas_sunat es
                LEFT JOIN log_consultas lc ON es.ruc::text = lc.ruc
-               WHERE (lc.estado IS NULL OR lc.estado NOT IN ('exitoso', 'revision'))
+               WHERE (lc.estado IS NULL OR lc.estado NOT IN ('exitoso', 'revision', 'invalido'))
+               AND NOT EXISTS (SELECT 1 FROM ruc_inexistentes ri WHERE ri.ruc = es.ruc::text)
                AND es.ruc < '$LAST_RUC_PROCESSED'
-               AND (es.actividad_economica_ciiu_rev3_principal ILIKE '%contabilidad%' 
-                    OR es.actividad_economica_ciiu_rev3_secundaria ILIKE '%contabilidad%' 
-                    OR es.actividad_economica_ciiu_rev4_principal ILIKE '%contabilidad%')
+               AND $ACTIVIDAD_FILTER
                ORDER BY es.ruc DESC LIMIT $batch_size;"
     fi
     
@@ -797,6 +824,22 @@ worker_function() {
                 return 0
             fi
             ;;
+        4)
+            # NÚMERO INVÁLIDO (dígito verificador o prefijo) - no se abrió el navegador
+            print_worker "$worker_id" "INVÁLIDO: RUC $ruc"
+            mark_invalido_with_terminal "$ruc" "$terminal_completo"
+            unmark_ruc_processing "$ruc"
+            update_stats "error"
+            return 0
+            ;;
+        3)
+            # RUC NO REGISTRADO EN SUNAT - el programa ya lo guardó en ruc_inexistentes
+            print_worker "$worker_id" "INEXISTENTE: RUC $ruc"
+            mark_inexistente_with_terminal "$ruc" "$terminal_completo"
+            unmark_ruc_processing "$ruc"
+            update_stats "success"
+            return 0
+            ;;
         124)
             print_worker "$worker_id" "TIMEOUT: RUC $ruc (${TIMEOUT_SCRAPER}s)"
             
@@ -990,6 +1033,22 @@ worker_function() {
                 return 0
             fi
             ;;
+        4)
+            # NÚMERO INVÁLIDO (dígito verificador o prefijo) - no se abrió el navegador
+            print_worker "$worker_id" "INVÁLIDO: RUC $ruc"
+            mark_invalido_with_terminal "$ruc" "$terminal_completo"
+            unmark_ruc_processing "$ruc"
+            update_stats "error"
+            return 0
+            ;;
+        3)
+            # RUC NO REGISTRADO EN SUNAT - el programa ya lo guardó en ruc_inexistentes
+            print_worker "$worker_id" "INEXISTENTE: RUC $ruc"
+            mark_inexistente_with_terminal "$ruc" "$terminal_completo"
+            unmark_ruc_processing "$ruc"
+            update_stats "success"
+            return 0
+            ;;
         124)
             print_worker "$worker_id" "TIMEOUT: RUC $ruc (${TIMEOUT_SCRAPER}s)"
             
@@ -1075,6 +1134,32 @@ mark_timeout_with_terminal() {
     PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -c "$query" >/dev/null 2>&1
 }
 
+# Nueva función para marcar RUC inexistente con terminal completo
+mark_inexistente_with_terminal() {
+    local ruc="$1"
+    local terminal_completo="$2"
+    
+    local terminal_escaped=""
+    [ -n "$terminal_completo" ] && terminal_escaped=$(escape_sql "$terminal_completo")
+    
+    local query="UPDATE log_consultas SET estado = 'inexistente', mensaje = 'RUC no registrado en SUNAT', especificacion = '$terminal_escaped', fecha_registro = CURRENT_TIMESTAMP WHERE ruc = '$ruc';"
+    
+    PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -c "$query" >/dev/null 2>&1
+}
+
+# Nueva función para marcar RUC inválido con terminal completo
+mark_invalido_with_terminal() {
+    local ruc="$1"
+    local terminal_completo="$2"
+    
+    local terminal_escaped=""
+    [ -n "$terminal_completo" ] && terminal_escaped=$(escape_sql "$terminal_completo")
+    
+    local query="UPDATE log_consultas SET estado = 'invalido', mensaje = 'RUC inválido (dígito verificador o prefijo)', especificacion = '$terminal_escaped', fecha_registro = CURRENT_TIMESTAMP WHERE ruc = '$ruc';"
+    
+    PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -c "$query" >/dev/null 2>&1
+}
+
 # Nueva función para marcar falla con terminal completo
 mark_failed_with_terminal() {
     local ruc="$1"
@@ -1244,13 +1329,17 @@ process_batch_parallel() {
     local total_fallido=$(PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -t -c "SELECT COUNT(*) FROM log_consultas WHERE estado = 'fallido';" 2>/dev/null | tr -d ' \t\n\r' || echo "0")
     local total_procesando=$(PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -t -c "SELECT COUNT(*) FROM log_consultas WHERE estado = 'procesando';" 2>/dev/null | tr -d ' \t\n\r' || echo "0")
     local total_error_terminal=$(PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -t -c "SELECT COUNT(*) FROM log_consultas WHERE estado = 'error_terminal';" 2>/dev/null | tr -d ' \t\n\r' || echo "0")
-    local total_general=$((total_exitoso + total_revision + total_fallido + total_procesando + total_error_terminal))
+    local total_inexistente=$(PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -t -c "SELECT COUNT(*) FROM log_consultas WHERE estado = 'inexistente';" 2>/dev/null | tr -d ' \t\n\r' || echo "0")
+    local total_invalido=$(PGPASSWORD="$DB_PASSWORD" psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" -t -c "SELECT COUNT(*) FROM log_consultas WHERE estado = 'invalido';" 2>/dev/null | tr -d ' \t\n\r' || echo "0")
+    local total_general=$((total_exitoso + total_revision + total_fallido + total_procesando + total_error_terminal + total_inexistente + total_invalido))
 
     print_success "✅ EXITOSOS: $total_exitoso"
     print_info "🔄 REVISIÓN (paginación): $total_revision" 
     print_error "❌ FALLIDOS: $total_fallido"
     print_warning "⏳ PROCESANDO: $total_procesando"
     print_error "💀 ERROR TERMINAL: $total_error_terminal"
+    print_info "🚫 INEXISTENTES: $total_inexistente"
+    print_warning "🔢 INVÁLIDOS: $total_invalido"
     print_info "📊 TOTAL PROCESADOS: $total_general"
 
     # Calcular porcentajes
@@ -1584,6 +1673,8 @@ echo "SCRAPER PARALELO - CON LIMPIEZA PROFUNDA DE CACHÉ"
 echo "======================================================================"
 
 check_dependencies
+init_schema
+init_actividad_filter
 
 print_info "=== CONFIGURACIÓN ==="
 print_info "Workers máximos: $MAX_PARALLEL_JOBS"
@@ -0,0 +1,318 @@
+// Package ciiu contiene el catálogo de la Clasificación Industrial
+// Internacional Uniforme (CIIU) en sus revisiones 3 y 4, con la jerarquía
+// sección → división → clase y la tabla de correspondencias Rev.3 → Rev.4.
+//
+// Los datos van embebidos en datos/*.tsv:
+//
+//	rev3.tsv, rev4.tsv  código<TAB>descripción, en orden: cada sección (letra)
+//	                    precede a sus divisiones (2 dígitos) y clases (4 dígitos)
+//	rev3_rev4.tsv       clase Rev.3<TAB>clases Rev.4 separadas por espacio;
+//	                    la primera es la correspondencia principal
+package ciiu
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed datos/*.tsv
+var datos embed.FS
+
+// Revision es la revisión de la CIIU
+type Revision int
+
+const (
+	Rev3 Revision = 3
+	Rev4 Revision = 4
+)
+
+func (r Revision) String() string {
+	return fmt.Sprintf("Rev.%d", int(r))
+}
+
+// Nivel es la posición del código en la jerarquía
+type Nivel int
+
+const (
+	NivelSeccion  Nivel = iota + 1 // letra, p. ej. "M"
+	NivelDivision                  // 2 dígitos, p. ej. "69"
+	NivelClase                     // 4 dígitos, p. ej. "6920"
+)
+
+var (
+	// ErrCodigoDesconocido indica un código que no está en el catálogo
+	ErrCodigoDesconocido = errors.New("código CIIU desconocido")
+	// ErrRevision indica una revisión distinta de 3 o 4
+	ErrRevision = errors.New("revisión CIIU no soportada")
+)
+
+// Codigo es una entrada del catálogo
+type Codigo struct {
+	Revision    Revision
+	Codigo      string
+	Descripcion string
+	Nivel       Nivel
+	Seccion     string // letra de la sección a la que pertenece
+	Division    string // división a la que pertenece; vacío en las secciones
+}
+
+// String retorna "6920 - ACTIVIDADES DE CONTABILIDAD, ..."
+func (c Codigo) String() string {
+	return c.Codigo + " - " + c.Descripcion
+}
+
+type catalogo struct {
+	codigos map[string]Codigo
+	orden   []string // códigos en el orden del archivo
+}
+
+var (
+	cargarUnaVez sync.Once
+	catalogos    map[Revision]*catalogo
+	rev3aRev4    map[string][]string
+	rev4aRev3    map[string][]string
+)
+
+// cargar lee los catálogos embebidos la primera vez que se usan. Un archivo mal
+// formado es un error de programación, por eso termina en panic (como regexp.MustCompile).
+func cargar() {
+	cargarUnaVez.Do(func() {
+		catalogos = map[Revision]*catalogo{}
+		for _, rev := range []Revision{Rev3, Rev4} {
+			c, err := leerCatalogo(rev)
+			if err != nil {
+				panic(err)
+			}
+			catalogos[rev] = c
+		}
+		var err error
+		if rev3aRev4, rev4aRev3, err = leerCorrespondencias(); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func leerCatalogo(rev Revision) (*catalogo, error) {
+	nombre := fmt.Sprintf("datos/rev%d.tsv", int(rev))
+	lineas, err := leerTSV(nombre)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &catalogo{codigos: map[string]Codigo{}}
+	var seccion string
+	for i, campos := range lineas {
+		codigo := Codigo{Revision: rev, Codigo: campos[0], Descripcion: campos[1]}
+		switch {
+		case len(codigo.Codigo) == 1 && codigo.Codigo >= "A" && codigo.Codigo <= "Z":
+			codigo.Nivel = NivelSeccion
+			seccion = codigo.Codigo
+		case len(codigo.Codigo) == 2 && soloDigitos(codigo.Codigo):
+			codigo.Nivel = NivelDivision
+			codigo.Division = codigo.Codigo
+		case len(codigo.Codigo) == 4 && soloDigitos(codigo.Codigo):
+			codigo.Nivel = NivelClase
+			codigo.Division = codigo.Codigo[:2]
+			if _, ok := c.codigos[codigo.Division]; !ok {
+				return nil, fmt.Errorf("%s:%d: la clase %s no tiene división", nombre, i+1, codigo.Codigo)
+			}
+		default:
+			return nil, fmt.Errorf("%s:%d: código mal formado %q", nombre, i+1, codigo.Codigo)
+		}
+		if seccion == "" {
+			return nil, fmt.Errorf("%s:%d: %s aparece antes de la primera sección", nombre, i+1, codigo.Codigo)
+		}
+		if _, repetido := c.codigos[codigo.Codigo]; repetido {
+			return nil, fmt.Errorf("%s:%d: código repetido %s", nombre, i+1, codigo.Codigo)
+		}
+		codigo.Seccion = seccion
+		c.codigos[codigo.Codigo] = codigo
+		c.orden = append(c.orden, codigo.Codigo)
+	}
+	return c, nil
+}
+
+func leerCorrespondencias() (map[string][]string, map[string][]string, error) {
+	const nombre = "datos/rev3_rev4.tsv"
+	lineas, err := leerTSV(nombre)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	directa := map[string][]string{}
+	inversa := map[string][]string{}
+	for i, campos := range lineas {
+		origen := campos[0]
+		if c, ok := catalogos[Rev3].codigos[origen]; !ok || c.Nivel != NivelClase {
+			return nil, nil, fmt.Errorf("%s:%d: %s no es una clase Rev.3", nombre, i+1, origen)
+		}
+		for _, destino := range strings.Fields(campos[1]) {
+			if c, ok := catalogos[Rev4].codigos[destino]; !ok || c.Nivel != NivelClase {
+				return nil, nil, fmt.Errorf("%s:%d: %s no es una clase Rev.4", nombre, i+1, destino)
+			}
+			directa[origen] = append(directa[origen], destino)
+			inversa[destino] = append(inversa[destino], origen)
+		}
+	}
+	return directa, inversa, nil
+}
+
+func leerTSV(nombre string) ([][2]string, error) {
+	f, err := datos.Open(nombre)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lineas [][2]string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		linea := strings.TrimSpace(scanner.Text())
+		if linea == "" {
+			continue
+		}
+		codigo, resto, ok := strings.Cut(linea, "\t")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: falta el tabulador", nombre, n)
+		}
+		lineas = append(lineas, [2]string{strings.TrimSpace(codigo), strings.TrimSpace(resto)})
+	}
+	return lineas, scanner.Err()
+}
+
+func obtenerCatalogo(rev Revision) (*catalogo, error) {
+	cargar()
+	c, ok := catalogos[rev]
+	if !ok {
+		return nil, fmt.Errorf("%d: %w", int(rev), ErrRevision)
+	}
+	return c, nil
+}
+
+// Normalizar lleva un código a la forma del catálogo: letras en mayúscula y,
+// en la Rev.3, los códigos de 5 dígitos que usa SUNAT (p. ej. "74130") a su
+// clase de 4 dígitos
+func Normalizar(rev Revision, codigo string) string {
+	codigo = strings.ToUpper(strings.TrimSpace(codigo))
+	if rev == Rev3 && len(codigo) == 5 && soloDigitos(codigo) {
+		return codigo[:4]
+	}
+	return codigo
+}
+
+// Obtener busca una sección, división o clase
+func Obtener(rev Revision, codigo string) (Codigo, error) {
+	c, err := obtenerCatalogo(rev)
+	if err != nil {
+		return Codigo{}, err
+	}
+	v, ok := c.codigos[Normalizar(rev, codigo)]
+	if !ok {
+		return Codigo{}, fmt.Errorf("%s %q: %w", rev, codigo, ErrCodigoDesconocido)
+	}
+	return v, nil
+}
+
+// Clases retorna las clases de una sección ("M") o división ("69"), en orden de código
+func Clases(rev Revision, seccionODivision string) ([]Codigo, error) {
+	padre, err := Obtener(rev, seccionODivision)
+	if err != nil {
+		return nil, err
+	}
+	if padre.Nivel == NivelClase {
+		return []Codigo{padre}, nil
+	}
+
+	c := catalogos[rev]
+	var clases []Codigo
+	for _, codigo := range c.orden {
+		v := c.codigos[codigo]
+		if v.Nivel != NivelClase {
+			continue
+		}
+		if (padre.Nivel == NivelSeccion && v.Seccion == padre.Codigo) || v.Division == padre.Codigo {
+			clases = append(clases, v)
+		}
+	}
+	return clases, nil
+}
+
+// Buscar retorna los códigos cuya descripción contiene todas las palabras de
+// texto, sin distinguir mayúsculas ni tildes
+func Buscar(rev Revision, texto string) ([]Codigo, error) {
+	c, err := obtenerCatalogo(rev)
+	if err != nil {
+		return nil, err
+	}
+	palabras := strings.Fields(simplificar(texto))
+	if len(palabras) == 0 {
+		return nil, nil
+	}
+
+	var resultado []Codigo
+	for _, codigo := range c.orden {
+		v := c.codigos[codigo]
+		descripcion := simplificar(v.Descripcion)
+		coincide := true
+		for _, p := range palabras {
+			if !strings.Contains(descripcion, p) {
+				coincide = false
+				break
+			}
+		}
+		if coincide {
+			resultado = append(resultado, v)
+		}
+	}
+	return resultado, nil
+}
+
+// Rev3ARev4 retorna las clases Rev.4 que corresponden a una clase Rev.3. La
+// primera es la correspondencia principal.
+func Rev3ARev4(codigo string) ([]Codigo, error) {
+	origen, err := Obtener(Rev3, codigo)
+	if err != nil {
+		return nil, err
+	}
+	return codigosDe(Rev4, rev3aRev4[origen.Codigo]), nil
+}
+
+// Rev4ARev3 retorna las clases Rev.3 cuyas actividades pasaron a una clase Rev.4
+func Rev4ARev3(codigo string) ([]Codigo, error) {
+	destino, err := Obtener(Rev4, codigo)
+	if err != nil {
+		return nil, err
+	}
+	origenes := append([]string(nil), rev4aRev3[destino.Codigo]...)
+	sort.Strings(origenes)
+	return codigosDe(Rev3, origenes), nil
+}
+
+func codigosDe(rev Revision, codigos []string) []Codigo {
+	resultado := make([]Codigo, 0, len(codigos))
+	for _, codigo := range codigos {
+		resultado = append(resultado, catalogos[rev].codigos[codigo])
+	}
+	return resultado
+}
+
+var sinTildes = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u")
+
+// simplificar pasa a minúsculas y quita las tildes ("Jurídicas" → "juridicas")
+func simplificar(s string) string {
+	return sinTildes.Replace(strings.ToLower(s))
+}
+
+func soloDigitos(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package ciiu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCatalogoCompleto(t *testing.T) {
+	// Cantidad de secciones, divisiones y clases publicadas de cada revisión
+	esperado := map[Revision][3]int{Rev3: {17, 60, 292}, Rev4: {21, 88, 419}}
+	for rev, cantidades := range esperado {
+		c, err := obtenerCatalogo(rev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var obtenido [3]int
+		for _, v := range c.codigos {
+			obtenido[v.Nivel-1]++
+		}
+		if obtenido != cantidades {
+			t.Errorf("%s: %v secciones/divisiones/clases, se esperaba %v", rev, obtenido, cantidades)
+		}
+	}
+
+	// Toda clase Rev.3 tiene al menos una correspondencia Rev.4
+	for codigo, v := range catalogos[Rev3].codigos {
+		if v.Nivel == NivelClase && len(rev3aRev4[codigo]) == 0 {
+			t.Errorf("la clase Rev.3 %s no tiene correspondencia", codigo)
+		}
+	}
+}
+
+func TestObtener(t *testing.T) {
+	c, err := Obtener(Rev4, "6920")
+	if err != nil || c.Seccion != "M" || c.Division != "69" || c.Nivel != NivelClase {
+		t.Fatalf("Obtener(Rev4, 6920) = %+v, %v", c, err)
+	}
+
+	// SUNAT escribe la Rev.3 con 5 dígitos
+	if c, err := Obtener(Rev3, "74120"); err != nil || c.Codigo != "7412" || c.Seccion != "K" {
+		t.Errorf("Obtener(Rev3, 74120) = %+v, %v", c, err)
+	}
+	if c, err := Obtener(Rev4, "m"); err != nil || c.Nivel != NivelSeccion {
+		t.Errorf("Obtener(Rev4, m) = %+v, %v", c, err)
+	}
+
+	if _, err := Obtener(Rev4, "6999"); !errors.Is(err, ErrCodigoDesconocido) {
+		t.Errorf("Obtener(Rev4, 6999): %v", err)
+	}
+	if _, err := Obtener(Revision(2), "6920"); !errors.Is(err, ErrRevision) {
+		t.Errorf("Obtener(Rev2): %v", err)
+	}
+}
+
+func TestClasesYBuscar(t *testing.T) {
+	clases, err := Clases(Rev4, "69")
+	if err != nil || len(clases) != 2 || clases[0].Codigo != "6910" || clases[1].Codigo != "6920" {
+		t.Fatalf("Clases(Rev4, 69) = %v, %v", clases, err)
+	}
+	if clases, _ := Clases(Rev4, "M"); len(clases) != 14 {
+		t.Errorf("Clases(Rev4, M) = %d clases, se esperaban 14", len(clases))
+	}
+
+	encontrados, err := Buscar(Rev4, "Contabilidad teneduria")
+	if err != nil || len(encontrados) != 1 || encontrados[0].Codigo != "6920" {
+		t.Errorf("Buscar = %v, %v", encontrados, err)
+	}
+}
+
+func TestCorrespondencias(t *testing.T) {
+	rev4, err := Rev3ARev4("74120")
+	if err != nil || len(rev4) != 1 || rev4[0].Codigo != "6920" {
+		t.Fatalf("Rev3ARev4(74120) = %v, %v", rev4, err)
+	}
+
+	rev3, err := Rev4ARev3("8411")
+	if err != nil || len(rev3) != 2 || rev3[0].Codigo != "7511" || rev3[1].Codigo != "7514" {
+		t.Errorf("Rev4ARev3(8411) = %v, %v", rev3, err)
+	}
+}
@@ -0,0 +1,658 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/diff"
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/secciones"
+)
+
+var (
+	// ErrRUCNoEncontrado indica que el RUC no tiene consultas guardadas (hasta la fecha pedida)
+	ErrRUCNoEncontrado = errors.New("RUC no encontrado en la base de datos")
+	// ErrConsultaNoEncontrada indica un id de ruc_consultas que no existe
+	ErrConsultaNoEncontrada = errors.New("consulta no encontrada en la base de datos")
+)
+
+// GetRUCByNumber reconstruye el RUC completo de su última consulta, tal como
+// lo escribió InsertRUCCompleto, con dos diferencias: los textos que
+// nullString guarda como NULL ("-", "No hay información") vuelven vacíos y un
+// monto sin moneda vuelve en soles. Adicionales vuelve de las secciones con
+// Lector. Retorna ErrRUCNoEncontrado si el RUC no está guardado.
+func (ds *DatabaseService) GetRUCByNumber(rucNumber string) (*models.RUCCompleto, error) {
+	return ds.leerRUC(rucNumber, "9999-12-31")
+}
+
+// GetRUCEnFecha reconstruye lo que SUNAT mostraba del RUC en la fecha dada:
+// la última consulta hecha hasta ese día inclusive. Retorna ErrRUCNoEncontrado
+// si el RUC no se había consultado hasta entonces.
+func (ds *DatabaseService) GetRUCEnFecha(rucNumber string, fecha time.Time) (*models.RUCCompleto, error) {
+	return ds.leerRUC(rucNumber, fecha.Format(time.DateOnly))
+}
+
+// GetRUCPorConsulta reconstruye el RUC de una consulta de ruc_consultas
+func (ds *DatabaseService) GetRUCPorConsulta(consultaID int64) (*models.RUCCompleto, error) {
+	tx, err := ds.iniciarLectura()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ruc, err := ds.leerConsulta(tx, consultaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%d: %w", consultaID, ErrConsultaNoEncontrada)
+	}
+	return ruc, err
+}
+
+// CambioRUC es un cambio guardado en ruc_cambios, con las consultas comparadas
+type CambioRUC struct {
+	diff.Cambio
+	ConsultaAnteriorID int64     `json:"consulta_anterior_id"`
+	ConsultaID         int64     `json:"consulta_id"`
+	FechaConsulta      time.Time `json:"fecha_consulta"`
+}
+
+// GetCambios retorna los cambios del RUC detectados en consultas hechas desde
+// la fecha dada, del más antiguo al más reciente
+func (ds *DatabaseService) GetCambios(rucNumber string, desde time.Time) ([]CambioRUC, error) {
+	var cambios []CambioRUC
+	filas, err := ds.db.Query(`
+		SELECT rc.tipo, rc.clave, rc.anterior, rc.nuevo, rc.consulta_anterior_id, rc.consulta_id, c.fecha_consulta
+		FROM ruc_cambios rc
+		JOIN ruc_consultas c ON c.id = rc.consulta_id
+		JOIN ruc_informacion_basica ib ON ib.id = rc.ruc_id
+		WHERE ib.ruc = $1 AND c.fecha_consulta >= $2
+		ORDER BY c.fecha_consulta, rc.id`,
+		rucNumber, desde)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cambios: %w", err)
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		var c CambioRUC
+		if err := filas.Scan((*textoNulo)(&c.Tipo), texto(&c.Clave), texto(&c.Anterior), texto(&c.Nuevo),
+			&c.ConsultaAnteriorID, &c.ConsultaID, &c.FechaConsulta); err != nil {
+			return nil, fmt.Errorf("error reading cambios: %w", err)
+		}
+		cambios = append(cambios, c)
+	}
+	if err := filas.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cambios: %w", err)
+	}
+	return cambios, nil
+}
+
+func (ds *DatabaseService) leerRUC(rucNumber, hasta string) (*models.RUCCompleto, error) {
+	tx, err := ds.iniciarLectura()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var consultaID int64
+	err = tx.QueryRow(`
+		SELECT c.id
+		FROM ruc_consultas c
+		JOIN ruc_informacion_basica ib ON ib.id = c.ruc_id
+		WHERE ib.ruc = $1 AND c.fecha_consulta < $2::date + 1
+		ORDER BY c.fecha_consulta DESC, c.id DESC
+		LIMIT 1`,
+		rucNumber, hasta).Scan(&consultaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%q: %w", rucNumber, ErrRUCNoEncontrado)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading consulta: %w", err)
+	}
+	return ds.leerConsulta(tx, consultaID)
+}
+
+// iniciarLectura abre una transacción de solo lectura para que las tablas se
+// lean consistentes aunque otro proceso esté guardando el mismo RUC
+func (ds *DatabaseService) iniciarLectura() (*sql.Tx, error) {
+	tx, err := ds.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// leerConsulta reconstruye la foto guardada por InsertRUCCompleto en la consulta consultaID
+func (ds *DatabaseService) leerConsulta(tx *sql.Tx, consultaID int64) (*models.RUCCompleto, error) {
+	ruc := &models.RUCCompleto{}
+
+	// 1. Consulta y RUC; la ficha puede estar guardada por una consulta anterior
+	var fichaID int64
+	err := tx.QueryRow(`
+		SELECT ib.ruc, c.fecha_consulta, c.version_api, c.ficha_consulta_id
+		FROM ruc_consultas c
+		JOIN ruc_informacion_basica ib ON ib.id = c.ruc_id
+		WHERE c.id = $1`,
+		consultaID).Scan(&ruc.InformacionBasica.RUC, &ruc.FechaConsulta, texto(&ruc.VersionAPI), &fichaID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Ficha
+	if err := ds.leerFicha(tx, fichaID, &ruc.InformacionBasica); err != nil {
+		return nil, fmt.Errorf("error reading ficha: %w", err)
+	}
+
+	// 3. Actividades económicas
+	if ruc.InformacionBasica.ActividadesEconomicas, err = ds.leerActividadesEconomicas(tx, fichaID); err != nil {
+		return nil, fmt.Errorf("error reading actividades economicas: %w", err)
+	}
+
+	// 4-7. Comprobantes, sistemas de emisión electrónica y padrones
+	listas := []struct {
+		nombre, tabla, columna string
+		destino                *[]string
+	}{
+		{"comprobantes pago", "ruc_comprobantes_pago", "comprobante_pago", &ruc.InformacionBasica.ComprobantesPago},
+		{"sistemas emision electronica", "ruc_sistemas_emision_electronica", "sistema_emision", &ruc.InformacionBasica.SistemaEmisionElectronica},
+		{"comprobantes electronicos", "ruc_comprobantes_electronicos", "comprobante_electronico", &ruc.InformacionBasica.ComprobantesElectronicos},
+		{"padrones", "ruc_padrones", "padron", &ruc.InformacionBasica.Padrones},
+	}
+	for _, l := range listas {
+		if *l.destino, err = ds.leerTextos(tx, fichaID, l.tabla, l.columna); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", l.nombre, err)
+		}
+	}
+
+	// 8. Estado y paginación de cada sección, y la consulta que guarda sus datos
+	datos, err := ds.leerEstadoSecciones(tx, consultaID, ruc)
+	if err != nil {
+		return nil, fmt.Errorf("error reading estado secciones: %w", err)
+	}
+
+	// 9. Consultas adicionales en el orden del registro de secciones
+	for _, sec := range seccionesGuardadas() {
+		if datos[sec.Clave] == 0 {
+			continue
+		}
+		if err := sec.leer(ds, tx, datos[sec.Clave], ruc); err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", sec.nombre(), err)
+		}
+	}
+
+	return ruc, nil
+}
+
+// leerFicha lee la ficha de ruc_fichas; los destinos siguen el orden de camposFicha
+func (ds *DatabaseService) leerFicha(tx *sql.Tx, consultaID int64, info *models.RUCInfo) error {
+	detalle := &models.Domicilio{}
+	destinos := []any{
+		texto(&info.RazonSocial), texto(&info.TipoContribuyente), (*textoNulo)(&info.TipoDocumento),
+		texto(&info.NumeroDocumento), texto(&info.NombreTitular), texto(&info.NombreComercial),
+		&info.FechaInscripcion, &info.FechaInicioActividades, texto(&info.Estado), texto(&info.Condicion),
+		texto(&info.DomicilioFiscal), texto(&info.SistemaEmision), texto(&info.ActividadComercioExterior),
+		texto(&info.SistemaContabilidad), &info.EmisorElectronicoDesde, &info.AfiliadoPLE,
+	}
+	err := tx.QueryRow(`SELECT `+columnasFicha+` FROM ruc_fichas WHERE consulta_id = $1`,
+		consultaID).Scan(append(destinos, destinosDomicilio(detalle)...)...)
+	info.DomicilioFiscalDetalle = domicilioOVacio(detalle)
+	return err
+}
+
+func (ds *DatabaseService) leerActividadesEconomicas(tx *sql.Tx, consultaID int64) ([]models.ActividadEconomica, error) {
+	var actividades []models.ActividadEconomica
+	err := leerFilas(tx, func(filas *sql.Rows) error {
+		var a models.ActividadEconomica
+		var revision sql.NullInt64
+		if err := filas.Scan((*textoNulo)(&a.Tipo), &a.Orden, texto(&a.CodigoCIIU), &revision,
+			texto(&a.Descripcion), texto(&a.SeccionCIIU), texto(&a.DivisionCIIU)); err != nil {
+			return err
+		}
+		a.RevisionCIIU = int(revision.Int64)
+		actividades = append(actividades, a)
+		return nil
+	}, `
+		SELECT tipo, orden, codigo_ciiu, revision_ciiu, descripcion, seccion_ciiu, division_ciiu
+		FROM ruc_actividades_economicas WHERE consulta_id = $1 ORDER BY id`, consultaID)
+	return actividades, err
+}
+
+// leerTextos lee las listas de texto de la ficha (comprobantes, padrones, ...) en el orden en que se guardaron
+func (ds *DatabaseService) leerTextos(tx *sql.Tx, consultaID int64, tabla, columna string) ([]string, error) {
+	var textos []string
+	err := leerFilas(tx, func(filas *sql.Rows) error {
+		var t string
+		if err := filas.Scan(&t); err != nil {
+			return err
+		}
+		textos = append(textos, t)
+		return nil
+	}, `SELECT `+columna+` FROM `+tabla+` WHERE consulta_id = $1 ORDER BY id`, consultaID)
+	return textos, err
+}
+
+// leerEstadoSecciones asigna en ruc el estado y la paginación de cada sección
+// y retorna, de las que guardaron datos, la consulta que los tiene
+func (ds *DatabaseService) leerEstadoSecciones(tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (map[string]int64, error) {
+	datos := make(map[string]int64)
+	err := leerFilas(tx, func(filas *sql.Rows) error {
+		var seccion, estado string
+		var datosID, paginas, filasPag, total sql.NullInt64
+		var detectada, verificado sql.NullBool
+		if err := filas.Scan(&seccion, &estado, &datosID, &detectada, &paginas, &filasPag, &total, &verificado); err != nil {
+			return err
+		}
+		if ruc.EstadoSecciones == nil {
+			ruc.EstadoSecciones = make(map[string]models.EstadoSeccion)
+		}
+		ruc.EstadoSecciones[seccion] = models.EstadoSeccion(estado)
+		if datosID.Valid {
+			datos[seccion] = datosID.Int64
+		}
+
+		// La paginación se guarda por clave y RUCCompleto la tiene por Nombre
+		sec, ok := secciones.Buscar(seccion)
+		if !ok {
+			return nil
+		}
+		if detectada.Valid {
+			if ruc.DeteccionPaginacion == nil {
+				ruc.DeteccionPaginacion = make(map[string]bool)
+			}
+			ruc.DeteccionPaginacion[sec.Nombre] = detectada.Bool
+		}
+		if paginas.Valid {
+			if ruc.Paginacion == nil {
+				ruc.Paginacion = make(map[string]models.PaginacionSeccion)
+			}
+			ruc.Paginacion[sec.Nombre] = models.PaginacionSeccion{Paginas: int(paginas.Int64), Filas: int(filasPag.Int64),
+				TotalReportado: int(total.Int64), Verificado: verificado.Bool}
+		}
+		return nil
+	}, `
+		SELECT seccion, estado, datos_consulta_id, paginacion_detectada, paginas, filas, total_reportado, verificado
+		FROM ruc_consulta_secciones WHERE consulta_id = $1 ORDER BY id`, consultaID)
+	return datos, err
+}
+
+func (ds *DatabaseService) leerInformacionHistorica(tx *sql.Tx, consultaID int64) (*models.InformacionHistorica, error) {
+	var histID int64
+	err := tx.QueryRow(`SELECT id FROM ruc_informacion_historica WHERE consulta_id = $1`,
+		consultaID).Scan(&histID)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	info := &models.InformacionHistorica{}
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var r models.RazonSocialHistorica
+		if err := filas.Scan(texto(&r.Nombre), &r.FechaDeBaja); err != nil {
+			return err
+		}
+		info.RazonesSociales = append(info.RazonesSociales, r)
+		return nil
+	}, `SELECT nombre, fecha_de_baja FROM ruc_razones_sociales_historicas WHERE informacion_historica_id = $1 ORDER BY id`, histID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var c models.CondicionHistorica
+		if err := filas.Scan(texto(&c.Condicion), &c.Desde, &c.Hasta); err != nil {
+			return err
+		}
+		info.Condiciones = append(info.Condiciones, c)
+		return nil
+	}, `SELECT condicion, desde, hasta FROM ruc_condiciones_historicas WHERE informacion_historica_id = $1 ORDER BY id`, histID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var d models.DomicilioFiscalHistorico
+		detalle := &models.Domicilio{}
+		if err := filas.Scan(append([]any{texto(&d.Direccion), &d.FechaDeBaja}, destinosDomicilio(detalle)...)...); err != nil {
+			return err
+		}
+		d.DireccionDetalle = domicilioOVacio(detalle)
+		info.Domicilios = append(info.Domicilios, d)
+		return nil
+	}, `SELECT direccion, fecha_de_baja, `+columnasDomicilio+`
+		FROM ruc_domicilios_fiscales_historicos WHERE informacion_historica_id = $1 ORDER BY id`, histID)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (ds *DatabaseService) leerDeudaCoactiva(tx *sql.Tx, consultaID int64) (*models.DeudaCoactiva, error) {
+	var deudaID int64
+	var monedaTotal string
+	deuda := &models.DeudaCoactiva{}
+	err := tx.QueryRow(`
+		SELECT id, total_deuda, moneda, cantidad_documentos
+		FROM ruc_deuda_coactiva WHERE consulta_id = $1`,
+		consultaID).Scan(&deudaID, &deuda.TotalDeuda, &monedaTotal, &deuda.CantidadDocumentos)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+	deuda.TotalDeuda.Moneda = models.Moneda(monedaTotal)
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var d models.DetalleDeuda
+		var moneda string
+		if err := filas.Scan(&d.Monto, &moneda, &d.PeriodoTributario, &d.FechaInicioCobranza, texto(&d.Entidad)); err != nil {
+			return err
+		}
+		d.Monto.Moneda = models.Moneda(moneda)
+		deuda.Deudas = append(deuda.Deudas, d)
+		return nil
+	}, `SELECT monto, moneda, periodo_tributario, fecha_inicio_cobranza, entidad
+		FROM ruc_detalle_deudas WHERE deuda_coactiva_id = $1 ORDER BY id`, deudaID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var f models.FilaInvalida
+		if err := filas.Scan(&f.Fila, texto(&f.Texto), texto(&f.Error)); err != nil {
+			return err
+		}
+		deuda.FilasInvalidas = append(deuda.FilasInvalidas, f)
+		return nil
+	}, `SELECT fila, texto, error FROM ruc_deuda_filas_invalidas WHERE deuda_coactiva_id = $1 ORDER BY id`, deudaID)
+	if err != nil {
+		return nil, err
+	}
+
+	return deuda, nil
+}
+
+func (ds *DatabaseService) leerOmisionesTributarias(tx *sql.Tx, consultaID int64) (*models.OmisionesTributarias, error) {
+	var omisionesID int64
+	omisiones := &models.OmisionesTributarias{}
+	err := tx.QueryRow(`
+		SELECT id, tiene_omisiones, cantidad_omisiones
+		FROM ruc_omisiones_tributarias WHERE consulta_id = $1`,
+		consultaID).Scan(&omisionesID, &omisiones.TieneOmisiones, &omisiones.CantidadOmisiones)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var o models.Omision
+		if err := filas.Scan(&o.Periodo, texto(&o.Tributo), texto(&o.TipoDeclaracion), &o.FechaVencimiento, texto(&o.Estado)); err != nil {
+			return err
+		}
+		omisiones.Omisiones = append(omisiones.Omisiones, o)
+		return nil
+	}, `SELECT periodo, tributo, tipo_declaracion, fecha_vencimiento, estado
+		FROM ruc_omisiones WHERE omisiones_tributarias_id = $1 ORDER BY id`, omisionesID)
+	if err != nil {
+		return nil, err
+	}
+
+	return omisiones, nil
+}
+
+func (ds *DatabaseService) leerCantidadTrabajadores(tx *sql.Tx, consultaID int64) (*models.CantidadTrabajadores, error) {
+	var trabajadoresID int64
+	err := tx.QueryRow(`SELECT id FROM ruc_cantidad_trabajadores WHERE consulta_id = $1`,
+		consultaID).Scan(&trabajadoresID)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	trabajadores := &models.CantidadTrabajadores{}
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var p models.Periodo
+		if err := filas.Scan(&p); err != nil {
+			return err
+		}
+		trabajadores.PeriodosDisponibles = append(trabajadores.PeriodosDisponibles, p)
+		return nil
+	}, `SELECT periodo FROM ruc_periodos_disponibles_trabajadores WHERE cantidad_trabajadores_id = $1 ORDER BY id`, trabajadoresID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var d models.DetalleTrabajadores
+		if err := filas.Scan(&d.Periodo, &d.CantidadTrabajadores, &d.CantidadPrestadoresServicio,
+			&d.CantidadPensionistas, &d.Total); err != nil {
+			return err
+		}
+		trabajadores.DetallePorPeriodo = append(trabajadores.DetallePorPeriodo, d)
+		return nil
+	}, `SELECT periodo, cantidad_trabajadores, cantidad_prestadores_servicio, cantidad_pensionistas, total
+		FROM ruc_detalle_trabajadores WHERE cantidad_trabajadores_id = $1 ORDER BY id`, trabajadoresID)
+	if err != nil {
+		return nil, err
+	}
+
+	return trabajadores, nil
+}
+
+func (ds *DatabaseService) leerActasProbatorias(tx *sql.Tx, consultaID int64) (*models.ActasProbatorias, error) {
+	var actasID int64
+	actas := &models.ActasProbatorias{}
+	err := tx.QueryRow(`
+		SELECT id, tiene_actas, cantidad_actas
+		FROM ruc_actas_probatorias WHERE consulta_id = $1`,
+		consultaID).Scan(&actasID, &actas.TieneActas, &actas.CantidadActas)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var a models.ActaProbatoria
+		if err := filas.Scan(texto(&a.NumeroActa), &a.FechaActa, texto(&a.LugarIntervencion),
+			texto(&a.ArticuloNumeral), texto(&a.DescripcionInfraccion), texto(&a.NumeroRIROZ),
+			texto(&a.TipoRIROZ), texto(&a.ActaReconocimiento)); err != nil {
+			return err
+		}
+		actas.Actas = append(actas.Actas, a)
+		return nil
+	}, `SELECT numero_acta, fecha_acta, lugar_intervencion, articulo_numeral,
+			descripcion_infraccion, numero_ri_roz, tipo_ri_roz, acta_reconocimiento
+		FROM ruc_actas WHERE actas_probatorias_id = $1 ORDER BY id`, actasID)
+	if err != nil {
+		return nil, err
+	}
+
+	return actas, nil
+}
+
+func (ds *DatabaseService) leerFacturasFisicas(tx *sql.Tx, consultaID int64) (*models.FacturasFisicas, error) {
+	var facturasID int64
+	facturas := &models.FacturasFisicas{}
+	err := tx.QueryRow(`
+		SELECT id, tiene_autorizacion
+		FROM ruc_facturas_fisicas WHERE consulta_id = $1`,
+		consultaID).Scan(&facturasID, &facturas.TieneAutorizacion)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	// Las dos tablas tienen las mismas columnas
+	const columnas = `numero_autorizacion, fecha_autorizacion, tipo_comprobante, serie, numero_inicial, numero_final`
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var f models.FacturaAutorizada
+		if err := filas.Scan(texto(&f.NumeroAutorizacion), &f.FechaAutorizacion, texto(&f.TipoComprobante),
+			texto(&f.Serie), texto(&f.NumeroInicial), texto(&f.NumeroFinal)); err != nil {
+			return err
+		}
+		facturas.Autorizaciones = append(facturas.Autorizaciones, f)
+		return nil
+	}, `SELECT `+columnas+` FROM ruc_facturas_autorizadas WHERE facturas_fisicas_id = $1 ORDER BY id`, facturasID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var f models.FacturaBajaOCancelada
+		if err := filas.Scan(texto(&f.NumeroAutorizacion), &f.FechaAutorizacion, texto(&f.TipoComprobante),
+			texto(&f.Serie), texto(&f.NumeroInicial), texto(&f.NumeroFinal)); err != nil {
+			return err
+		}
+		facturas.CanceladasOBajas = append(facturas.CanceladasOBajas, f)
+		return nil
+	}, `SELECT `+columnas+` FROM ruc_facturas_canceladas_bajas WHERE facturas_fisicas_id = $1 ORDER BY id`, facturasID)
+	if err != nil {
+		return nil, err
+	}
+
+	return facturas, nil
+}
+
+func (ds *DatabaseService) leerReactivaPeru(tx *sql.Tx, consultaID int64) (*models.ReactivaPeru, error) {
+	reactiva := &models.ReactivaPeru{}
+	err := tx.QueryRow(`
+		SELECT razon_social, tiene_deuda_coactiva, fecha_actualizacion, referencia_legal
+		FROM ruc_reactiva_peru WHERE consulta_id = $1`,
+		consultaID).Scan(texto(&reactiva.RazonSocial), &reactiva.TieneDeudaCoactiva,
+		&reactiva.FechaActualizacion, texto(&reactiva.ReferenciaLegal))
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+	return reactiva, nil
+}
+
+func (ds *DatabaseService) leerProgramaCovid19(tx *sql.Tx, consultaID int64) (*models.ProgramaCovid19, error) {
+	covid := &models.ProgramaCovid19{}
+	err := tx.QueryRow(`
+		SELECT razon_social, participa_programa, tiene_deuda_coactiva, fecha_actualizacion, base_legal
+		FROM ruc_programa_covid19 WHERE consulta_id = $1`,
+		consultaID).Scan(texto(&covid.RazonSocial), &covid.ParticipaPrograma, &covid.TieneDeudaCoactiva,
+		&covid.FechaActualizacion, texto(&covid.BaseLegal))
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+	return covid, nil
+}
+
+func (ds *DatabaseService) leerRepresentantesLegales(tx *sql.Tx, consultaID int64) (*models.RepresentantesLegales, error) {
+	var representantesID int64
+	err := tx.QueryRow(`SELECT id FROM ruc_representantes_legales WHERE consulta_id = $1`,
+		consultaID).Scan(&representantesID)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	representantes := &models.RepresentantesLegales{}
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var r models.RepresentanteLegal
+		if err := filas.Scan((*textoNulo)(&r.TipoDocumento), texto(&r.NumeroDocumento), texto(&r.NombreCompleto),
+			texto(&r.Apellidos), texto(&r.Nombres), (*textoNulo)(&r.Cargo), texto(&r.CargoSUNAT),
+			&r.FechaDesde, &r.FechaHasta, &r.Vigente); err != nil {
+			return err
+		}
+		representantes.Representantes = append(representantes.Representantes, r)
+		return nil
+	}, `SELECT tipo_documento, numero_documento, nombre_completo, apellidos, nombres,
+			cargo, cargo_sunat, fecha_desde, fecha_hasta, vigente
+		FROM ruc_representantes WHERE representantes_legales_id = $1 ORDER BY id`, representantesID)
+	if err != nil {
+		return nil, err
+	}
+
+	return representantes, nil
+}
+
+func (ds *DatabaseService) leerEstablecimientosAnexos(tx *sql.Tx, consultaID int64) (*models.EstablecimientosAnexos, error) {
+	var establecimientosID int64
+	establecimientos := &models.EstablecimientosAnexos{}
+	err := tx.QueryRow(`
+		SELECT id, cantidad_anexos
+		FROM ruc_establecimientos_anexos WHERE consulta_id = $1`,
+		consultaID).Scan(&establecimientosID, &establecimientos.CantidadAnexos)
+	if err != nil {
+		return nil, sinFilas(err)
+	}
+
+	err = leerFilas(tx, func(filas *sql.Rows) error {
+		var e models.EstablecimientoAnexo
+		detalle := &models.Domicilio{}
+		destinos := []any{texto(&e.Codigo), texto(&e.TipoEstablecimiento), texto(&e.Direccion), texto(&e.ActividadEconomica)}
+		if err := filas.Scan(append(destinos, destinosDomicilio(detalle)...)...); err != nil {
+			return err
+		}
+		e.DireccionDetalle = domicilioOVacio(detalle)
+		establecimientos.Establecimientos = append(establecimientos.Establecimientos, e)
+		return nil
+	}, `SELECT codigo, tipo_establecimiento, direccion, actividad_economica, `+columnasDomicilio+`
+		FROM ruc_establecimientos WHERE establecimientos_anexos_id = $1 ORDER BY id`, establecimientosID)
+	if err != nil {
+		return nil, err
+	}
+
+	return establecimientos, nil
+}
+
+// leerFilas ejecuta la consulta y llama a leer con cada fila
+func leerFilas(tx *sql.Tx, leer func(*sql.Rows) error, query string, args ...any) error {
+	filas, err := tx.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer filas.Close()
+
+	for filas.Next() {
+		if err := leer(filas); err != nil {
+			return err
+		}
+	}
+	return filas.Err()
+}
+
+// sinFilas convierte sql.ErrNoRows en nil: la consulta no guardó la sección
+func sinFilas(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// textoNulo lee una columna de texto que nullString pudo guardar como NULL
+type textoNulo string
+
+func (t *textoNulo) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*t = ""
+	case []byte:
+		*t = textoNulo(v)
+	case string:
+		*t = textoNulo(v)
+	default:
+		return fmt.Errorf("texto: no se puede leer %T", src)
+	}
+	return nil
+}
+
+func texto(s *string) *textoNulo {
+	return (*textoNulo)(s)
+}
+
+// destinosDomicilio retorna los destinos de Scan para camposDomicilio
+func destinosDomicilio(d *models.Domicilio) []any {
+	return []any{
+		texto(&d.TipoVia), texto(&d.NombreVia), texto(&d.Numero),
+		texto(&d.Interior), texto(&d.Manzana), texto(&d.Lote),
+		texto(&d.TipoUrbanizacion), texto(&d.Urbanizacion),
+		texto(&d.Departamento), texto(&d.Provincia), texto(&d.Distrito),
+		texto(&d.Ubigeo),
+	}
+}
+
+// domicilioOVacio retorna nil si todas las columnas eran NULL, como las guarda valoresDomicilio sin dirección
+func domicilioOVacio(d *models.Domicilio) *models.Domicilio {
+	if *d == (models.Domicilio{}) {
+		return nil
+	}
+	return d
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/secciones"
+)
+
+// GetRUCByNumber debe retornar lo que guardó InsertRUCCompleto, con los textos
+// que nullString guarda como NULL vacíos y los montos sin moneda en soles
+func TestGetRUCByNumberIdaYVuelta(t *testing.T) {
+	ds := baseDePrueba(t)
+
+	guardado := consultasDePrueba(1)[0]
+	guardado.FechaConsulta = time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond)
+	guardado.InformacionBasica.NombreComercial = "-"
+	guardado.InformacionBasica.SistemaContabilidad = "No hay información"
+	trabajadores, _ := secciones.Buscar("cantidad_trabajadores")
+	guardado.DeteccionPaginacion = map[string]bool{trabajadores.Nombre: true}
+	guardado.Paginacion = map[string]models.PaginacionSeccion{
+		trabajadores.Nombre: {Paginas: 2, Filas: 12, TotalReportado: 12, Verificado: true},
+	}
+	if err := ds.InsertRUCCompleto(guardado); err != nil {
+		t.Fatal(err)
+	}
+
+	leido, err := ds.GetRUCByNumber(guardado.InformacionBasica.RUC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esperado := *guardado
+	esperado.InformacionBasica.NombreComercial = ""
+	esperado.InformacionBasica.SistemaContabilidad = ""
+	deuda := *guardado.DeudaCoactiva
+	deuda.TotalDeuda.Moneda = models.MonedaPEN
+	esperado.DeudaCoactiva = &deuda
+
+	if !leido.FechaConsulta.Equal(esperado.FechaConsulta) {
+		t.Errorf("FechaConsulta = %v, se esperaba %v", leido.FechaConsulta, esperado.FechaConsulta)
+	}
+	leido.FechaConsulta = esperado.FechaConsulta
+
+	if !reflect.DeepEqual(leido, &esperado) {
+		l, _ := json.Marshal(leido)
+		e, _ := json.Marshal(&esperado)
+		t.Errorf("GetRUCByNumber:\n%s\nse esperaba:\n%s", l, e)
+	}
+}
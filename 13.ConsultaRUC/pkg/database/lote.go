@@ -0,0 +1,423 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/lib/pq"
+)
+
+// TamanoLote es la cantidad de RUC por transacción de InsertRUCCompletoLote
+// cuando no se indica otra
+const TamanoLote = 200
+
+// ResultadoLote es el resultado de guardar un RUC con InsertRUCCompletoLote
+type ResultadoLote struct {
+	RUC string
+	Err error // nil si se guardó
+}
+
+// InsertRUCCompletoLote guarda muchas consultas con pocas sentencias: cada lote
+// de hasta tamano RUC (TamanoLote si es 0) va en una transacción y cada tabla
+// recibe un INSERT de varias filas por lote, en lugar de uno por fila y por RUC.
+// Se guarda lo mismo que con InsertRUCCompleto, incluidos los cambios respecto
+// de la consulta anterior.
+//
+// Retorna un resultado por consulta, en el orden de rucs. Si un lote falla se
+// vuelve a guardar RUC por RUC con InsertRUCCompleto, para que un RUC con
+// problemas no arrastre a los demás y su error quede en su resultado.
+func (ds *DatabaseService) InsertRUCCompletoLote(rucs []*models.RUCCompleto, tamano int) []ResultadoLote {
+	if tamano <= 0 {
+		tamano = TamanoLote
+	}
+
+	resultados := make([]ResultadoLote, len(rucs))
+	for i, ruc := range rucs {
+		resultados[i].RUC = ruc.InformacionBasica.RUC
+	}
+
+	for _, indices := range partirLotes(rucs, tamano) {
+		lote := make([]*models.RUCCompleto, len(indices))
+		for j, i := range indices {
+			lote[j] = rucs[i]
+		}
+
+		err := ds.insertLote(lote)
+		if err == nil {
+			continue
+		}
+		if len(indices) == 1 {
+			resultados[indices[0]].Err = err
+			continue
+		}
+		for _, i := range indices {
+			resultados[i].Err = ds.InsertRUCCompleto(rucs[i])
+		}
+	}
+	return resultados
+}
+
+// partirLotes agrupa los índices de rucs en lotes de hasta tamano. Un RUC
+// repetido empieza otro lote: dos consultas del mismo RUC en una sentencia
+// tocarían dos veces su fila de ruc_informacion_basica, y sus cambios se
+// calculan contra la consulta anterior ya guardada.
+func partirLotes(rucs []*models.RUCCompleto, tamano int) [][]int {
+	var lotes [][]int
+	var lote []int
+	enLote := make(map[string]bool)
+	for i, ruc := range rucs {
+		if len(lote) == tamano || enLote[ruc.InformacionBasica.RUC] {
+			lotes = append(lotes, lote)
+			lote, enLote = nil, make(map[string]bool)
+		}
+		lote = append(lote, i)
+		enLote[ruc.InformacionBasica.RUC] = true
+	}
+	if len(lote) > 0 {
+		lotes = append(lotes, lote)
+	}
+	return lotes
+}
+
+// insertLote guarda un lote de consultas de RUC distintos en una transacción,
+// con los mismos pasos que InsertRUCCompleto pero para todo el lote a la vez
+func (ds *DatabaseService) insertLote(lote []*models.RUCCompleto) error {
+	tx, err := ds.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Insertar o actualizar los RUC con la ficha de esta consulta
+	rucIDs, conConsultas, err := ds.insertRUCsInformacionBasica(tx, lote)
+	if err != nil {
+		return fmt.Errorf("error inserting RUC info: %w", err)
+	}
+
+	// 2. Buscar consultas anteriores con la misma ficha
+	fichas := make([]any, len(lote))
+	for i, ruc := range lote {
+		fichas[i] = ruc.InformacionBasica
+	}
+	huellasFicha, fichaIDs, err := ds.buscarFotos(tx, "ruc_fichas", rucIDs, fichas)
+	if err != nil {
+		return fmt.Errorf("error reading fichas: %w", err)
+	}
+
+	// 3. Insertar las consultas con ids reservados, para enlazar lo demás sin RETURNING
+	consultaIDs, err := reservarIDs(tx, "ruc_consultas", len(lote))
+	if err != nil {
+		return fmt.Errorf("error reserving consultas: %w", err)
+	}
+	consultas := make([][]interface{}, len(lote))
+	for i, ruc := range lote {
+		fichaID := fichaIDs[i]
+		if fichaID == 0 {
+			fichaID = consultaIDs[i]
+		}
+		consultas[i] = []interface{}{consultaIDs[i], rucIDs[i], ruc.FechaConsulta, ruc.VersionAPI, fichaID}
+	}
+	err = insertFilas(tx, "ruc_consultas",
+		[]string{"id", "ruc_id", "fecha_consulta", "version_api", "ficha_consulta_id"}, consultas, "")
+	if err != nil {
+		return fmt.Errorf("error inserting consultas: %w", err)
+	}
+
+	// 4. Insertar las fichas que cambiaron con sus listas
+	var fichasNuevas [][]interface{}
+	var listas []lista
+	for i, ruc := range lote {
+		if fichaIDs[i] != 0 {
+			continue
+		}
+		fila := append([]interface{}{consultaIDs[i], rucIDs[i], huellasFicha[i]}, ds.valoresFicha(&ruc.InformacionBasica)...)
+		fichasNuevas = append(fichasNuevas, fila)
+
+		for j, l := range ds.listasFicha(rucIDs[i], consultaIDs[i], &ruc.InformacionBasica) {
+			if j == len(listas) {
+				listas = append(listas, lista{nombre: l.nombre, tabla: l.tabla, columnas: l.columnas})
+			}
+			listas[j].filas = append(listas[j].filas, l.filas...)
+		}
+	}
+	err = insertFilas(tx, "ruc_fichas", append([]string{"consulta_id", "ruc_id", "huella"}, camposFicha...), fichasNuevas, "")
+	if err != nil {
+		return fmt.Errorf("error inserting ficha: %w", err)
+	}
+	for _, l := range listas {
+		if err := insertFilas(tx, l.tabla, l.columnas, l.filas, ""); err != nil {
+			return fmt.Errorf("error inserting %s: %w", l.nombre, err)
+		}
+	}
+
+	// 5. Insertar consultas adicionales en el orden del registro de secciones
+	datos := make([]map[string]int64, len(lote))
+	for i := range datos {
+		datos[i] = make(map[string]int64)
+	}
+	for _, sec := range seccionesGuardadas() {
+		// Los escritores de las secciones propias guardan RUC por RUC
+		if sec.Escritor != nil {
+			for i, ruc := range lote {
+				datosID, err := sec.escribir(ds, tx, rucIDs[i], consultaIDs[i], ruc)
+				if err != nil {
+					return fmt.Errorf("error inserting %s: %w", sec.nombre(), err)
+				}
+				datos[i][sec.Clave] = datosID
+			}
+			continue
+		}
+
+		fotos := make([]*foto, len(lote))
+		for i, ruc := range lote {
+			fotos[i] = sec.sunat.foto(ds, ruc)
+		}
+		datosIDs, err := ds.insertFotos(tx, rucIDs, consultaIDs, fotos)
+		if err != nil {
+			return fmt.Errorf("error inserting %s: %w", sec.nombre(), err)
+		}
+		for i, datosID := range datosIDs {
+			if datosID != 0 {
+				datos[i][sec.Clave] = datosID
+			}
+		}
+	}
+
+	// 6. Insertar el estado de cada sección
+	var estados [][]interface{}
+	for i, ruc := range lote {
+		estados = append(estados, filasEstadoSecciones(rucIDs[i], consultaIDs[i], ruc, datos[i])...)
+	}
+	if err := insertFilas(tx, "ruc_consulta_secciones", columnasEstadoSecciones, estados, ""); err != nil {
+		return fmt.Errorf("error inserting estado secciones: %w", err)
+	}
+
+	// 7. Descartar las consultas repetidas y registrar los cambios de los RUC
+	// que ya tenían consultas; en una carga inicial no hay ninguno y este paso
+	// no cuesta nada
+	for i := range lote {
+		if !conConsultas[i] {
+			continue
+		}
+		repetida, err := ds.descartarConsultaRepetida(tx, rucIDs[i], consultaIDs[i])
+		if err != nil {
+			return fmt.Errorf("error deleting consulta repetida: %w", err)
+		}
+		if repetida {
+			continue
+		}
+		if err := ds.insertCambios(tx, rucIDs[i], consultaIDs[i]); err != nil {
+			return fmt.Errorf("error inserting cambios: %w", err)
+		}
+	}
+
+	// 8. Los RUC que figuraban como inexistentes dejan de estarlo
+	numeros := make([]string, len(lote))
+	for i, ruc := range lote {
+		numeros[i] = ruc.InformacionBasica.RUC
+	}
+	if _, err := tx.Exec(`DELETE FROM ruc_inexistentes WHERE ruc = ANY($1)`, pq.Array(numeros)); err != nil {
+		return fmt.Errorf("error deleting ruc inexistente: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// insertRUCsInformacionBasica crea los RUC del lote o actualiza su ficha, y
+// retorna sus id y si ya tenían consultas guardadas
+func (ds *DatabaseService) insertRUCsInformacionBasica(tx *sql.Tx, lote []*models.RUCCompleto) ([]int64, []bool, error) {
+	filas := make([][]interface{}, len(lote))
+	numeros := make([]string, len(lote))
+	for i, ruc := range lote {
+		filas[i] = append([]interface{}{ruc.InformacionBasica.RUC}, ds.valoresFicha(&ruc.InformacionBasica)...)
+		numeros[i] = ruc.InformacionBasica.RUC
+	}
+	err := insertFilas(tx, "ruc_informacion_basica", append([]string{"ruc"}, camposFicha...), filas, `
+		ON CONFLICT (ruc) DO UPDATE SET
+		`+actualizarFicha+`,
+		updated_at = CURRENT_TIMESTAMP
+		WHERE `+fichaCambiada)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Los RUC cuya ficha no cambió no pasan por el UPDATE: los id se leen aparte
+	rows, err := tx.Query(`
+		SELECT b.ruc, b.id, EXISTS (SELECT 1 FROM ruc_consultas c WHERE c.ruc_id = b.id)
+		FROM ruc_informacion_basica b
+		WHERE b.ruc = ANY($1)`,
+		pq.Array(numeros))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	type rucGuardado struct {
+		id           int64
+		conConsultas bool
+	}
+	guardados := make(map[string]rucGuardado, len(lote))
+	for rows.Next() {
+		var ruc string
+		var g rucGuardado
+		if err := rows.Scan(&ruc, &g.id, &g.conConsultas); err != nil {
+			return nil, nil, err
+		}
+		guardados[ruc] = g
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	rucIDs := make([]int64, len(lote))
+	conConsultas := make([]bool, len(lote))
+	for i, numero := range numeros {
+		g, ok := guardados[numero]
+		if !ok {
+			return nil, nil, fmt.Errorf("RUC %s not found in ruc_informacion_basica", numero)
+		}
+		rucIDs[i], conConsultas[i] = g.id, g.conConsultas
+	}
+	return rucIDs, conConsultas, nil
+}
+
+// insertFotos guarda la foto de una sección para cada RUC del lote (nil si no
+// se consultó) y retorna, por RUC, la consulta que tiene sus datos; 0 si no hay
+func (ds *DatabaseService) insertFotos(tx *sql.Tx, rucIDs, consultaIDs []int64, fotos []*foto) ([]int64, error) {
+	var (
+		indices []int
+		ids     []int64
+		datos   []any
+		tabla   string
+	)
+	for i, f := range fotos {
+		if f != nil {
+			indices = append(indices, i)
+			ids = append(ids, rucIDs[i])
+			datos = append(datos, f.datos)
+			tabla = f.tabla
+		}
+	}
+	datosIDs := make([]int64, len(fotos))
+	if len(indices) == 0 {
+		return datosIDs, nil
+	}
+
+	// Solo se guardan los datos que ninguna consulta anterior del RUC tenía
+	huellas, previas, err := ds.buscarFotos(tx, tabla, ids, datos)
+	if err != nil {
+		return nil, err
+	}
+	var nuevas, nuevasHuellas []int
+	var representantes []models.RepresentanteLegal
+	for j, i := range indices {
+		if previas[j] != 0 {
+			datosIDs[i] = previas[j]
+			continue
+		}
+		datosIDs[i] = consultaIDs[i]
+		nuevas = append(nuevas, i)
+		nuevasHuellas = append(nuevasHuellas, j)
+		representantes = append(representantes, fotos[i].personas...)
+	}
+	if len(nuevas) == 0 {
+		return datosIDs, nil
+	}
+
+	personas, err := ds.upsertPersonas(tx, representantes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Registros principales con ids reservados y sus detalles, una sentencia
+	// por tabla para todo el lote
+	principalIDs, err := reservarIDs(tx, tabla, len(nuevas))
+	if err != nil {
+		return nil, err
+	}
+	principales := make([][]interface{}, len(nuevas))
+	var detalles []detalle
+	for k, i := range nuevas {
+		f := fotos[i]
+		principales[k] = append([]interface{}{principalIDs[k]},
+			f.filaPrincipal(rucIDs[i], consultaIDs[i], huellas[nuevasHuellas[k]])...)
+
+		for j, d := range f.detalles {
+			if j == len(detalles) {
+				detalles = append(detalles, detalle{tabla: d.tabla, fk: d.fk, columnas: d.columnas, clave: d.clave})
+			}
+			detalles[j].filas = append(detalles[j].filas, d.filasDe(principalIDs[k], personas)...)
+		}
+	}
+	columnas := append([]string{"id"}, fotos[nuevas[0]].columnasPrincipal()...)
+	if err := insertFilas(tx, tabla, columnas, principales, ""); err != nil {
+		return nil, err
+	}
+	// Las filas ya llevan el id de su registro principal y de su persona
+	for _, d := range detalles {
+		if err := insertFilas(tx, d.tabla, d.columnasConFK(), d.filas, ""); err != nil {
+			return nil, err
+		}
+	}
+	return datosIDs, nil
+}
+
+// buscarFotos es buscarFoto para un lote, con un RUC por elemento: retorna la
+// huella de cada dato y la consulta anterior que ya lo guardó, 0 si no hay
+func (ds *DatabaseService) buscarFotos(tx *sql.Tx, tabla string, rucIDs []int64, datos []any) ([]string, []int64, error) {
+	huellas := make([]string, len(datos))
+	for i, d := range datos {
+		h, err := huella(d)
+		if err != nil {
+			return nil, nil, err
+		}
+		huellas[i] = h
+	}
+
+	rows, err := tx.Query(`
+		SELECT ruc_id, consulta_id FROM `+tabla+`
+		WHERE (ruc_id, huella) IN (SELECT * FROM unnest($1::bigint[], $2::bpchar[]))`,
+		pq.Array(rucIDs), pq.Array(huellas))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	previas := make(map[int64]int64)
+	for rows.Next() {
+		var rucID, consultaID int64
+		if err := rows.Scan(&rucID, &consultaID); err != nil {
+			return nil, nil, err
+		}
+		previas[rucID] = consultaID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	consultaIDs := make([]int64, len(rucIDs))
+	for i, rucID := range rucIDs {
+		consultaIDs[i] = previas[rucID]
+	}
+	return huellas, consultaIDs, nil
+}
+
+// reservarIDs toma n valores de la secuencia del id de tabla
+func reservarIDs(tx *sql.Tx, tabla string, n int) ([]int64, error) {
+	rows, err := tx.Query(`SELECT nextval(pg_get_serial_sequence($1, 'id')) FROM generate_series(1, $2)`, tabla, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0, n)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/documento"
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+func TestPartirLotes(t *testing.T) {
+	rucs := make([]*models.RUCCompleto, 0, 6)
+	for _, n := range []string{"20100000001", "20100000002", "20100000003", "20100000001", "20100000004", "20100000005"} {
+		rucs = append(rucs, &models.RUCCompleto{InformacionBasica: models.RUCInfo{RUC: n}})
+	}
+
+	// El RUC repetido empieza otro lote aunque el lote no esté lleno
+	want := [][]int{{0, 1, 2}, {3, 4, 5}}
+	if got := partirLotes(rucs, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("partirLotes = %v, se esperaba %v", got, want)
+	}
+	want = [][]int{{0, 1}, {2, 3}, {4, 5}}
+	if got := partirLotes(rucs, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("partirLotes = %v, se esperaba %v", got, want)
+	}
+}
+
+func TestSentenciaFilas(t *testing.T) {
+	got := sentenciaFilas("personas", []string{"tipo_documento", "numero_documento"}, 2,
+		"ON CONFLICT (tipo_documento, numero_documento) DO NOTHING")
+	want := "INSERT INTO personas (tipo_documento, numero_documento) VALUES ($1, $2), ($3, $4)" +
+		" ON CONFLICT (tipo_documento, numero_documento) DO NOTHING"
+	if got != want {
+		t.Errorf("sentenciaFilas:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestFilasDe(t *testing.T) {
+	ds := &DatabaseService{}
+	f := ds.fotoRepresentantesLegales(&models.RepresentantesLegales{Representantes: []models.RepresentanteLegal{
+		{TipoDocumento: documento.DNI, NumeroDocumento: "41234567", NombreCompleto: "PEREZ, JUAN"},
+		{NumeroDocumento: "X123", NombreCompleto: "SIN DOCUMENTO"},
+	}})
+
+	personas := map[clavePersona]int64{{string(documento.DNI), "41234567"}: 7}
+	filas := f.detalles[0].filasDe(30, personas)
+	if filas[0][0] != int64(30) || filas[0][1] != int64(7) {
+		t.Errorf("fila con persona: id %v, persona_id %v; se esperaba 30 y 7", filas[0][0], filas[0][1])
+	}
+	if filas[1][1] != nil {
+		t.Errorf("persona_id sin tipo de documento = %v, se esperaba NULL", filas[1][1])
+	}
+	if n := len(filas[0]); n != len(f.detalles[0].columnasConFK()) {
+		t.Errorf("la fila tiene %d valores para %d columnas", n, len(f.detalles[0].columnasConFK()))
+	}
+}
+
+func TestFilasDeRepeticion(t *testing.T) {
+	// Dos omisiones con el mismo periodo y tributo se guardan las dos
+	ds := &DatabaseService{}
+	omision := models.Omision{Periodo: models.NuevoPeriodo(2024, time.January), Tributo: "IGV", Estado: "PENDIENTE"}
+	otra := models.Omision{Periodo: models.NuevoPeriodo(2024, time.February), Tributo: "IGV", Estado: "PENDIENTE"}
+	f := ds.fotoOmisionesTributarias(&models.OmisionesTributarias{Omisiones: []models.Omision{omision, otra, omision}})
+
+	d := f.detalles[0]
+	columnas := d.columnasConFK()
+	if columnas[len(columnas)-1] != "repeticion" {
+		t.Fatalf("columnas = %v, se esperaba repeticion al final", columnas)
+	}
+	var repeticiones []interface{}
+	for _, fila := range d.filasDe(30, nil) {
+		repeticiones = append(repeticiones, fila[len(fila)-1])
+	}
+	if want := []interface{}{1, 1, 2}; !reflect.DeepEqual(repeticiones, want) {
+		t.Errorf("repeticion = %v, se esperaba %v", repeticiones, want)
+	}
+}
+
+// Los tests con base y los benchmarks escriben en la base de
+// TEST_DATABASE_URL, que se migra si hace falta; los benchmarks sirven para
+// dimensionar la base:
+//
+//	TEST_DATABASE_URL=postgres://... go test ./pkg/database -run '^$' -bench Insert -benchtime 2000x
+func baseDePrueba(b testing.TB) *DatabaseService {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		b.Skip("TEST_DATABASE_URL no definida")
+	}
+
+	migrador, err := NewMigrador(url)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer migrador.Close()
+	if _, err := migrador.Subir(0); err != nil {
+		b.Fatal(err)
+	}
+
+	ds, err := NewDatabaseService(url)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { ds.Close() })
+	return ds
+}
+
+// corrida y rucsDePrueba numeran los RUC de los benchmarks para que no se
+// repitan entre ejecuciones ni entre llamadas de una misma ejecución
+var (
+	corrida      = time.Now().Unix() % 10000
+	rucsDePrueba int
+)
+
+// consultasDePrueba arma n consultas de RUC nuevos, con filas en las
+// secciones más grandes
+func consultasDePrueba(n int) []*models.RUCCompleto {
+	rucs := make([]*models.RUCCompleto, n)
+	for i := range rucs {
+		rucsDePrueba++
+		ruc := &models.RUCCompleto{
+			InformacionBasica: models.RUCInfo{
+				RUC:               fmt.Sprintf("1%04d%06d", corrida, rucsDePrueba),
+				RazonSocial:       fmt.Sprintf("EMPRESA %d S.A.C.", i),
+				TipoContribuyente: "SOCIEDAD ANONIMA CERRADA",
+				Estado:            "ACTIVO",
+				Condicion:         "HABIDO",
+				DomicilioFiscal:   "AV. LOS OLIVOS 123 LIMA - LIMA - SAN ISIDRO",
+				ActividadesEconomicas: []models.ActividadEconomica{
+					{Tipo: models.ActividadPrincipal, CodigoCIIU: "6920", RevisionCIIU: 4, Descripcion: "ACTIVIDADES DE CONTABILIDAD"},
+				},
+				ComprobantesPago: []string{"FACTURA", "BOLETA DE VENTA"},
+				Padrones:         []string{"Incorporado al Régimen de Buenos Contribuyentes"},
+			},
+			DeudaCoactiva:          &models.DeudaCoactiva{},
+			OmisionesTributarias:   &models.OmisionesTributarias{},
+			CantidadTrabajadores:   &models.CantidadTrabajadores{},
+			RepresentantesLegales:  &models.RepresentantesLegales{},
+			EstablecimientosAnexos: &models.EstablecimientosAnexos{},
+			EstadoSecciones:        map[string]models.EstadoSeccion{},
+			FechaConsulta:          time.Now(),
+			VersionAPI:             "bench",
+		}
+		for m := time.January; m <= time.December; m++ {
+			p := models.NuevoPeriodo(2024, m)
+			ruc.CantidadTrabajadores.PeriodosDisponibles = append(ruc.CantidadTrabajadores.PeriodosDisponibles, p)
+			ruc.CantidadTrabajadores.DetallePorPeriodo = append(ruc.CantidadTrabajadores.DetallePorPeriodo,
+				models.DetalleTrabajadores{Periodo: p, CantidadTrabajadores: 10 + int(m), Total: 10 + int(m)})
+		}
+		for j := 0; j < 3; j++ {
+			ruc.DeudaCoactiva.Deudas = append(ruc.DeudaCoactiva.Deudas, models.DetalleDeuda{
+				Monto: models.Soles(int64(1000 * (j + 1))), PeriodoTributario: models.NuevoPeriodo(2023, time.Month(j+1)),
+				FechaInicioCobranza: models.NuevaFecha(2024, time.March, j+1), Entidad: "SUNAT"})
+			ruc.OmisionesTributarias.Omisiones = append(ruc.OmisionesTributarias.Omisiones, models.Omision{
+				Periodo: models.NuevoPeriodo(2024, time.Month(j+1)), Tributo: "IGV", Estado: "PENDIENTE"})
+			ruc.RepresentantesLegales.Representantes = append(ruc.RepresentantesLegales.Representantes, models.RepresentanteLegal{
+				TipoDocumento: documento.DNI, NumeroDocumento: fmt.Sprintf("%08d", i*3+j),
+				NombreCompleto: "PEREZ, JUAN", Cargo: models.CargoGerenteGeneral, Vigente: true})
+			ruc.EstablecimientosAnexos.Establecimientos = append(ruc.EstablecimientosAnexos.Establecimientos, models.EstablecimientoAnexo{
+				Codigo: fmt.Sprintf("%04d", j+1), TipoEstablecimiento: "LOCAL COMERCIAL", Direccion: "JR. UNION 456"})
+		}
+		for _, clave := range []string{"deuda_coactiva", "omisiones_tributarias", "cantidad_trabajadores", "representantes_legales", "establecimientos_anexos"} {
+			ruc.EstadoSecciones[clave] = models.SeccionConsultada
+		}
+		rucs[i] = ruc
+	}
+	return rucs
+}
+
+func BenchmarkInsertRUCCompleto(b *testing.B) {
+	ds := baseDePrueba(b)
+	rucs := consultasDePrueba(b.N)
+
+	b.ResetTimer()
+	for _, ruc := range rucs {
+		if err := ds.InsertRUCCompleto(ruc); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "rucs/s")
+}
+
+func BenchmarkInsertRUCCompletoLote(b *testing.B) {
+	ds := baseDePrueba(b)
+	rucs := consultasDePrueba(b.N)
+
+	b.ResetTimer()
+	for _, r := range ds.InsertRUCCompletoLote(rucs, TamanoLote) {
+		if r.Err != nil {
+			b.Fatalf("%s: %v", r.RUC, r.Err)
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "rucs/s")
+}
@@ -0,0 +1,344 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Las migraciones van embebidas en el binario como pares
+// NNNN_nombre.up.sql / NNNN_nombre.down.sql con versiones consecutivas desde 1.
+// La versión aplicada se guarda en schema_migrations.
+//
+//go:embed migraciones/*.sql
+var archivosMigraciones embed.FS
+
+var (
+	ErrVersionEsquema = errors.New("versión de esquema distinta a la esperada")
+	ErrMigraciones    = errors.New("migraciones embebidas inválidas")
+)
+
+// Migracion es un paso del esquema con su SQL para aplicarlo y deshacerlo
+type Migracion struct {
+	Version int
+	Nombre  string
+	Subir   string
+	Bajar   string
+}
+
+// EstadoMigracion indica si una migración está aplicada y desde cuándo
+type EstadoMigracion struct {
+	Migracion
+	Aplicada   bool
+	AplicadaEn time.Time
+}
+
+var nombreMigracion = regexp.MustCompile(`^(\d{4})_(\w+)\.(up|down)\.sql$`)
+
+// Migraciones retorna las migraciones embebidas ordenadas por versión
+func Migraciones() ([]Migracion, error) {
+	archivos, err := fs.Glob(archivosMigraciones, "migraciones/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	porVersion := make(map[int]*Migracion)
+	for _, archivo := range archivos {
+		m := nombreMigracion.FindStringSubmatch(path.Base(archivo))
+		if m == nil {
+			return nil, fmt.Errorf("%q: nombre no reconocido: %w", archivo, ErrMigraciones)
+		}
+		version, _ := strconv.Atoi(m[1])
+		contenido, err := archivosMigraciones.ReadFile(archivo)
+		if err != nil {
+			return nil, err
+		}
+
+		mig := porVersion[version]
+		if mig == nil {
+			mig = &Migracion{Version: version, Nombre: m[2]}
+			porVersion[version] = mig
+		} else if mig.Nombre != m[2] {
+			return nil, fmt.Errorf("%q: la versión %d ya es %q: %w", archivo, version, mig.Nombre, ErrMigraciones)
+		}
+		if m[3] == "up" {
+			mig.Subir = string(contenido)
+		} else {
+			mig.Bajar = string(contenido)
+		}
+	}
+
+	migraciones := make([]Migracion, 0, len(porVersion))
+	for _, mig := range porVersion {
+		migraciones = append(migraciones, *mig)
+	}
+	sort.Slice(migraciones, func(i, j int) bool { return migraciones[i].Version < migraciones[j].Version })
+
+	for i, mig := range migraciones {
+		if mig.Version != i+1 {
+			return nil, fmt.Errorf("%q: se esperaba la versión %d: %w", mig.Nombre, i+1, ErrMigraciones)
+		}
+		if mig.Subir == "" || mig.Bajar == "" {
+			return nil, fmt.Errorf("%q: falta el .up.sql o el .down.sql: %w", mig.Nombre, ErrMigraciones)
+		}
+	}
+	return migraciones, nil
+}
+
+// UltimaVersion es la versión del esquema que espera este binario
+func UltimaVersion() (int, error) {
+	migraciones, err := Migraciones()
+	if err != nil {
+		return 0, err
+	}
+	return len(migraciones), nil
+}
+
+// consultor es lo que comparten *sql.DB y *sql.Tx para leer la versión
+type consultor interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// versionEsquema retorna la última migración aplicada, 0 si la base nunca se
+// migró (no existe schema_migrations)
+func versionEsquema(q consultor) (int, error) {
+	var existe bool
+	if err := q.QueryRow(`SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&existe); err != nil {
+		return 0, fmt.Errorf("error reading schema version: %w", err)
+	}
+	if !existe {
+		return 0, nil
+	}
+
+	var version int
+	if err := q.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("error reading schema version: %w", err)
+	}
+	return version, nil
+}
+
+// verificarEsquema falla si la base no está en la versión de las migraciones
+// embebidas, para no escribir con un binario y un esquema que no coinciden
+func verificarEsquema(db *sql.DB) error {
+	esperada, err := UltimaVersion()
+	if err != nil {
+		return err
+	}
+	actual, err := versionEsquema(db)
+	if err != nil {
+		return err
+	}
+	if actual != esperada {
+		return fmt.Errorf("esquema en versión %d, se esperaba %d (go run ./cmd/migrate up): %w", actual, esperada, ErrVersionEsquema)
+	}
+	return nil
+}
+
+// Migrador aplica y deshace las migraciones embebidas. A diferencia de
+// DatabaseService, no exige que el esquema esté al día.
+type Migrador struct {
+	db          *sql.DB
+	migraciones []Migracion
+}
+
+func NewMigrador(connectionString string) (*Migrador, error) {
+	migraciones, err := Migraciones()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("postgres", connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error pinging database: %w", err)
+	}
+	return &Migrador{db: db, migraciones: migraciones}, nil
+}
+
+func (m *Migrador) Close() error {
+	return m.db.Close()
+}
+
+// Version retorna la última migración aplicada
+func (m *Migrador) Version() (int, error) {
+	return versionEsquema(m.db)
+}
+
+// Subir aplica hasta n migraciones pendientes (todas si n <= 0) y retorna las
+// aplicadas
+func (m *Migrador) Subir(n int) ([]Migracion, error) {
+	var aplicadas []Migracion
+	for n <= 0 || len(aplicadas) < n {
+		mig, err := m.paso(true)
+		if err != nil || mig == nil {
+			return aplicadas, err
+		}
+		aplicadas = append(aplicadas, *mig)
+	}
+	return aplicadas, nil
+}
+
+// Bajar deshace las últimas n migraciones aplicadas y retorna las deshechas
+func (m *Migrador) Bajar(n int) ([]Migracion, error) {
+	var deshechas []Migracion
+	for len(deshechas) < n {
+		mig, err := m.paso(false)
+		if err != nil || mig == nil {
+			return deshechas, err
+		}
+		deshechas = append(deshechas, *mig)
+	}
+	return deshechas, nil
+}
+
+// paso aplica la siguiente migración o deshace la última en su propia
+// transacción; retorna nil si no queda ninguna
+func (m *Migrador) paso(subir bool) (*Migracion, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	version, err := m.bloquear(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mig Migracion
+	if subir {
+		if version == len(m.migraciones) {
+			return nil, nil
+		}
+		mig = m.migraciones[version]
+		if _, err := tx.Exec(mig.Subir); err != nil {
+			return nil, fmt.Errorf("error applying migration %04d_%s: %w", mig.Version, mig.Nombre, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, nombre) VALUES ($1, $2)`, mig.Version, mig.Nombre); err != nil {
+			return nil, fmt.Errorf("error inserting schema_migrations: %w", err)
+		}
+	} else {
+		if version == 0 {
+			return nil, nil
+		}
+		mig = m.migraciones[version-1]
+		if _, err := tx.Exec(mig.Bajar); err != nil {
+			return nil, fmt.Errorf("error reverting migration %04d_%s: %w", mig.Version, mig.Nombre, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, mig.Version); err != nil {
+			return nil, fmt.Errorf("error deleting schema_migrations: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing migration %04d_%s: %w", mig.Version, mig.Nombre, err)
+	}
+	return &mig, nil
+}
+
+// bloquear toma el advisory lock de las migraciones, que evita que dos
+// procesos migren la misma base a la vez, crea schema_migrations si no existe
+// y retorna la versión aplicada
+func (m *Migrador) bloquear(tx *sql.Tx) (int, error) {
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('schema_migrations'))`); err != nil {
+		return 0, fmt.Errorf("error locking schema_migrations: %w", err)
+	}
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			nombre TEXT NOT NULL,
+			aplicada_en TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`); err != nil {
+		return 0, fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	version, err := versionEsquema(tx)
+	if err != nil {
+		return 0, err
+	}
+	if version > len(m.migraciones) {
+		return 0, fmt.Errorf("esquema en versión %d, este binario solo conoce hasta la %d: %w", version, len(m.migraciones), ErrVersionEsquema)
+	}
+	return version, nil
+}
+
+// Adoptar registra como aplicadas las migraciones hasta version sin ejecutar su
+// SQL, para una base que ya tiene ese esquema pero no schema_migrations (p. ej.
+// restaurada de un respaldo parcial). Una base creada con database/prueba.sql no
+// se adopta: Subir aparta sus tablas y copia sus datos (migraciones 2 y 5).
+// Solo se puede adoptar una base sin migraciones registradas; retorna las
+// registradas.
+func (m *Migrador) Adoptar(version int) ([]Migracion, error) {
+	if version < 1 || version > len(m.migraciones) {
+		return nil, fmt.Errorf("versión %d fuera de rango (1 a %d): %w", version, len(m.migraciones), ErrVersionEsquema)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	actual, err := m.bloquear(tx)
+	if err != nil {
+		return nil, err
+	}
+	if actual != 0 {
+		return nil, fmt.Errorf("la base ya está en la versión %d (go run ./cmd/migrate up): %w", actual, ErrVersionEsquema)
+	}
+
+	adoptadas := m.migraciones[:version]
+	for _, mig := range adoptadas {
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, nombre) VALUES ($1, $2)`, mig.Version, mig.Nombre); err != nil {
+			return nil, fmt.Errorf("error inserting schema_migrations: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing schema_migrations: %w", err)
+	}
+	return adoptadas, nil
+}
+
+// Estado lista todas las migraciones embebidas indicando cuáles están aplicadas
+func (m *Migrador) Estado() ([]EstadoMigracion, error) {
+	aplicadas := make(map[int]time.Time)
+	version, err := versionEsquema(m.db)
+	if err != nil {
+		return nil, err
+	}
+	if version > 0 {
+		rows, err := m.db.Query(`SELECT version, aplicada_en FROM schema_migrations`)
+		if err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var v int
+			var en time.Time
+			if err := rows.Scan(&v, &en); err != nil {
+				return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+			}
+			aplicadas[v] = en
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+	}
+
+	estado := make([]EstadoMigracion, len(m.migraciones))
+	for i, mig := range m.migraciones {
+		en, ok := aplicadas[mig.Version]
+		estado[i] = EstadoMigracion{Migracion: mig, Aplicada: ok, AplicadaEn: en}
+	}
+	return estado, nil
+}
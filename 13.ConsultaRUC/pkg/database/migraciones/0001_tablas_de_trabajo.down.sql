@@ -0,0 +1,3 @@
+-- empresas_sunat no se elimina: se carga aparte y tarda en volver a cargarse
+DROP TABLE IF EXISTS log_consultas;
+DROP TABLE IF EXISTS ruc_pruebas;
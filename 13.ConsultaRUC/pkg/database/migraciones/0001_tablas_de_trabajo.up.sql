@@ -0,0 +1,38 @@
+-- Migración 1: tablas de trabajo de main.sh (antes database/ruc_pruebas.sql).
+-- empresas_sunat se carga aparte con el padrón reducido de SUNAT.
+CREATE TABLE IF NOT EXISTS empresas_sunat (
+    ruc BIGINT PRIMARY KEY,
+    estado TEXT,
+    condicion TEXT,
+    tipo TEXT,
+    actividad_economica_ciiu_rev3_principal TEXT,
+    actividad_economica_ciiu_rev3_secundaria TEXT,
+    actividad_economica_ciiu_rev4_principal TEXT,
+    nro_trabajadores TEXT,
+    tipo_facturacion TEXT,
+    tipo_contabilidad TEXT,
+    comercio_exterior TEXT,
+    ubigeo TEXT,
+    departamento TEXT,
+    provincia TEXT,
+    distrito TEXT,
+    periodo_publicacion CHAR(6)
+);
+
+-- Crear la tabla ruc_pruebas
+CREATE TABLE IF NOT EXISTS ruc_pruebas (
+    id SERIAL PRIMARY KEY,
+    ruc VARCHAR(11) NOT NULL UNIQUE  -- UNIQUE constraint agregada
+);
+
+CREATE TABLE IF NOT EXISTS log_consultas (
+    id SERIAL PRIMARY KEY,
+    ruc VARCHAR(11) NOT NULL,
+    estado VARCHAR(50) NOT NULL,
+    mensaje TEXT,
+    especificacion TEXT,                        -- salida de la terminal del scraper
+    fecha_registro TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bases creadas con los scripts anteriores, sin la columna
+ALTER TABLE log_consultas ADD COLUMN IF NOT EXISTS especificacion TEXT;
\ No newline at end of file
@@ -0,0 +1,73 @@
+-- Deshace la migración 2 en orden inverso: vistas, funciones y tablas.
+
+DROP VIEW IF EXISTS personas_empresas;
+DROP VIEW IF EXISTS ruc_establecimientos_anexos_actual;
+DROP VIEW IF EXISTS ruc_representantes_legales_actual;
+DROP VIEW IF EXISTS ruc_programa_covid19_actual;
+DROP VIEW IF EXISTS ruc_reactiva_peru_actual;
+DROP VIEW IF EXISTS ruc_facturas_fisicas_actual;
+DROP VIEW IF EXISTS ruc_actas_probatorias_actual;
+DROP VIEW IF EXISTS ruc_cantidad_trabajadores_actual;
+DROP VIEW IF EXISTS ruc_omisiones_tributarias_actual;
+DROP VIEW IF EXISTS ruc_deuda_coactiva_actual;
+DROP VIEW IF EXISTS ruc_informacion_historica_actual;
+DROP VIEW IF EXISTS ruc_padrones_actual;
+DROP VIEW IF EXISTS ruc_comprobantes_electronicos_actual;
+DROP VIEW IF EXISTS ruc_sistemas_emision_electronica_actual;
+DROP VIEW IF EXISTS ruc_comprobantes_pago_actual;
+DROP VIEW IF EXISTS ruc_actividades_economicas_actual;
+DROP VIEW IF EXISTS ruc_consulta_actual;
+
+DROP FUNCTION IF EXISTS ruc_consulta_al(VARCHAR, DATE);
+
+DROP TABLE IF EXISTS ruc_cambios;
+DROP TABLE IF EXISTS ruc_establecimientos;
+DROP TABLE IF EXISTS ruc_establecimientos_anexos;
+DROP TABLE IF EXISTS ruc_representantes;
+DROP TABLE IF EXISTS personas;
+DROP TABLE IF EXISTS ruc_representantes_legales;
+DROP TABLE IF EXISTS ruc_programa_covid19;
+DROP TABLE IF EXISTS ruc_reactiva_peru;
+DROP TABLE IF EXISTS ruc_facturas_canceladas_bajas;
+DROP TABLE IF EXISTS ruc_facturas_autorizadas;
+DROP TABLE IF EXISTS ruc_facturas_fisicas;
+DROP TABLE IF EXISTS ruc_actas;
+DROP TABLE IF EXISTS ruc_actas_probatorias;
+DROP TABLE IF EXISTS ruc_detalle_trabajadores;
+DROP TABLE IF EXISTS ruc_periodos_disponibles_trabajadores;
+DROP TABLE IF EXISTS ruc_cantidad_trabajadores;
+DROP TABLE IF EXISTS ruc_omisiones;
+DROP TABLE IF EXISTS ruc_omisiones_tributarias;
+DROP TABLE IF EXISTS ruc_deuda_filas_invalidas;
+DROP TABLE IF EXISTS ruc_detalle_deudas;
+DROP TABLE IF EXISTS ruc_deuda_coactiva;
+DROP TABLE IF EXISTS ruc_domicilios_fiscales_historicos;
+DROP TABLE IF EXISTS ruc_condiciones_historicas;
+DROP TABLE IF EXISTS ruc_razones_sociales_historicas;
+DROP TABLE IF EXISTS ruc_informacion_historica;
+DROP TABLE IF EXISTS ruc_inexistentes;
+DROP TABLE IF EXISTS ruc_consulta_secciones;
+DROP TABLE IF EXISTS ruc_padrones;
+DROP TABLE IF EXISTS ruc_comprobantes_electronicos;
+DROP TABLE IF EXISTS ruc_sistemas_emision_electronica;
+DROP TABLE IF EXISTS ruc_comprobantes_pago;
+DROP TABLE IF EXISTS ruc_actividades_economicas;
+DROP TABLE IF EXISTS ruc_fichas;
+DROP TABLE IF EXISTS ruc_consultas;
+DROP TABLE IF EXISTS ruc_informacion_basica;
+
+-- Si la migración 5 aún no copió las tablas de database/prueba.sql, vuelven a
+-- su esquema con el trigger que usa update_updated_at_column
+DO $$
+DECLARE
+    tabla TEXT;
+BEGIN
+    IF to_regnamespace('ruc_anterior') IS NULL THEN
+        DROP FUNCTION IF EXISTS update_updated_at_column();
+        RETURN;
+    END IF;
+    FOR tabla IN SELECT tablename FROM pg_tables WHERE schemaname = 'ruc_anterior' LOOP
+        EXECUTE format('ALTER TABLE ruc_anterior.%I SET SCHEMA %I', tabla, current_schema());
+    END LOOP;
+    DROP SCHEMA ruc_anterior;
+END $$;
@@ -0,0 +1,784 @@
+-- ====================================
+-- TABLAS PARA SISTEMA RUC - PostgreSQL
+-- ====================================
+-- Migración 2: esquema de los RUC consultados (antes database/prueba.sql)
+
+-- Una base creada con database/prueba.sql tiene tablas con estos nombres pero
+-- sin consultas por foto: se apartan en el esquema ruc_anterior, con sus
+-- índices y secuencias, y la migración 5 copia sus datos al esquema nuevo.
+-- En una base creada con las migraciones no hace nada.
+DO $$
+DECLARE
+    tabla TEXT;
+BEGIN
+    IF to_regclass('ruc_informacion_basica') IS NULL THEN
+        RETURN;
+    END IF;
+    CREATE SCHEMA ruc_anterior;
+    FOREACH tabla IN ARRAY ARRAY[
+        'ruc_informacion_basica', 'ruc_actividades_economicas', 'ruc_comprobantes_pago',
+        'ruc_sistemas_emision_electronica', 'ruc_comprobantes_electronicos', 'ruc_padrones', 'ruc_consultas',
+        'ruc_informacion_historica', 'ruc_razones_sociales_historicas', 'ruc_condiciones_historicas',
+        'ruc_domicilios_fiscales_historicos', 'ruc_deuda_coactiva', 'ruc_detalle_deudas',
+        'ruc_omisiones_tributarias', 'ruc_omisiones', 'ruc_cantidad_trabajadores',
+        'ruc_periodos_disponibles_trabajadores', 'ruc_detalle_trabajadores', 'ruc_actas_probatorias', 'ruc_actas',
+        'ruc_facturas_fisicas', 'ruc_facturas_autorizadas', 'ruc_facturas_canceladas_bajas', 'ruc_reactiva_peru',
+        'ruc_programa_covid19', 'ruc_representantes_legales', 'ruc_representantes',
+        'ruc_establecimientos_anexos', 'ruc_establecimientos'
+    ] LOOP
+        IF to_regclass(tabla) IS NOT NULL THEN
+            EXECUTE format('ALTER TABLE %I SET SCHEMA ruc_anterior', tabla);
+        END IF;
+    END LOOP;
+END $$;
+
+-- Un RUC por fila, con la ficha de su última consulta (el historial está en ruc_fichas)
+CREATE TABLE ruc_informacion_basica (
+    id BIGSERIAL PRIMARY KEY,
+    ruc VARCHAR(11) NOT NULL UNIQUE,
+    razon_social TEXT NOT NULL,
+    tipo_contribuyente VARCHAR(100),
+    tipo_documento VARCHAR(20),                 -- solo personas naturales: DNI, CE, PASAPORTE, ... (pkg/documento)
+    numero_documento VARCHAR(15),
+    nombre_titular TEXT,
+    nombre_comercial TEXT,
+    fecha_inscripcion DATE,
+    fecha_inicio_actividades DATE,
+    estado VARCHAR(50),
+    condicion VARCHAR(50),
+    domicilio_fiscal TEXT,
+    sistema_emision VARCHAR(100),
+    actividad_comercio_exterior VARCHAR(100),
+    sistema_contabilidad VARCHAR(100),
+    emisor_electronico_desde DATE,
+    afiliado_ple DATE,
+    -- domicilio_fiscal separado con models.ParseDomicilio; ubigeo se une con empresas_sunat.ubigeo
+    tipo_via VARCHAR(10),
+    nombre_via TEXT,
+    numero VARCHAR(20),
+    interior VARCHAR(20),
+    manzana VARCHAR(20),
+    lote VARCHAR(20),
+    tipo_urbanizacion VARCHAR(10),
+    urbanizacion TEXT,
+    departamento VARCHAR(50),
+    provincia VARCHAR(50),
+    distrito VARCHAR(50),
+    ubigeo VARCHAR(6),                          -- NULL si el distrito no está en pkg/ubigeo
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Cada consulta es una foto inmutable de lo que SUNAT mostró ese día; las
+-- tablas de la ficha y de las consultas adicionales se enlazan con consulta_id.
+-- Los datos que no cambiaron desde una consulta anterior no se vuelven a
+-- guardar: la consulta apunta a la que ya los tiene (ficha_consulta_id y
+-- ruc_consulta_secciones.datos_consulta_id).
+CREATE TABLE ruc_consultas (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    fecha_consulta TIMESTAMP NOT NULL,
+    version_api VARCHAR(20),
+    ficha_consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id), -- consulta con esta misma ficha en ruc_fichas
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Cada ficha distinta de un RUC, guardada por la primera consulta que la vio;
+-- ruc_informacion_basica guarda la de la última. Las listas de la ficha
+-- (actividades, comprobantes, padrones) se enlazan con el mismo consulta_id.
+CREATE TABLE ruc_fichas (
+    consulta_id BIGINT PRIMARY KEY REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la ficha con sus listas
+    razon_social TEXT NOT NULL,
+    tipo_contribuyente VARCHAR(100),
+    tipo_documento VARCHAR(20),                 -- solo personas naturales: DNI, CE, PASAPORTE, ... (pkg/documento)
+    numero_documento VARCHAR(15),
+    nombre_titular TEXT,
+    nombre_comercial TEXT,
+    fecha_inscripcion DATE,
+    fecha_inicio_actividades DATE,
+    estado VARCHAR(50),
+    condicion VARCHAR(50),
+    domicilio_fiscal TEXT,
+    sistema_emision VARCHAR(100),
+    actividad_comercio_exterior VARCHAR(100),
+    sistema_contabilidad VARCHAR(100),
+    emisor_electronico_desde DATE,
+    afiliado_ple DATE,
+    -- domicilio_fiscal separado con models.ParseDomicilio; ubigeo se une con empresas_sunat.ubigeo
+    tipo_via VARCHAR(10),
+    nombre_via TEXT,
+    numero VARCHAR(20),
+    interior VARCHAR(20),
+    manzana VARCHAR(20),
+    lote VARCHAR(20),
+    tipo_urbanizacion VARCHAR(10),
+    urbanizacion TEXT,
+    departamento VARCHAR(50),
+    provincia VARCHAR(50),
+    distrito VARCHAR(50),
+    ubigeo VARCHAR(6),                          -- NULL si el distrito no está en pkg/ubigeo
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+-- Tabla para actividades económicas (relación muchos a muchos)
+CREATE TABLE ruc_actividades_economicas (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    actividad_economica TEXT NOT NULL,          -- fila tal como la muestra la ficha
+    tipo VARCHAR(20) NOT NULL,                  -- principal o secundaria
+    orden SMALLINT NOT NULL DEFAULT 0,          -- 1 o 2 en las secundarias
+    codigo_ciiu VARCHAR(5) NOT NULL,
+    revision_ciiu SMALLINT NOT NULL,            -- 3 o 4
+    descripcion TEXT,
+    seccion_ciiu CHAR(1),                       -- NULL si el código no está en pkg/ciiu
+    division_ciiu CHAR(2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla para comprobantes de pago (relación muchos a muchos)
+CREATE TABLE ruc_comprobantes_pago (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    comprobante_pago VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla para sistemas de emisión electrónica (relación muchos a muchos)
+CREATE TABLE ruc_sistemas_emision_electronica (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    sistema_emision VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla para comprobantes electrónicos (relación muchos a muchos)
+CREATE TABLE ruc_comprobantes_electronicos (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    comprobante_electronico VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tabla para padrones (relación muchos a muchos)
+CREATE TABLE ruc_padrones (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    padron VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Estado de cada consulta adicional en cada consulta del RUC
+CREATE TABLE ruc_consulta_secciones (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    seccion VARCHAR(50) NOT NULL,
+    estado VARCHAR(20) NOT NULL CHECK (estado IN ('consultada', 'no_solicitada', 'no_disponible')),
+    datos_consulta_id BIGINT REFERENCES ruc_consultas(id), -- consulta que guardó los datos; NULL si no hay
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (consulta_id, seccion)
+);
+
+-- RUCs que SUNAT informó como no registrados; main.sh los excluye de los lotes
+CREATE TABLE ruc_inexistentes (
+    ruc VARCHAR(11) PRIMARY KEY,
+    fecha_deteccion TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    fecha_ultima_consulta TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    veces_consultado INTEGER NOT NULL DEFAULT 1
+);
+
+-- ====================================
+-- INFORMACIÓN HISTÓRICA
+-- ====================================
+
+CREATE TABLE ruc_informacion_historica (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+CREATE TABLE ruc_razones_sociales_historicas (
+    id BIGSERIAL PRIMARY KEY,
+    informacion_historica_id BIGINT NOT NULL REFERENCES ruc_informacion_historica(id) ON DELETE CASCADE,
+    nombre TEXT,
+    fecha_de_baja DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (informacion_historica_id, nombre, fecha_de_baja)
+);
+
+CREATE TABLE ruc_condiciones_historicas (
+    id BIGSERIAL PRIMARY KEY,
+    informacion_historica_id BIGINT NOT NULL REFERENCES ruc_informacion_historica(id) ON DELETE CASCADE,
+    condicion VARCHAR(50),
+    desde DATE,
+    hasta DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (informacion_historica_id, condicion, desde)
+);
+
+CREATE TABLE ruc_domicilios_fiscales_historicos (
+    id BIGSERIAL PRIMARY KEY,
+    informacion_historica_id BIGINT NOT NULL REFERENCES ruc_informacion_historica(id) ON DELETE CASCADE,
+    direccion TEXT,
+    fecha_de_baja DATE,
+    -- direccion separado con models.ParseDomicilio; ubigeo se une con empresas_sunat.ubigeo
+    tipo_via VARCHAR(10),
+    nombre_via TEXT,
+    numero VARCHAR(20),
+    interior VARCHAR(20),
+    manzana VARCHAR(20),
+    lote VARCHAR(20),
+    tipo_urbanizacion VARCHAR(10),
+    urbanizacion TEXT,
+    departamento VARCHAR(50),
+    provincia VARCHAR(50),
+    distrito VARCHAR(50),
+    ubigeo VARCHAR(6),                          -- NULL si el distrito no está en pkg/ubigeo
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (informacion_historica_id, direccion, fecha_de_baja)
+);
+
+-- ====================================
+-- DEUDA COACTIVA
+-- ====================================
+
+CREATE TABLE ruc_deuda_coactiva (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    total_deuda DECIMAL(15,2),
+    moneda CHAR(3) NOT NULL DEFAULT 'PEN',
+    cantidad_documentos INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+-- Sin clave natural: SUNAT no muestra el número de documento de cada deuda
+CREATE TABLE ruc_detalle_deudas (
+    id BIGSERIAL PRIMARY KEY,
+    deuda_coactiva_id BIGINT NOT NULL REFERENCES ruc_deuda_coactiva(id) ON DELETE CASCADE,
+    monto DECIMAL(15,2),
+    moneda CHAR(3) NOT NULL DEFAULT 'PEN',
+    periodo_tributario DATE,                    -- primer día del mes (models.Periodo)
+    fecha_inicio_cobranza DATE,
+    entidad VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE ruc_deuda_filas_invalidas (
+    id BIGSERIAL PRIMARY KEY,
+    deuda_coactiva_id BIGINT NOT NULL REFERENCES ruc_deuda_coactiva(id) ON DELETE CASCADE,
+    fila INTEGER,
+    texto TEXT,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ====================================
+-- OMISIONES TRIBUTARIAS
+-- ====================================
+
+CREATE TABLE ruc_omisiones_tributarias (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    tiene_omisiones BOOLEAN,
+    cantidad_omisiones INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+CREATE TABLE ruc_omisiones (
+    id BIGSERIAL PRIMARY KEY,
+    omisiones_tributarias_id BIGINT NOT NULL REFERENCES ruc_omisiones_tributarias(id) ON DELETE CASCADE,
+    periodo DATE,                               -- primer día del mes (models.Periodo)
+    tributo VARCHAR(100),
+    tipo_declaracion VARCHAR(100),
+    fecha_vencimiento DATE,
+    estado VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (omisiones_tributarias_id, periodo, tributo)
+);
+
+-- ====================================
+-- CANTIDAD DE TRABAJADORES
+-- ====================================
+
+CREATE TABLE ruc_cantidad_trabajadores (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+CREATE TABLE ruc_periodos_disponibles_trabajadores (
+    id BIGSERIAL PRIMARY KEY,
+    cantidad_trabajadores_id BIGINT NOT NULL REFERENCES ruc_cantidad_trabajadores(id) ON DELETE CASCADE,
+    periodo DATE,                               -- primer día del mes (models.Periodo)
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (cantidad_trabajadores_id, periodo)
+);
+
+CREATE TABLE ruc_detalle_trabajadores (
+    id BIGSERIAL PRIMARY KEY,
+    cantidad_trabajadores_id BIGINT NOT NULL REFERENCES ruc_cantidad_trabajadores(id) ON DELETE CASCADE,
+    periodo DATE,                               -- primer día del mes (models.Periodo)
+    cantidad_trabajadores INTEGER,
+    cantidad_prestadores_servicio INTEGER,
+    cantidad_pensionistas INTEGER,
+    total INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (cantidad_trabajadores_id, periodo)
+);
+
+-- ====================================
+-- ACTAS PROBATORIAS
+-- ====================================
+
+CREATE TABLE ruc_actas_probatorias (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    tiene_actas BOOLEAN,
+    cantidad_actas INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+CREATE TABLE ruc_actas (
+    id BIGSERIAL PRIMARY KEY,
+    actas_probatorias_id BIGINT NOT NULL REFERENCES ruc_actas_probatorias(id) ON DELETE CASCADE,
+    numero_acta VARCHAR(50),
+    fecha_acta DATE,
+    lugar_intervencion TEXT,
+    articulo_numeral VARCHAR(100),
+    descripcion_infraccion TEXT,
+    numero_ri_roz VARCHAR(50),
+    tipo_ri_roz VARCHAR(50),
+    acta_reconocimiento VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (actas_probatorias_id, numero_acta)
+);
+
+-- ====================================
+-- FACTURAS FÍSICAS
+-- ====================================
+
+CREATE TABLE ruc_facturas_fisicas (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    tiene_autorizacion BOOLEAN,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+CREATE TABLE ruc_facturas_autorizadas (
+    id BIGSERIAL PRIMARY KEY,
+    facturas_fisicas_id BIGINT NOT NULL REFERENCES ruc_facturas_fisicas(id) ON DELETE CASCADE,
+    numero_autorizacion VARCHAR(50),
+    fecha_autorizacion DATE,
+    tipo_comprobante VARCHAR(100),
+    serie VARCHAR(20),
+    numero_inicial VARCHAR(20),
+    numero_final VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (facturas_fisicas_id, numero_autorizacion, serie, numero_inicial)
+);
+
+CREATE TABLE ruc_facturas_canceladas_bajas (
+    id BIGSERIAL PRIMARY KEY,
+    facturas_fisicas_id BIGINT NOT NULL REFERENCES ruc_facturas_fisicas(id) ON DELETE CASCADE,
+    numero_autorizacion VARCHAR(50),
+    fecha_autorizacion DATE,
+    tipo_comprobante VARCHAR(100),
+    serie VARCHAR(20),
+    numero_inicial VARCHAR(20),
+    numero_final VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (facturas_fisicas_id, numero_autorizacion, serie, numero_inicial)
+);
+
+-- ====================================
+-- REACTIVA PERÚ
+-- ====================================
+
+CREATE TABLE ruc_reactiva_peru (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    razon_social TEXT,
+    tiene_deuda_coactiva BOOLEAN,
+    fecha_actualizacion DATE,
+    referencia_legal TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+-- ====================================
+-- PROGRAMA COVID-19
+-- ====================================
+
+CREATE TABLE ruc_programa_covid19 (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    razon_social TEXT,
+    participa_programa BOOLEAN,
+    tiene_deuda_coactiva BOOLEAN,
+    fecha_actualizacion DATE,
+    base_legal TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+-- ====================================
+-- REPRESENTANTES LEGALES
+-- ====================================
+
+CREATE TABLE ruc_representantes_legales (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+-- Personas naturales que aparecen como representantes, una por documento;
+-- se vinculan con cada RUC mediante ruc_representantes.persona_id
+CREATE TABLE personas (
+    id BIGSERIAL PRIMARY KEY,
+    tipo_documento VARCHAR(20) NOT NULL,        -- DNI, CE, PASAPORTE, ... (pkg/documento)
+    numero_documento VARCHAR(15) NOT NULL,
+    nombre_completo TEXT,
+    apellidos TEXT,
+    nombres TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tipo_documento, numero_documento)
+);
+
+CREATE TABLE ruc_representantes (
+    id BIGSERIAL PRIMARY KEY,
+    representantes_legales_id BIGINT NOT NULL REFERENCES ruc_representantes_legales(id) ON DELETE CASCADE,
+    persona_id BIGINT REFERENCES personas(id),  -- NULL si el tipo de documento no se reconoce
+    tipo_documento VARCHAR(20),
+    numero_documento VARCHAR(20),
+    nombre_completo TEXT,
+    apellidos TEXT,
+    nombres TEXT,
+    cargo VARCHAR(30),                          -- vocabulario de models.Cargo: GERENTE GENERAL, APODERADO, ...
+    cargo_sunat VARCHAR(100),                   -- texto original de SUNAT
+    fecha_desde DATE,
+    fecha_hasta DATE,
+    vigente BOOLEAN,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (representantes_legales_id, tipo_documento, numero_documento, cargo_sunat)
+);
+
+-- ====================================
+-- ESTABLECIMIENTOS ANEXOS
+-- ====================================
+
+CREATE TABLE ruc_establecimientos_anexos (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL UNIQUE REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    huella CHAR(64) NOT NULL,                   -- SHA-256 de la sección
+    cantidad_anexos INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ruc_id, huella)
+);
+
+CREATE TABLE ruc_establecimientos (
+    id BIGSERIAL PRIMARY KEY,
+    establecimientos_anexos_id BIGINT NOT NULL REFERENCES ruc_establecimientos_anexos(id) ON DELETE CASCADE,
+    codigo VARCHAR(20),
+    tipo_establecimiento VARCHAR(100),
+    direccion TEXT,
+    actividad_economica TEXT,
+    -- direccion separado con models.ParseDomicilio; ubigeo se une con empresas_sunat.ubigeo
+    tipo_via VARCHAR(10),
+    nombre_via TEXT,
+    numero VARCHAR(20),
+    interior VARCHAR(20),
+    manzana VARCHAR(20),
+    lote VARCHAR(20),
+    tipo_urbanizacion VARCHAR(10),
+    urbanizacion TEXT,
+    departamento VARCHAR(50),
+    provincia VARCHAR(50),
+    distrito VARCHAR(50),
+    ubigeo VARCHAR(6),                          -- NULL si el distrito no está en pkg/ubigeo
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (establecimientos_anexos_id, codigo)
+);
+
+-- ====================================
+-- CAMBIOS ENTRE CONSULTAS
+-- ====================================
+
+-- Diferencias de cada consulta con la anterior del mismo RUC (pkg/diff)
+CREATE TABLE ruc_cambios (
+    id BIGSERIAL PRIMARY KEY,
+    ruc_id BIGINT NOT NULL REFERENCES ruc_informacion_basica(id) ON DELETE CASCADE,
+    consulta_anterior_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    consulta_id BIGINT NOT NULL REFERENCES ruc_consultas(id) ON DELETE CASCADE,
+    tipo VARCHAR(30) NOT NULL,                  -- diff.Tipo: estado, condicion, deuda_nueva, representante_cese, ...
+    clave TEXT,                                 -- fila afectada: periodo y tributo, código, documento y cargo, ...
+    anterior TEXT,
+    nuevo TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ====================================
+-- ÍNDICES PARA OPTIMIZACIÓN
+-- ====================================
+
+-- Índices principales
+CREATE INDEX idx_ruc_informacion_basica_ruc ON ruc_informacion_basica(ruc);
+CREATE INDEX idx_ruc_informacion_basica_estado ON ruc_informacion_basica(estado);
+CREATE INDEX idx_ruc_informacion_basica_condicion ON ruc_informacion_basica(condicion);
+CREATE INDEX idx_ruc_informacion_basica_ubigeo ON ruc_informacion_basica(ubigeo);
+CREATE INDEX idx_ruc_informacion_basica_documento ON ruc_informacion_basica(tipo_documento, numero_documento);
+CREATE INDEX idx_ruc_informacion_basica_razon_social ON ruc_informacion_basica USING gin(to_tsvector('spanish', razon_social));
+
+-- Índices de relaciones
+CREATE INDEX idx_ruc_actividades_economicas_ruc_id ON ruc_actividades_economicas(ruc_id);
+CREATE INDEX idx_ruc_actividades_economicas_ciiu ON ruc_actividades_economicas(revision_ciiu, codigo_ciiu);
+CREATE INDEX idx_ruc_actividades_economicas_division ON ruc_actividades_economicas(revision_ciiu, division_ciiu);
+CREATE INDEX idx_ruc_comprobantes_pago_ruc_id ON ruc_comprobantes_pago(ruc_id);
+CREATE INDEX idx_ruc_sistemas_emision_electronica_ruc_id ON ruc_sistemas_emision_electronica(ruc_id);
+CREATE INDEX idx_ruc_comprobantes_electronicos_ruc_id ON ruc_comprobantes_electronicos(ruc_id);
+CREATE INDEX idx_ruc_padrones_ruc_id ON ruc_padrones(ruc_id);
+CREATE INDEX idx_ruc_consultas_ruc_id ON ruc_consultas(ruc_id, fecha_consulta);
+CREATE INDEX idx_ruc_actividades_economicas_consulta_id ON ruc_actividades_economicas(consulta_id);
+CREATE INDEX idx_ruc_comprobantes_pago_consulta_id ON ruc_comprobantes_pago(consulta_id);
+CREATE INDEX idx_ruc_sistemas_emision_electronica_consulta_id ON ruc_sistemas_emision_electronica(consulta_id);
+CREATE INDEX idx_ruc_comprobantes_electronicos_consulta_id ON ruc_comprobantes_electronicos(consulta_id);
+CREATE INDEX idx_ruc_padrones_consulta_id ON ruc_padrones(consulta_id);
+CREATE INDEX idx_ruc_consulta_secciones_datos ON ruc_consulta_secciones(datos_consulta_id);
+CREATE INDEX idx_ruc_establecimientos_ubigeo ON ruc_establecimientos(ubigeo);
+
+-- Índices de fechas
+CREATE INDEX idx_ruc_consultas_fecha_consulta ON ruc_consultas(fecha_consulta);
+CREATE INDEX idx_ruc_informacion_historica_ruc_id ON ruc_informacion_historica(ruc_id);
+
+-- Índices de deuda
+CREATE INDEX idx_ruc_deuda_coactiva_ruc_id ON ruc_deuda_coactiva(ruc_id);
+CREATE INDEX idx_ruc_deuda_coactiva_total_deuda ON ruc_deuda_coactiva(total_deuda);
+CREATE INDEX idx_ruc_detalle_deudas_periodo_tributario ON ruc_detalle_deudas(periodo_tributario);
+
+-- Índices de omisiones
+CREATE INDEX idx_ruc_omisiones_periodo ON ruc_omisiones(periodo);
+
+-- Índices de trabajadores
+CREATE INDEX idx_ruc_detalle_trabajadores_periodo ON ruc_detalle_trabajadores(periodo);
+CREATE INDEX idx_ruc_detalle_trabajadores_total ON ruc_detalle_trabajadores(total);
+
+-- Índices de cambios
+CREATE INDEX idx_ruc_cambios_ruc_id ON ruc_cambios(ruc_id, consulta_id);
+CREATE INDEX idx_ruc_cambios_consulta_id ON ruc_cambios(consulta_id);
+CREATE INDEX idx_ruc_cambios_tipo ON ruc_cambios(tipo);
+
+-- Índices de representantes
+CREATE INDEX idx_ruc_representantes_numero_documento ON ruc_representantes(numero_documento);
+CREATE INDEX idx_ruc_representantes_vigente ON ruc_representantes(vigente);
+CREATE INDEX idx_ruc_representantes_persona_id ON ruc_representantes(persona_id);
+CREATE INDEX idx_ruc_representantes_cargo ON ruc_representantes(cargo);
+CREATE INDEX idx_personas_apellidos ON personas(apellidos);
+
+-- ====================================
+-- VISTAS DE LA SITUACIÓN ACTUAL
+-- ====================================
+
+-- Última consulta de cada RUC
+CREATE VIEW ruc_consulta_actual AS
+SELECT DISTINCT ON (ruc_id) ruc_id, id AS consulta_id, ficha_consulta_id, fecha_consulta
+FROM ruc_consultas
+ORDER BY ruc_id, fecha_consulta DESC, id DESC;
+
+-- Listas de la ficha de la última consulta
+CREATE VIEW ruc_actividades_economicas_actual AS
+SELECT t.* FROM ruc_actividades_economicas t
+JOIN ruc_consulta_actual ca ON ca.ficha_consulta_id = t.consulta_id;
+
+CREATE VIEW ruc_comprobantes_pago_actual AS
+SELECT t.* FROM ruc_comprobantes_pago t
+JOIN ruc_consulta_actual ca ON ca.ficha_consulta_id = t.consulta_id;
+
+CREATE VIEW ruc_sistemas_emision_electronica_actual AS
+SELECT t.* FROM ruc_sistemas_emision_electronica t
+JOIN ruc_consulta_actual ca ON ca.ficha_consulta_id = t.consulta_id;
+
+CREATE VIEW ruc_comprobantes_electronicos_actual AS
+SELECT t.* FROM ruc_comprobantes_electronicos t
+JOIN ruc_consulta_actual ca ON ca.ficha_consulta_id = t.consulta_id;
+
+CREATE VIEW ruc_padrones_actual AS
+SELECT t.* FROM ruc_padrones t
+JOIN ruc_consulta_actual ca ON ca.ficha_consulta_id = t.consulta_id;
+
+-- Consultas adicionales: la última consulta que guardó cada sección, aunque
+-- consultas posteriores no la hayan pedido. Las filas de detalle se unen por
+-- el id de estas vistas, p. ej. ruc_detalle_deudas.deuda_coactiva_id.
+CREATE VIEW ruc_informacion_historica_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_informacion_historica t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'informacion_historica' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_deuda_coactiva_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_deuda_coactiva t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'deuda_coactiva' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_omisiones_tributarias_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_omisiones_tributarias t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'omisiones_tributarias' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_cantidad_trabajadores_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_cantidad_trabajadores t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'cantidad_trabajadores' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_actas_probatorias_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_actas_probatorias t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'actas_probatorias' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_facturas_fisicas_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_facturas_fisicas t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'facturas_fisicas' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_reactiva_peru_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_reactiva_peru t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'reactiva_peru' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_programa_covid19_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_programa_covid19 t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'garantias_covid19' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_representantes_legales_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_representantes_legales t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'representantes_legales' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+CREATE VIEW ruc_establecimientos_anexos_actual AS
+SELECT DISTINCT ON (t.ruc_id) t.*
+FROM ruc_establecimientos_anexos t
+JOIN ruc_consulta_secciones cs ON cs.seccion = 'establecimientos_anexos' AND cs.datos_consulta_id = t.consulta_id
+JOIN ruc_consultas c ON c.id = cs.consulta_id
+ORDER BY t.ruc_id, c.fecha_consulta DESC, c.id DESC;
+
+-- Empresas en que aparece cada persona, con su cargo en la última consulta de cada RUC
+CREATE VIEW personas_empresas AS
+SELECT p.id AS persona_id,
+       p.tipo_documento,
+       p.numero_documento,
+       p.nombre_completo,
+       ib.ruc,
+       ib.razon_social,
+       r.cargo,
+       r.fecha_desde,
+       r.fecha_hasta,
+       r.vigente
+FROM personas p
+JOIN ruc_representantes r ON r.persona_id = p.id
+JOIN ruc_representantes_legales_actual rl ON rl.id = r.representantes_legales_id
+JOIN ruc_informacion_basica ib ON ib.id = rl.ruc_id;
+
+-- Consulta de un RUC vigente en una fecha: la última hecha hasta ese día.
+-- SELECT f.* FROM ruc_consultas c JOIN ruc_fichas f ON f.consulta_id = c.ficha_consulta_id
+-- WHERE c.id = ruc_consulta_al('20606316977', '2025-08-12');
+CREATE OR REPLACE FUNCTION ruc_consulta_al(p_ruc VARCHAR, p_fecha DATE)
+RETURNS BIGINT AS $$
+    SELECT c.id
+    FROM ruc_consultas c
+    JOIN ruc_informacion_basica ib ON ib.id = c.ruc_id
+    WHERE ib.ruc = p_ruc AND c.fecha_consulta < p_fecha + 1
+    ORDER BY c.fecha_consulta DESC, c.id DESC
+    LIMIT 1;
+$$ LANGUAGE sql STABLE;
+
+-- ====================================
+-- FUNCIONES DE UTILIDAD
+-- ====================================
+
+-- Función para actualizar timestamps automáticamente
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Aplicar trigger a la tabla principal
+CREATE TRIGGER update_ruc_informacion_basica_updated_at 
+    BEFORE UPDATE ON ruc_informacion_basica 
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- ====================================
+-- COMENTARIOS EN TABLAS
+-- ====================================
+
+COMMENT ON TABLE ruc_informacion_basica IS 'Información básica del RUC según su última consulta';
+COMMENT ON TABLE ruc_consultas IS 'Registro de consultas realizadas al RUC; cada una es una foto inmutable';
+COMMENT ON TABLE ruc_fichas IS 'Cada ficha RUC distinta que SUNAT mostró, guardada por la primera consulta que la vio';
+COMMENT ON TABLE ruc_consulta_secciones IS 'Estado de cada consulta adicional (consultada, no_solicitada, no_disponible)';
+COMMENT ON TABLE ruc_cambios IS 'Cambios de cada consulta respecto de la anterior del mismo RUC';
+COMMENT ON TABLE ruc_inexistentes IS 'RUCs que SUNAT informó como no registrados, con la fecha de detección';
+COMMENT ON TABLE ruc_informacion_historica IS 'Información histórica de cambios del RUC';
+COMMENT ON TABLE ruc_deuda_coactiva IS 'Información de deuda en cobranza coactiva';
+COMMENT ON TABLE ruc_deuda_filas_invalidas IS 'Filas de deuda coactiva cuyo monto no se pudo interpretar; no suman en total_deuda';
+COMMENT ON TABLE ruc_omisiones_tributarias IS 'Información de omisiones tributarias';
+COMMENT ON TABLE ruc_cantidad_trabajadores IS 'Información de cantidad de trabajadores por período';
+COMMENT ON TABLE ruc_actas_probatorias IS 'Información de actas probatorias del contribuyente';
+COMMENT ON TABLE ruc_facturas_fisicas IS 'Información de facturas físicas autorizadas';
+COMMENT ON TABLE ruc_reactiva_peru IS 'Información del programa Reactiva Perú';
+COMMENT ON TABLE ruc_programa_covid19 IS 'Información del programa de garantías COVID-19';
+COMMENT ON TABLE ruc_representantes_legales IS 'Información de representantes legales';
+COMMENT ON TABLE personas IS 'Personas que aparecen como representantes legales, identificadas por su documento';
+COMMENT ON TABLE ruc_establecimientos_anexos IS 'Información de establecimientos anexos';
\ No newline at end of file
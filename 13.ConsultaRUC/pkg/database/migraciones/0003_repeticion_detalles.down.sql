@@ -0,0 +1,29 @@
+-- Deshace la migración 3. Las filas repetidas (repeticion > 1) se eliminan:
+-- la clave natural de la migración 2 no las admite.
+
+DO $$
+DECLARE
+    tabla TEXT;
+BEGIN
+    FOREACH tabla IN ARRAY ARRAY[
+        'ruc_razones_sociales_historicas', 'ruc_condiciones_historicas', 'ruc_domicilios_fiscales_historicos',
+        'ruc_omisiones', 'ruc_periodos_disponibles_trabajadores', 'ruc_detalle_trabajadores', 'ruc_actas',
+        'ruc_facturas_autorizadas', 'ruc_facturas_canceladas_bajas', 'ruc_representantes', 'ruc_establecimientos'
+    ] LOOP
+        EXECUTE format('DELETE FROM %I WHERE repeticion > 1', tabla);
+        EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', tabla, tabla || '_clave');
+        EXECUTE format('ALTER TABLE %I DROP COLUMN repeticion', tabla);
+    END LOOP;
+END $$;
+
+ALTER TABLE ruc_razones_sociales_historicas ADD UNIQUE (informacion_historica_id, nombre, fecha_de_baja);
+ALTER TABLE ruc_condiciones_historicas ADD UNIQUE (informacion_historica_id, condicion, desde);
+ALTER TABLE ruc_domicilios_fiscales_historicos ADD UNIQUE (informacion_historica_id, direccion, fecha_de_baja);
+ALTER TABLE ruc_omisiones ADD UNIQUE (omisiones_tributarias_id, periodo, tributo);
+ALTER TABLE ruc_periodos_disponibles_trabajadores ADD UNIQUE (cantidad_trabajadores_id, periodo);
+ALTER TABLE ruc_detalle_trabajadores ADD UNIQUE (cantidad_trabajadores_id, periodo);
+ALTER TABLE ruc_actas ADD UNIQUE (actas_probatorias_id, numero_acta);
+ALTER TABLE ruc_facturas_autorizadas ADD UNIQUE (facturas_fisicas_id, numero_autorizacion, serie, numero_inicial);
+ALTER TABLE ruc_facturas_canceladas_bajas ADD UNIQUE (facturas_fisicas_id, numero_autorizacion, serie, numero_inicial);
+ALTER TABLE ruc_representantes ADD UNIQUE (representantes_legales_id, tipo_documento, numero_documento, cargo_sunat);
+ALTER TABLE ruc_establecimientos ADD UNIQUE (establecimientos_anexos_id, codigo);
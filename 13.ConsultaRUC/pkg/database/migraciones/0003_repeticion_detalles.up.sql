@@ -0,0 +1,47 @@
+-- Migración 3: una fila de detalle que repite su clave natural en la página
+-- de SUNAT (dos omisiones del mismo periodo y tributo, ...) se guarda con
+-- repeticion 2, 3, ... en lugar de descartarse, para que la sección guardada
+-- coincida con su huella.
+
+DO $$
+DECLARE
+    tabla TEXT;
+    restriccion TEXT;
+BEGIN
+    FOREACH tabla IN ARRAY ARRAY[
+        'ruc_razones_sociales_historicas', 'ruc_condiciones_historicas', 'ruc_domicilios_fiscales_historicos',
+        'ruc_omisiones', 'ruc_periodos_disponibles_trabajadores', 'ruc_detalle_trabajadores', 'ruc_actas',
+        'ruc_facturas_autorizadas', 'ruc_facturas_canceladas_bajas', 'ruc_representantes', 'ruc_establecimientos'
+    ] LOOP
+        -- Cada tabla tiene una sola UNIQUE, la de su clave natural, con el nombre que le dio PostgreSQL
+        FOR restriccion IN
+            SELECT conname FROM pg_constraint WHERE conrelid = tabla::regclass AND contype = 'u'
+        LOOP
+            EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', tabla, restriccion);
+        END LOOP;
+        EXECUTE format('ALTER TABLE %I ADD COLUMN repeticion SMALLINT NOT NULL DEFAULT 1', tabla);
+    END LOOP;
+END $$;
+
+ALTER TABLE ruc_razones_sociales_historicas ADD CONSTRAINT ruc_razones_sociales_historicas_clave
+    UNIQUE (informacion_historica_id, nombre, fecha_de_baja, repeticion);
+ALTER TABLE ruc_condiciones_historicas ADD CONSTRAINT ruc_condiciones_historicas_clave
+    UNIQUE (informacion_historica_id, condicion, desde, repeticion);
+ALTER TABLE ruc_domicilios_fiscales_historicos ADD CONSTRAINT ruc_domicilios_fiscales_historicos_clave
+    UNIQUE (informacion_historica_id, direccion, fecha_de_baja, repeticion);
+ALTER TABLE ruc_omisiones ADD CONSTRAINT ruc_omisiones_clave
+    UNIQUE (omisiones_tributarias_id, periodo, tributo, repeticion);
+ALTER TABLE ruc_periodos_disponibles_trabajadores ADD CONSTRAINT ruc_periodos_disponibles_trabajadores_clave
+    UNIQUE (cantidad_trabajadores_id, periodo, repeticion);
+ALTER TABLE ruc_detalle_trabajadores ADD CONSTRAINT ruc_detalle_trabajadores_clave
+    UNIQUE (cantidad_trabajadores_id, periodo, repeticion);
+ALTER TABLE ruc_actas ADD CONSTRAINT ruc_actas_clave
+    UNIQUE (actas_probatorias_id, numero_acta, repeticion);
+ALTER TABLE ruc_facturas_autorizadas ADD CONSTRAINT ruc_facturas_autorizadas_clave
+    UNIQUE (facturas_fisicas_id, numero_autorizacion, serie, numero_inicial, repeticion);
+ALTER TABLE ruc_facturas_canceladas_bajas ADD CONSTRAINT ruc_facturas_canceladas_bajas_clave
+    UNIQUE (facturas_fisicas_id, numero_autorizacion, serie, numero_inicial, repeticion);
+ALTER TABLE ruc_representantes ADD CONSTRAINT ruc_representantes_clave
+    UNIQUE (representantes_legales_id, tipo_documento, numero_documento, cargo_sunat, repeticion);
+ALTER TABLE ruc_establecimientos ADD CONSTRAINT ruc_establecimientos_clave
+    UNIQUE (establecimientos_anexos_id, codigo, repeticion);
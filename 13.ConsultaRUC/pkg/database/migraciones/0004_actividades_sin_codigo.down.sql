@@ -0,0 +1,9 @@
+-- Deshace la migración 4: las actividades sin código vuelven a los valores vacíos.
+
+UPDATE ruc_actividades_economicas SET tipo = '', codigo_ciiu = '', revision_ciiu = 0
+WHERE codigo_ciiu IS NULL;
+
+ALTER TABLE ruc_actividades_economicas
+    ALTER COLUMN tipo SET NOT NULL,
+    ALTER COLUMN codigo_ciiu SET NOT NULL,
+    ALTER COLUMN revision_ciiu SET NOT NULL;
@@ -0,0 +1,11 @@
+-- Migración 4: una actividad económica que no sigue el formato de la ficha se
+-- guarda con tipo, codigo_ciiu y revision_ciiu en NULL, para que no aparezca
+-- con un código vacío en el índice (revision_ciiu, codigo_ciiu) ni en los joins.
+
+ALTER TABLE ruc_actividades_economicas
+    ALTER COLUMN tipo DROP NOT NULL,
+    ALTER COLUMN codigo_ciiu DROP NOT NULL,
+    ALTER COLUMN revision_ciiu DROP NOT NULL;
+
+UPDATE ruc_actividades_economicas SET tipo = NULL, codigo_ciiu = NULL, revision_ciiu = NULL
+WHERE codigo_ciiu = '';
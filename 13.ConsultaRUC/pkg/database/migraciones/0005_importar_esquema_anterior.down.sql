@@ -0,0 +1,3 @@
+-- La importación no se deshace: los datos copiados son consultas como las
+-- del scraper y el esquema ruc_anterior ya no existe. Bajar de la versión 2
+-- los elimina junto con el resto del esquema.
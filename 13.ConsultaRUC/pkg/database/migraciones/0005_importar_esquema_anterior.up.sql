@@ -0,0 +1,401 @@
+-- Migración 5: copia al esquema nuevo los datos de una base creada con
+-- database/prueba.sql, que la migración 2 apartó en el esquema ruc_anterior.
+-- En una base creada con las migraciones no hace nada.
+--
+-- El esquema anterior guardaba solo la última ficha de cada RUC (con sus
+-- listas) y, por cada consulta, una fila nueva de cada consulta adicional con
+-- el created_at de su transacción, igual al de su fila en ruc_consultas:
+--   * cada consulta anterior es una consulta con su mismo id; una sección sin
+--     consulta con el mismo created_at recibe una consulta propia;
+--   * la ficha y sus listas quedan en la última consulta del RUC, y todas las
+--     consultas importadas apuntan a ella con ficha_consulta_id;
+--   * cada sección queda 'consultada' en su consulta, conservando su id; las
+--     consultas en que no se guardó una sección no tienen fila para ella en
+--     ruc_consulta_secciones, porque el esquema anterior no registraba por qué;
+--   * las huellas son de la importación ('ruc_anterior:tabla:id') y nunca
+--     coinciden con las del scraper, así que la próxima consulta guarda todo;
+--   * los textos se convierten como lo hace el scraper (fechas dd/mm/aaaa,
+--     periodos de models.ParsePeriodo, documentos de pkg/documento, actividades
+--     de models.ParseActividadEconomica); lo que no se reconoce queda en NULL.
+--     cargo, apellidos, nombres y las partes de los domicilios quedan en NULL
+--     hasta la próxima consulta; cargo_sunat conserva el cargo anterior.
+--     Los booleanos y cantidades que el scraper siempre escribía pasan con
+--     COALESCE a false o 0, por si alguna fila se cargó a mano.
+-- Al terminar se elimina el esquema ruc_anterior.
+
+CREATE FUNCTION pg_temp.fecha_anterior(texto TEXT) RETURNS DATE LANGUAGE plpgsql AS $$
+BEGIN
+    IF trim(texto) ~ '^\d{2}/\d{2}/\d{4}$' THEN
+        RETURN to_date(trim(texto), 'DD/MM/YYYY');
+    END IF;
+    RETURN NULL;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END $$;
+
+CREATE FUNCTION pg_temp.periodo_anterior(texto TEXT) RETURNS DATE LANGUAGE plpgsql AS $$
+DECLARE
+    t TEXT := upper(regexp_replace(trim(texto), '\s+', ' ', 'g'));
+    m TEXT[];
+    anio INT;
+    mes INT;
+BEGIN
+    m := regexp_match(t, '^(\d{4})[-/]?(\d{2})$');
+    IF m IS NOT NULL THEN
+        anio := m[1]; mes := m[2];
+    ELSE
+        m := regexp_match(t, '^(\d{1,2})[-/](\d{4})$');
+        IF m IS NOT NULL THEN
+            anio := m[2]; mes := m[1];
+        ELSE
+            m := regexp_match(t, '^([A-Z]+)\.?[\s/-]*(\d{4})$');
+            IF m IS NULL THEN
+                RETURN NULL;
+            END IF;
+            anio := m[2];
+            mes := COALESCE(
+                array_position(ARRAY['ENERO', 'FEBRERO', 'MARZO', 'ABRIL', 'MAYO', 'JUNIO', 'JULIO',
+                    'AGOSTO', 'SETIEMBRE', 'OCTUBRE', 'NOVIEMBRE', 'DICIEMBRE'], m[1]),
+                array_position(ARRAY['ENE', 'FEB', 'MAR', 'ABR', 'MAY', 'JUN', 'JUL',
+                    'AGO', 'SET', 'OCT', 'NOV', 'DIC'], m[1]),
+                CASE WHEN m[1] IN ('SEPTIEMBRE', 'SEP') THEN 9 END);
+        END IF;
+    END IF;
+    IF mes BETWEEN 1 AND 12 AND anio >= 1900 THEN
+        RETURN make_date(anio, mes, 1);
+    END IF;
+    RETURN NULL;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END $$;
+
+-- Mismas etiquetas que documento.ParseTipo, ya simplificadas
+CREATE FUNCTION pg_temp.tipo_documento_anterior(texto TEXT) RETURNS VARCHAR LANGUAGE sql AS $$
+    SELECT CASE regexp_replace(trim(replace(translate(upper(texto), 'ÁÉÍÓÚÜ.', 'AEIOUU'), '/', ' / ')), '\s+', ' ', 'g')
+        WHEN 'DNI' THEN 'DNI'
+        WHEN 'DOC NACIONAL DE IDENTIDAD' THEN 'DNI'
+        WHEN 'DOCUMENTO NACIONAL DE IDENTIDAD' THEN 'DNI'
+        WHEN 'LIBRETA ELECTORAL' THEN 'DNI'
+        WHEN 'LIBRETA ELECTORAL / DNI' THEN 'DNI'
+        WHEN 'LE / DNI' THEN 'DNI'
+        WHEN 'CE' THEN 'CE'
+        WHEN 'CARNET DE EXTRANJERIA' THEN 'CE'
+        WHEN 'CARNE DE EXTRANJERIA' THEN 'CE'
+        WHEN 'CARNET EXTRANJERIA' THEN 'CE'
+        WHEN 'CARNET EXT' THEN 'CE'
+        WHEN 'CARNE EXT' THEN 'CE'
+        WHEN 'PASAPORTE' THEN 'PASAPORTE'
+        WHEN 'PAS' THEN 'PASAPORTE'
+        WHEN 'PASS' THEN 'PASAPORTE'
+        WHEN 'RUC' THEN 'RUC'
+        WHEN 'REG UNICO DE CONTRIBUYENTES' THEN 'RUC'
+        WHEN 'CDI' THEN 'CDI'
+        WHEN 'CED DIPLOMATICA DE IDENTIDAD' THEN 'CDI'
+        WHEN 'CEDULA DIPLOMATICA DE IDENTIDAD' THEN 'CDI'
+        WHEN 'PTP' THEN 'PTP'
+        WHEN 'PERMISO TEMPORAL DE PERMANENCIA' THEN 'PTP'
+        WHEN 'PERMISO TEMP PERMANENCIA' THEN 'PTP'
+        WHEN 'CPP' THEN 'CPP'
+        WHEN 'CARNE DE PERMISO TEMP DE PERMANENCIA' THEN 'CPP'
+        WHEN 'CARNE DE PERMISO TEMPORAL DE PERMANENCIA' THEN 'CPP'
+        WHEN 'CARNET DE PERMISO TEMPORAL DE PERMANENCIA' THEN 'CPP'
+        WHEN 'DOC TRIB NO DOM SIN RUC' THEN 'NO_DOMICILIADO'
+        WHEN 'DOCTRIBNODOMSINRUC' THEN 'NO_DOMICILIADO'
+    END
+$$;
+
+CREATE FUNCTION pg_temp.huella_anterior(tabla TEXT, id BIGINT) RETURNS CHAR(64) LANGUAGE sql AS $$
+    SELECT encode(sha256(convert_to('ruc_anterior:' || tabla || ':' || id, 'UTF8')), 'hex')
+$$;
+
+DO $$
+DECLARE
+    tabla TEXT;
+    clave TEXT;
+BEGIN
+    IF to_regnamespace('ruc_anterior') IS NULL THEN
+        RETURN;
+    END IF;
+
+    -- Ficha: el tipo de documento anterior es la fila entera "DNI 10428447 - TITULAR"
+    INSERT INTO ruc_informacion_basica (id, ruc, razon_social, tipo_contribuyente,
+        tipo_documento, numero_documento, nombre_titular, nombre_comercial,
+        fecha_inscripcion, fecha_inicio_actividades, estado, condicion, domicilio_fiscal,
+        sistema_emision, actividad_comercio_exterior, sistema_contabilidad,
+        emisor_electronico_desde, afiliado_ple, created_at, updated_at)
+    SELECT b.id, b.ruc, b.razon_social, b.tipo_contribuyente,
+        pg_temp.tipo_documento_anterior(d.m[1]),
+        CASE WHEN length(d.m[2]) <= 15 THEN d.m[2] END,
+        NULLIF(trim(d.m[3]), ''),
+        b.nombre_comercial, b.fecha_inscripcion, b.fecha_inicio_actividades, b.estado, b.condicion,
+        b.domicilio_fiscal, b.sistema_emision, b.actividad_comercio_exterior, b.sistema_contabilidad,
+        b.emisor_electronico_desde, pg_temp.fecha_anterior(b.afiliado_ple), b.created_at, b.updated_at
+    FROM ruc_anterior.ruc_informacion_basica b
+    LEFT JOIN LATERAL (SELECT regexp_match(b.tipo_documento, '^([^0-9]*)([0-9]+)\s*-\s*(.*)$') AS m) d ON true;
+
+    -- Consultas: cada una apunta a sí misma hasta saber cuál es la última del RUC
+    INSERT INTO ruc_consultas (id, ruc_id, fecha_consulta, version_api, ficha_consulta_id, created_at)
+    SELECT id, ruc_id, fecha_consulta, version_api, id, created_at FROM ruc_anterior.ruc_consultas;
+    PERFORM setval(pg_get_serial_sequence('ruc_consultas', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM ruc_consultas;
+
+    -- Secciones guardadas sin una consulta con su created_at, y RUCs sin
+    -- consultas. La CTE con nextval se evalúa una vez por fila.
+    WITH nuevas AS (
+        SELECT nextval(pg_get_serial_sequence('ruc_consultas', 'id')) AS id, ruc_id, fecha, creada
+        FROM (
+            SELECT DISTINCT s.ruc_id, COALESCE(s.created_at, CURRENT_TIMESTAMP) AS fecha, s.created_at AS creada
+            FROM (
+                SELECT ruc_id, created_at FROM ruc_anterior.ruc_informacion_historica
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_deuda_coactiva
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_omisiones_tributarias
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_cantidad_trabajadores
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_actas_probatorias
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_facturas_fisicas
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_reactiva_peru
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_programa_covid19
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_representantes_legales
+                UNION SELECT ruc_id, created_at FROM ruc_anterior.ruc_establecimientos_anexos
+            ) s
+            WHERE NOT EXISTS (SELECT 1 FROM ruc_consultas c
+                WHERE c.ruc_id = s.ruc_id AND c.created_at IS NOT DISTINCT FROM s.created_at)
+            UNION
+            SELECT b.id, COALESCE(b.updated_at, b.created_at, CURRENT_TIMESTAMP), b.updated_at
+            FROM ruc_informacion_basica b
+            WHERE NOT EXISTS (SELECT 1 FROM ruc_consultas c WHERE c.ruc_id = b.id)
+        ) sin_consulta
+    )
+    INSERT INTO ruc_consultas (id, ruc_id, fecha_consulta, ficha_consulta_id, created_at)
+    SELECT id, ruc_id, fecha, id, creada FROM nuevas;
+
+    CREATE TEMP TABLE ultima_consulta ON COMMIT DROP AS
+    SELECT DISTINCT ON (ruc_id) ruc_id, id AS consulta_id
+    FROM ruc_consultas ORDER BY ruc_id, fecha_consulta DESC, id DESC;
+
+    UPDATE ruc_consultas c SET ficha_consulta_id = u.consulta_id
+    FROM ultima_consulta u WHERE u.ruc_id = c.ruc_id;
+
+    INSERT INTO ruc_fichas (consulta_id, ruc_id, huella, razon_social, tipo_contribuyente,
+        tipo_documento, numero_documento, nombre_titular, nombre_comercial,
+        fecha_inscripcion, fecha_inicio_actividades, estado, condicion, domicilio_fiscal,
+        sistema_emision, actividad_comercio_exterior, sistema_contabilidad,
+        emisor_electronico_desde, afiliado_ple)
+    SELECT u.consulta_id, b.id, pg_temp.huella_anterior('ruc_fichas', u.consulta_id), b.razon_social, b.tipo_contribuyente,
+        b.tipo_documento, b.numero_documento, b.nombre_titular, b.nombre_comercial,
+        b.fecha_inscripcion, b.fecha_inicio_actividades, b.estado, b.condicion, b.domicilio_fiscal,
+        b.sistema_emision, b.actividad_comercio_exterior, b.sistema_contabilidad,
+        b.emisor_electronico_desde, b.afiliado_ple
+    FROM ruc_informacion_basica b JOIN ultima_consulta u ON u.ruc_id = b.id;
+
+    -- Listas de la ficha
+    INSERT INTO ruc_actividades_economicas (ruc_id, consulta_id, actividad_economica,
+        tipo, orden, codigo_ciiu, revision_ciiu, descripcion, created_at)
+    SELECT a.ruc_id, u.consulta_id, a.actividad_economica,
+        lower(p.m[1]),
+        CASE WHEN p.m IS NULL THEN 0 ELSE COALESCE(p.m[2]::SMALLINT, CASE WHEN lower(p.m[1]) = 'secundaria' THEN 1 ELSE 0 END) END,
+        p.m[4],
+        CASE WHEN p.m IS NULL THEN NULL WHEN p.m[3] IS NOT NULL THEN p.m[3]::SMALLINT WHEN length(p.m[4]) = 5 THEN 3 ELSE 4 END,
+        COALESCE(trim(p.m[5]), a.actividad_economica),
+        a.created_at
+    FROM ruc_anterior.ruc_actividades_economicas a
+    JOIN ultima_consulta u ON u.ruc_id = a.ruc_id
+    LEFT JOIN LATERAL (
+        SELECT m FROM regexp_match(regexp_replace(trim(a.actividad_economica), '\s+', ' ', 'g'),
+            '^(principal|secundaria)(?:\s+(\d))?\s*-\s*(?:ciiu\s*(?:rev\.?\s*([34])\s*)?)?(\d{4,5})\s*-\s*(.+)$', 'i') AS m
+        -- la principal no lleva número (models.ParseActividadEconomica)
+        WHERE NOT (lower(m[1]) = 'principal' AND m[2] IS NOT NULL)
+    ) p ON true
+    ORDER BY a.id;
+
+    INSERT INTO ruc_comprobantes_pago (ruc_id, consulta_id, comprobante_pago, created_at)
+    SELECT l.ruc_id, u.consulta_id, l.comprobante_pago, l.created_at
+    FROM ruc_anterior.ruc_comprobantes_pago l JOIN ultima_consulta u ON u.ruc_id = l.ruc_id ORDER BY l.id;
+
+    INSERT INTO ruc_sistemas_emision_electronica (ruc_id, consulta_id, sistema_emision, created_at)
+    SELECT l.ruc_id, u.consulta_id, l.sistema_emision, l.created_at
+    FROM ruc_anterior.ruc_sistemas_emision_electronica l JOIN ultima_consulta u ON u.ruc_id = l.ruc_id ORDER BY l.id;
+
+    INSERT INTO ruc_comprobantes_electronicos (ruc_id, consulta_id, comprobante_electronico, created_at)
+    SELECT l.ruc_id, u.consulta_id, l.comprobante_electronico, l.created_at
+    FROM ruc_anterior.ruc_comprobantes_electronicos l JOIN ultima_consulta u ON u.ruc_id = l.ruc_id ORDER BY l.id;
+
+    INSERT INTO ruc_padrones (ruc_id, consulta_id, padron, created_at)
+    SELECT l.ruc_id, u.consulta_id, l.padron, l.created_at
+    FROM ruc_anterior.ruc_padrones l JOIN ultima_consulta u ON u.ruc_id = l.ruc_id ORDER BY l.id;
+
+    -- Consultas adicionales: cada fila va a la consulta de su mismo created_at
+    INSERT INTO ruc_informacion_historica (id, ruc_id, consulta_id, huella, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_informacion_historica', t.id), t.created_at
+    FROM ruc_anterior.ruc_informacion_historica t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_razones_sociales_historicas (informacion_historica_id, nombre, fecha_de_baja, repeticion, created_at)
+    SELECT informacion_historica_id, nombre, fecha_de_baja,
+        row_number() OVER (PARTITION BY informacion_historica_id, nombre, fecha_de_baja ORDER BY id), created_at
+    FROM ruc_anterior.ruc_razones_sociales_historicas ORDER BY id;
+
+    INSERT INTO ruc_condiciones_historicas (informacion_historica_id, condicion, desde, hasta, repeticion, created_at)
+    SELECT informacion_historica_id, condicion, desde, hasta,
+        row_number() OVER (PARTITION BY informacion_historica_id, condicion, desde ORDER BY id), created_at
+    FROM ruc_anterior.ruc_condiciones_historicas ORDER BY id;
+
+    INSERT INTO ruc_domicilios_fiscales_historicos (informacion_historica_id, direccion, fecha_de_baja, repeticion, created_at)
+    SELECT informacion_historica_id, direccion, fecha_de_baja,
+        row_number() OVER (PARTITION BY informacion_historica_id, direccion, fecha_de_baja ORDER BY id), created_at
+    FROM ruc_anterior.ruc_domicilios_fiscales_historicos ORDER BY id;
+
+    INSERT INTO ruc_deuda_coactiva (id, ruc_id, consulta_id, huella, total_deuda, cantidad_documentos, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_deuda_coactiva', t.id),
+        COALESCE(t.total_deuda, 0), COALESCE(t.cantidad_documentos, 0), t.created_at
+    FROM ruc_anterior.ruc_deuda_coactiva t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_detalle_deudas (deuda_coactiva_id, monto, periodo_tributario, fecha_inicio_cobranza, entidad, created_at)
+    SELECT deuda_coactiva_id, COALESCE(monto, 0), pg_temp.periodo_anterior(periodo_tributario), fecha_inicio_cobranza, entidad, created_at
+    FROM ruc_anterior.ruc_detalle_deudas ORDER BY id;
+
+    INSERT INTO ruc_omisiones_tributarias (id, ruc_id, consulta_id, huella, tiene_omisiones, cantidad_omisiones, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_omisiones_tributarias', t.id),
+        COALESCE(t.tiene_omisiones, false), COALESCE(t.cantidad_omisiones, 0), t.created_at
+    FROM ruc_anterior.ruc_omisiones_tributarias t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_omisiones (omisiones_tributarias_id, periodo, tributo, tipo_declaracion,
+        fecha_vencimiento, estado, repeticion, created_at)
+    SELECT omisiones_tributarias_id, periodo, tributo, tipo_declaracion, fecha_vencimiento, estado,
+        row_number() OVER (PARTITION BY omisiones_tributarias_id, periodo, tributo ORDER BY id), created_at
+    FROM (SELECT id, omisiones_tributarias_id, pg_temp.periodo_anterior(periodo) AS periodo, tributo,
+              tipo_declaracion, fecha_vencimiento, estado, created_at
+          FROM ruc_anterior.ruc_omisiones) o
+    ORDER BY id;
+
+    INSERT INTO ruc_cantidad_trabajadores (id, ruc_id, consulta_id, huella, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_cantidad_trabajadores', t.id), t.created_at
+    FROM ruc_anterior.ruc_cantidad_trabajadores t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_periodos_disponibles_trabajadores (cantidad_trabajadores_id, periodo, repeticion, created_at)
+    SELECT cantidad_trabajadores_id, periodo,
+        row_number() OVER (PARTITION BY cantidad_trabajadores_id, periodo ORDER BY id), created_at
+    FROM (SELECT id, cantidad_trabajadores_id, pg_temp.periodo_anterior(periodo) AS periodo, created_at
+          FROM ruc_anterior.ruc_periodos_disponibles_trabajadores) p
+    ORDER BY id;
+
+    INSERT INTO ruc_detalle_trabajadores (cantidad_trabajadores_id, periodo, cantidad_trabajadores,
+        cantidad_prestadores_servicio, cantidad_pensionistas, total, repeticion, created_at)
+    SELECT cantidad_trabajadores_id, periodo, COALESCE(cantidad_trabajadores, 0),
+        COALESCE(cantidad_prestadores_servicio, 0), COALESCE(cantidad_pensionistas, 0), COALESCE(total, 0),
+        row_number() OVER (PARTITION BY cantidad_trabajadores_id, periodo ORDER BY id), created_at
+    FROM (SELECT id, cantidad_trabajadores_id, pg_temp.periodo_anterior(periodo) AS periodo, cantidad_trabajadores,
+              cantidad_prestadores_servicio, cantidad_pensionistas, total, created_at
+          FROM ruc_anterior.ruc_detalle_trabajadores) d
+    ORDER BY id;
+
+    INSERT INTO ruc_actas_probatorias (id, ruc_id, consulta_id, huella, tiene_actas, cantidad_actas, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_actas_probatorias', t.id),
+        COALESCE(t.tiene_actas, false), COALESCE(t.cantidad_actas, 0), t.created_at
+    FROM ruc_anterior.ruc_actas_probatorias t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_actas (actas_probatorias_id, numero_acta, fecha_acta, lugar_intervencion, articulo_numeral,
+        descripcion_infraccion, numero_ri_roz, tipo_ri_roz, acta_reconocimiento, repeticion, created_at)
+    SELECT actas_probatorias_id, numero_acta, fecha_acta, lugar_intervencion, articulo_numeral,
+        descripcion_infraccion, numero_ri_roz, tipo_ri_roz, acta_reconocimiento,
+        row_number() OVER (PARTITION BY actas_probatorias_id, numero_acta ORDER BY id), created_at
+    FROM ruc_anterior.ruc_actas ORDER BY id;
+
+    INSERT INTO ruc_facturas_fisicas (id, ruc_id, consulta_id, huella, tiene_autorizacion, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_facturas_fisicas', t.id),
+        COALESCE(t.tiene_autorizacion, false), t.created_at
+    FROM ruc_anterior.ruc_facturas_fisicas t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    FOREACH tabla IN ARRAY ARRAY['ruc_facturas_autorizadas', 'ruc_facturas_canceladas_bajas'] LOOP
+        EXECUTE format($f$
+            INSERT INTO %1$I (facturas_fisicas_id, numero_autorizacion, fecha_autorizacion, tipo_comprobante,
+                serie, numero_inicial, numero_final, repeticion, created_at)
+            SELECT facturas_fisicas_id, numero_autorizacion, fecha_autorizacion, tipo_comprobante,
+                serie, numero_inicial, numero_final,
+                row_number() OVER (PARTITION BY facturas_fisicas_id, numero_autorizacion, serie, numero_inicial ORDER BY id),
+                created_at
+            FROM ruc_anterior.%1$I ORDER BY id$f$, tabla);
+    END LOOP;
+
+    INSERT INTO ruc_reactiva_peru (id, ruc_id, consulta_id, huella, razon_social, tiene_deuda_coactiva,
+        fecha_actualizacion, referencia_legal, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_reactiva_peru', t.id), t.razon_social,
+        COALESCE(t.tiene_deuda_coactiva, false), t.fecha_actualizacion, t.referencia_legal, t.created_at
+    FROM ruc_anterior.ruc_reactiva_peru t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_programa_covid19 (id, ruc_id, consulta_id, huella, razon_social, participa_programa,
+        tiene_deuda_coactiva, fecha_actualizacion, base_legal, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_programa_covid19', t.id), t.razon_social,
+        COALESCE(t.participa_programa, false), COALESCE(t.tiene_deuda_coactiva, false), t.fecha_actualizacion, t.base_legal, t.created_at
+    FROM ruc_anterior.ruc_programa_covid19 t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_representantes_legales (id, ruc_id, consulta_id, huella, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_representantes_legales', t.id), t.created_at
+    FROM ruc_anterior.ruc_representantes_legales t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    CREATE TEMP TABLE representantes_anteriores ON COMMIT DROP AS
+    SELECT r.*, pg_temp.tipo_documento_anterior(r.tipo_documento) AS tipo
+    FROM ruc_anterior.ruc_representantes r;
+
+    -- Una persona por documento reconocido, con el nombre de su fila más reciente
+    INSERT INTO personas (tipo_documento, numero_documento, nombre_completo)
+    SELECT DISTINCT ON (tipo, numero_documento) tipo, numero_documento, nombre_completo
+    FROM representantes_anteriores
+    WHERE tipo IS NOT NULL AND numero_documento <> '' AND length(numero_documento) <= 15
+    ORDER BY tipo, numero_documento, id DESC
+    ON CONFLICT (tipo_documento, numero_documento) DO NOTHING;
+
+    INSERT INTO ruc_representantes (representantes_legales_id, persona_id, tipo_documento, numero_documento,
+        nombre_completo, cargo_sunat, fecha_desde, fecha_hasta, vigente, repeticion, created_at)
+    SELECT r.representantes_legales_id, p.id, r.tipo, r.numero_documento, r.nombre_completo, r.cargo,
+        r.fecha_desde, r.fecha_hasta, COALESCE(r.vigente, false),
+        row_number() OVER (PARTITION BY r.representantes_legales_id, r.tipo, r.numero_documento, r.cargo ORDER BY r.id),
+        r.created_at
+    FROM representantes_anteriores r
+    LEFT JOIN personas p ON p.tipo_documento = r.tipo AND p.numero_documento = r.numero_documento
+    ORDER BY r.id;
+
+    INSERT INTO ruc_establecimientos_anexos (id, ruc_id, consulta_id, huella, cantidad_anexos, created_at)
+    SELECT t.id, t.ruc_id, c.id, pg_temp.huella_anterior('ruc_establecimientos_anexos', t.id),
+        COALESCE(t.cantidad_anexos, 0), t.created_at
+    FROM ruc_anterior.ruc_establecimientos_anexos t
+    JOIN ruc_consultas c ON c.ruc_id = t.ruc_id AND c.created_at IS NOT DISTINCT FROM t.created_at;
+
+    INSERT INTO ruc_establecimientos (establecimientos_anexos_id, codigo, tipo_establecimiento, direccion,
+        actividad_economica, repeticion, created_at)
+    SELECT establecimientos_anexos_id, codigo, tipo_establecimiento, direccion, actividad_economica,
+        row_number() OVER (PARTITION BY establecimientos_anexos_id, codigo ORDER BY id), created_at
+    FROM ruc_anterior.ruc_establecimientos ORDER BY id;
+
+    -- Cada sección importada queda consultada en su consulta
+    FOR tabla, clave IN SELECT * FROM (VALUES
+        ('ruc_informacion_historica', 'informacion_historica'),
+        ('ruc_deuda_coactiva', 'deuda_coactiva'),
+        ('ruc_omisiones_tributarias', 'omisiones_tributarias'),
+        ('ruc_cantidad_trabajadores', 'cantidad_trabajadores'),
+        ('ruc_actas_probatorias', 'actas_probatorias'),
+        ('ruc_facturas_fisicas', 'facturas_fisicas'),
+        ('ruc_reactiva_peru', 'reactiva_peru'),
+        ('ruc_programa_covid19', 'garantias_covid19'),
+        ('ruc_representantes_legales', 'representantes_legales'),
+        ('ruc_establecimientos_anexos', 'establecimientos_anexos')
+    ) secciones
+    LOOP
+        EXECUTE format($f$
+            INSERT INTO ruc_consulta_secciones (ruc_id, consulta_id, seccion, estado, datos_consulta_id, created_at)
+            SELECT ruc_id, consulta_id, %L, 'consultada', consulta_id, created_at FROM %I$f$, clave, tabla);
+        -- Los id se conservaron: la secuencia sigue desde el mayor
+        EXECUTE format('SELECT setval(pg_get_serial_sequence(%L, ''id''), COALESCE(MAX(id), 0) + 1, false) FROM %I',
+            tabla, tabla);
+    END LOOP;
+    PERFORM setval(pg_get_serial_sequence('ruc_informacion_basica', 'id'), COALESCE(MAX(id), 0) + 1, false)
+    FROM ruc_informacion_basica;
+
+    DROP SCHEMA ruc_anterior CASCADE;
+END $$;
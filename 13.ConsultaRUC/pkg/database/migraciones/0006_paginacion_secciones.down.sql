@@ -0,0 +1,8 @@
+-- Deshace la migración 6
+
+ALTER TABLE ruc_consulta_secciones
+    DROP COLUMN paginacion_detectada,
+    DROP COLUMN paginas,
+    DROP COLUMN filas,
+    DROP COLUMN total_reportado,
+    DROP COLUMN verificado;
@@ -0,0 +1,10 @@
+-- Migración 6: la paginación de cada consulta adicional (DeteccionPaginacion y
+-- Paginacion de RUCCompleto) se guarda en su fila de ruc_consulta_secciones.
+-- NULL si la consulta no la registró; las consultas anteriores quedan así.
+
+ALTER TABLE ruc_consulta_secciones
+    ADD COLUMN paginacion_detectada BOOLEAN, -- la tabla tenía más de una página
+    ADD COLUMN paginas INTEGER,              -- páginas recorridas
+    ADD COLUMN filas INTEGER,                -- filas extraídas de todas las páginas
+    ADD COLUMN total_reportado INTEGER,      -- "Z" del contador "X a Y de Z"; 0 si no se mostraba
+    ADD COLUMN verificado BOOLEAN;           -- filas coincide con total_reportado
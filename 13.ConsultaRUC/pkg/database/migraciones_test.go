@@ -0,0 +1,286 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/documento"
+)
+
+func TestMigraciones(t *testing.T) {
+	migraciones, err := Migraciones()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migraciones) == 0 {
+		t.Fatal("no hay migraciones embebidas")
+	}
+	for i, m := range migraciones {
+		if m.Version != i+1 {
+			t.Errorf("migración %q: versión %d, se esperaba %d", m.Nombre, m.Version, i+1)
+		}
+	}
+
+	// La columna que antes agregaba un ALTER suelto debe venir en el esquema
+	if !strings.Contains(migraciones[0].Subir, "especificacion TEXT") {
+		t.Error("log_consultas.especificacion no está en la migración 1")
+	}
+}
+
+// esquemaDePrueba crea un esquema vacío dentro de TEST_DATABASE_URL y retorna
+// la URL que lo usa como search_path; se elimina al terminar la prueba
+func esquemaDePrueba(t *testing.T, prefijo string) (*sql.DB, string) {
+	t.Helper()
+	base := os.Getenv("TEST_DATABASE_URL")
+	if base == "" {
+		t.Skip("TEST_DATABASE_URL no definida")
+	}
+	db, err := sql.Open("postgres", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	esquema := fmt.Sprintf("%s_%d", prefijo, time.Now().UnixNano())
+	if _, err := db.Exec(`CREATE SCHEMA ` + esquema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Exec(`DROP SCHEMA ` + esquema + ` CASCADE`) })
+
+	u, err := url.Parse(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", esquema)
+	u.RawQuery = q.Encode()
+	return db, u.String()
+}
+
+// sentenciasSQL separa un script en sentencias por los ";" que no están en
+// comentarios, textos ni cuerpos $$
+func sentenciasSQL(script string) []string {
+	var sentencias []string
+	inicio := 0
+	texto, dolar := false, false
+	for i := 0; i < len(script); i++ {
+		switch {
+		case texto:
+			texto = script[i] != '\''
+		case dolar:
+			dolar = !strings.HasPrefix(script[i:], "$$")
+			if !dolar {
+				i++
+			}
+		case strings.HasPrefix(script[i:], "--"):
+			if fin := strings.IndexByte(script[i:], '\n'); fin >= 0 {
+				i += fin
+			} else {
+				i = len(script)
+			}
+		case strings.HasPrefix(script[i:], "$$"):
+			dolar = true
+			i++
+		case script[i] == '\'':
+			texto = true
+		case script[i] == ';':
+			sentencias = append(sentencias, script[inicio:i])
+			inicio = i + 1
+		}
+	}
+	if strings.TrimSpace(script[inicio:]) != "" {
+		sentencias = append(sentencias, script[inicio:])
+	}
+	return sentencias
+}
+
+func TestSentenciasSQL(t *testing.T) {
+	script := `CREATE TABLE a (x TEXT); -- b; c
+CREATE FUNCTION f() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.x = 'a;b';
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+ALTER TABLE a ADD COLUMN y TEXT`
+	sentencias := sentenciasSQL(script)
+	if len(sentencias) != 3 {
+		t.Fatalf("sentenciasSQL = %q, se esperaban 3", sentencias)
+	}
+	if !strings.HasSuffix(sentencias[1], "$$ language 'plpgsql'") {
+		t.Errorf("sentencia 2 = %q", sentencias[1])
+	}
+}
+
+// TestMigrarEsquemaAnterior parte de una base creada con los scripts
+// anteriores a las migraciones (database/ruc_pruebas.sql y database/prueba.sql
+// tal como estaban) y con filas guardadas por el scraper de entonces
+func TestMigrarEsquemaAnterior(t *testing.T) {
+	db, dsn := esquemaDePrueba(t, "anterior")
+	// Si la migración falla, el esquema apartado por la 2 queda en la base
+	t.Cleanup(func() { db.Exec(`DROP SCHEMA IF EXISTS ruc_anterior CASCADE`) })
+
+	vieja, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vieja.Close()
+	// Como psql -f: cada sentencia por separado y un error no detiene el
+	// script (prueba.sql tiene un índice sobre una columna que no existe)
+	for _, archivo := range []string{"ruc_pruebas.sql", "prueba.sql"} {
+		script, err := os.ReadFile("testdata/esquema_anterior/" + archivo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sentencia := range sentenciasSQL(string(script)) {
+			if _, err := vieja.Exec(sentencia); err != nil {
+				t.Logf("%s: %v", archivo, err)
+			}
+		}
+	}
+	datos, err := os.ReadFile("testdata/esquema_anterior/datos.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vieja.Exec(string(datos)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewDatabaseService(dsn); !errors.Is(err, ErrVersionEsquema) {
+		t.Fatalf("NewDatabaseService en una base sin migrar: %v, se esperaba ErrVersionEsquema", err)
+	}
+	migrador, err := NewMigrador(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrador.Close()
+	if _, err := migrador.Subir(0); err != nil {
+		t.Fatal(err)
+	}
+	var apartado bool
+	if err := db.QueryRow(`SELECT to_regnamespace('ruc_anterior') IS NOT NULL`).Scan(&apartado); err != nil || apartado {
+		t.Errorf("el esquema ruc_anterior sigue en la base (err = %v)", err)
+	}
+
+	ds, err := NewDatabaseService(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+
+	// Consulta 5: la última con consulta propia, dueña de la ficha
+	rc, err := ds.GetRUCPorConsulta(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := rc.InformacionBasica
+	if info.TipoDocumento != documento.DNI || info.NumeroDocumento != "42844785" || info.NombreTitular != "PEREZ GOMEZ, JUAN" {
+		t.Errorf("documento = %q %q %q", info.TipoDocumento, info.NumeroDocumento, info.NombreTitular)
+	}
+	if info.AfiliadoPLE.String() != "01/01/2013" {
+		t.Errorf("AfiliadoPLE = %v", info.AfiliadoPLE)
+	}
+	actividades := info.ActividadesEconomicas
+	if len(actividades) != 3 || actividades[0].CodigoCIIU != "6920" || actividades[0].RevisionCIIU != 4 ||
+		actividades[1].Orden != 1 || actividades[2].CodigoCIIU != "" || actividades[2].Descripcion != "NO REGISTRA" {
+		t.Errorf("ActividadesEconomicas = %+v", actividades)
+	}
+	if rc.OmisionesTributarias == nil || len(rc.OmisionesTributarias.Omisiones) != 3 {
+		t.Fatalf("OmisionesTributarias = %+v", rc.OmisionesTributarias)
+	}
+	omisiones := rc.OmisionesTributarias.Omisiones
+	if omisiones[0].Periodo.String() != "202311" || omisiones[1].Periodo.String() != "202311" || omisiones[2].Periodo.Conocido() {
+		t.Errorf("periodos de las omisiones = %v, %v, %v", omisiones[0].Periodo, omisiones[1].Periodo, omisiones[2].Periodo)
+	}
+	if rc.DeudaCoactiva == nil || rc.DeudaCoactiva.CantidadDocumentos != 0 {
+		t.Errorf("DeudaCoactiva = %+v", rc.DeudaCoactiva)
+	}
+	if rc.RepresentantesLegales == nil || len(rc.RepresentantesLegales.Representantes) != 1 ||
+		rc.RepresentantesLegales.Representantes[0].TipoDocumento != documento.DNI ||
+		rc.RepresentantesLegales.Representantes[0].CargoSUNAT != "TITULAR-GERENTE" {
+		t.Errorf("RepresentantesLegales = %+v", rc.RepresentantesLegales)
+	}
+	if _, ok := rc.EstadoSecciones["establecimientos_anexos"]; ok {
+		t.Errorf("la consulta 5 no guardó establecimientos: %v", rc.EstadoSecciones)
+	}
+
+	// Consulta 3: sus propias consultas adicionales y la ficha de la última
+	rc, err = ds.GetRUCPorConsulta(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.DeudaCoactiva == nil || len(rc.DeudaCoactiva.Deudas) != 1 || rc.DeudaCoactiva.Deudas[0].PeriodoTributario.String() != "202312" {
+		t.Errorf("DeudaCoactiva de la consulta 3 = %+v", rc.DeudaCoactiva)
+	}
+	if rc.InformacionBasica.RazonSocial != "PEREZ GOMEZ JUAN" {
+		t.Errorf("RazonSocial = %q", rc.InformacionBasica.RazonSocial)
+	}
+
+	// Los establecimientos sin consulta con su created_at reciben una, la última
+	rc, err = ds.GetRUCByNumber("10428447851")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.EstablecimientosAnexos == nil || len(rc.EstablecimientosAnexos.Establecimientos) != 1 {
+		t.Errorf("EstablecimientosAnexos = %+v", rc.EstablecimientosAnexos)
+	}
+	if len(rc.InformacionBasica.ActividadesEconomicas) != 3 {
+		t.Errorf("la última consulta no tiene la ficha: %+v", rc.InformacionBasica)
+	}
+
+	// Las secuencias siguen desde los id importados
+	if err := ds.InsertRUCCompleto(consultasDePrueba(1)[0]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestAdoptar registra las migraciones de una base que ya tiene el esquema
+// pero perdió schema_migrations, como una restaurada de un respaldo parcial
+func TestAdoptar(t *testing.T) {
+	db, dsn := esquemaDePrueba(t, "adopcion")
+	migrador, err := NewMigrador(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer migrador.Close()
+	if _, err := migrador.Subir(0); err != nil {
+		t.Fatal(err)
+	}
+	esquema, _ := url.Parse(dsn)
+	if _, err := db.Exec(`DROP TABLE ` + esquema.Query().Get("search_path") + `.schema_migrations`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDatabaseService(dsn); !errors.Is(err, ErrVersionEsquema) {
+		t.Fatalf("NewDatabaseService sin schema_migrations: %v, se esperaba ErrVersionEsquema", err)
+	}
+
+	ultima, err := UltimaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	adoptadas, err := migrador.Adoptar(ultima)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adoptadas) != ultima {
+		t.Errorf("se adoptaron %d migraciones, se esperaban %d", len(adoptadas), ultima)
+	}
+	if _, err := migrador.Adoptar(ultima); !errors.Is(err, ErrVersionEsquema) {
+		t.Errorf("adoptar dos veces: %v, se esperaba ErrVersionEsquema", err)
+	}
+
+	ds, err := NewDatabaseService(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ds.Close()
+	if err := ds.InsertRUCCompleto(consultasDePrueba(1)[0]); err != nil {
+		t.Fatal(err)
+	}
+}
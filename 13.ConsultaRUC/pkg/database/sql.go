@@ -1,12 +1,17 @@
 package database
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"time"
+	"strings"
 
+	"github.com/consulta-ruc-scraper/pkg/diff"
 	"github.com/consulta-ruc-scraper/pkg/models"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/consulta-ruc-scraper/pkg/secciones"
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
 type DatabaseService struct {
@@ -23,6 +28,11 @@ func NewDatabaseService(connectionString string) (*DatabaseService, error) {
 		return nil, fmt.Errorf("error pinging database: %w", err)
 	}
 
+	if err := verificarEsquema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	service := &DatabaseService{db: db}
 	return service, nil
 }
@@ -38,571 +48,1103 @@ func (ds *DatabaseService) InsertRUCCompleto(ruc *models.RUCCompleto) error {
 	}
 	defer tx.Rollback()
 
-	// 1. Insertar información básica
+	// 1. Insertar o actualizar el RUC con la ficha de esta consulta
 	rucID, err := ds.insertRUCInformacionBasica(tx, ruc)
 	if err != nil {
 		return fmt.Errorf("error inserting RUC info: %w", err)
 	}
 
-	// 2. Insertar actividades económicas
-	if err := ds.insertActividadesEconomicas(tx, rucID, ruc.InformacionBasica.ActividadesEconomicas); err != nil {
-		return fmt.Errorf("error inserting actividades economicas: %w", err)
+	// 2. Buscar una consulta anterior con la misma ficha; si no hay, la guarda esta
+	huellaFicha, fichaID, err := ds.buscarFoto(tx, "ruc_fichas", rucID, ruc.InformacionBasica)
+	if err != nil {
+		return fmt.Errorf("error reading ficha: %w", err)
 	}
 
-	// 3. Insertar comprobantes de pago
-	if err := ds.insertComprobantesPago(tx, rucID, ruc.InformacionBasica.ComprobantesPago); err != nil {
-		return fmt.Errorf("error inserting comprobantes pago: %w", err)
+	// 3. Insertar consulta; todo lo que sigue es la foto de esta consulta
+	consultaID, err := ds.insertConsulta(tx, rucID, fichaID, ruc)
+	if err != nil {
+		return fmt.Errorf("error inserting consulta: %w", err)
 	}
 
-	// 4. Insertar sistemas de emisión electrónica
-	if err := ds.insertSistemasEmisionElectronica(tx, rucID, ruc.InformacionBasica.SistemaEmisionElectronica); err != nil {
-		return fmt.Errorf("error inserting sistemas emision electronica: %w", err)
+	// 4. Insertar la ficha con sus listas solo si cambió
+	if fichaID == 0 {
+		if err := ds.insertFicha(tx, rucID, consultaID, huellaFicha, &ruc.InformacionBasica); err != nil {
+			return err
+		}
 	}
 
-	// 5. Insertar comprobantes electrónicos
-	if err := ds.insertComprobantesElectronicos(tx, rucID, ruc.InformacionBasica.ComprobantesElectronicos); err != nil {
-		return fmt.Errorf("error inserting comprobantes electronicos: %w", err)
+	// 5. Insertar consultas adicionales en el orden del registro de secciones;
+	// cada escritor retorna la consulta que guarda los datos de su sección
+	datos := make(map[string]int64)
+	for _, sec := range seccionesGuardadas() {
+		datosID, err := sec.escribir(ds, tx, rucID, consultaID, ruc)
+		if err != nil {
+			return fmt.Errorf("error inserting %s: %w", sec.nombre(), err)
+		}
+		if datosID != 0 {
+			datos[sec.Clave] = datosID
+		}
 	}
 
-	// 6. Insertar padrones
-	if err := ds.insertPadrones(tx, rucID, ruc.InformacionBasica.Padrones); err != nil {
-		return fmt.Errorf("error inserting padrones: %w", err)
+	// 6. Insertar el estado de cada sección (consultada, no_solicitada, ...)
+	if err := ds.insertEstadoSecciones(tx, rucID, consultaID, ruc, datos); err != nil {
+		return fmt.Errorf("error inserting estado secciones: %w", err)
 	}
 
-	// 7. Insertar consulta
-	if err := ds.insertConsulta(tx, rucID, ruc); err != nil {
-		return fmt.Errorf("error inserting consulta: %w", err)
+	// 7. Descartar la consulta si repite la anterior; si no, registrar los cambios
+	repetida, err := ds.descartarConsultaRepetida(tx, rucID, consultaID)
+	if err != nil {
+		return fmt.Errorf("error deleting consulta repetida: %w", err)
 	}
-
-	// 8. Insertar información histórica
-	if ruc.InformacionHistorica != nil {
-		if err := ds.insertInformacionHistorica(tx, rucID, ruc.InformacionHistorica); err != nil {
-			return fmt.Errorf("error inserting informacion historica: %w", err)
+	if !repetida {
+		if err := ds.insertCambios(tx, rucID, consultaID); err != nil {
+			return fmt.Errorf("error inserting cambios: %w", err)
 		}
 	}
 
-	// 9. Insertar deuda coactiva
-	if ruc.DeudaCoactiva != nil {
-		if err := ds.insertDeudaCoactiva(tx, rucID, ruc.DeudaCoactiva); err != nil {
-			return fmt.Errorf("error inserting deuda coactiva: %w", err)
-		}
+	// 8. Si el RUC figuraba como inexistente, deja de estarlo
+	if _, err := tx.Exec(`DELETE FROM ruc_inexistentes WHERE ruc = $1`, ruc.InformacionBasica.RUC); err != nil {
+		return fmt.Errorf("error deleting ruc inexistente: %w", err)
 	}
 
-	// 10. Insertar omisiones tributarias
-	if ruc.OmisionesTributarias != nil {
-		if err := ds.insertOmisionesTributarias(tx, rucID, ruc.OmisionesTributarias); err != nil {
-			return fmt.Errorf("error inserting omisiones tributarias: %w", err)
-		}
+	return tx.Commit()
+}
+
+// RegistrarRUCInexistente guarda un RUC que SUNAT informó como no registrado.
+// Si ya estaba registrado actualiza la fecha de la última consulta.
+func (ds *DatabaseService) RegistrarRUCInexistente(ruc string) error {
+	_, err := ds.db.Exec(`
+		INSERT INTO ruc_inexistentes (ruc)
+		VALUES ($1)
+		ON CONFLICT (ruc) DO UPDATE SET
+			fecha_ultima_consulta = CURRENT_TIMESTAMP,
+			veces_consultado = ruc_inexistentes.veces_consultado + 1`,
+		ruc)
+	if err != nil {
+		return fmt.Errorf("error registrando RUC inexistente %s: %w", ruc, err)
+	}
+	return nil
+}
+
+// columnasRepeticion son las columnas de ruc_consulta_secciones que deben
+// coincidir para que una consulta repita la anterior
+const columnasRepeticion = `seccion, estado, datos_consulta_id, paginacion_detectada, paginas, filas, total_reportado, verificado`
+
+// descartarConsultaRepetida borra la consulta si repite la anterior del RUC:
+// tiene la misma versión, apunta a la misma ficha y cada sección tiene el
+// mismo estado, los mismos datos y la misma paginación. Sus filas de
+// ruc_consulta_secciones se borran en cascada y no tiene otras, porque la
+// ficha y las secciones sin cambios no se vuelven a guardar; así consultar de
+// nuevo un RUC sin cambios no agrega filas. Retorna si la borró.
+func (ds *DatabaseService) descartarConsultaRepetida(tx *sql.Tx, rucID, consultaID int64) (bool, error) {
+	res, err := tx.Exec(`
+		WITH anterior AS (
+			SELECT id, version_api, ficha_consulta_id FROM ruc_consultas
+			WHERE ruc_id = $1
+			  AND (fecha_consulta, id) < (SELECT fecha_consulta, id FROM ruc_consultas WHERE id = $2)
+			ORDER BY fecha_consulta DESC, id DESC
+			LIMIT 1
+		)
+		DELETE FROM ruc_consultas c
+		USING anterior a
+		WHERE c.id = $2
+		  AND c.ficha_consulta_id = a.ficha_consulta_id
+		  AND c.version_api IS NOT DISTINCT FROM a.version_api
+		  AND NOT EXISTS (
+			(SELECT `+columnasRepeticion+` FROM ruc_consulta_secciones WHERE consulta_id = c.id
+			 EXCEPT
+			 SELECT `+columnasRepeticion+` FROM ruc_consulta_secciones WHERE consulta_id = a.id)
+			UNION ALL
+			(SELECT `+columnasRepeticion+` FROM ruc_consulta_secciones WHERE consulta_id = a.id
+			 EXCEPT
+			 SELECT `+columnasRepeticion+` FROM ruc_consulta_secciones WHERE consulta_id = c.id))`,
+		rucID, consultaID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// insertCambios compara la consulta con la anterior del RUC y guarda las
+// diferencias en ruc_cambios. Las dos consultas se leen de la base para que
+// sus textos tengan la misma normalización. Cada sección se compara con la
+// última consulta que tiene sus datos (ver completarAnterior).
+func (ds *DatabaseService) insertCambios(tx *sql.Tx, rucID, consultaID int64) error {
+	var anteriorID int64
+	err := tx.QueryRow(`
+		SELECT id FROM ruc_consultas
+		WHERE ruc_id = $1
+		  AND (fecha_consulta, id) < (SELECT fecha_consulta, id FROM ruc_consultas WHERE id = $2)
+		ORDER BY fecha_consulta DESC, id DESC
+		LIMIT 1`,
+		rucID, consultaID).Scan(&anteriorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // primera consulta del RUC
+	}
+	if err != nil {
+		return err
+	}
+
+	anterior, err := ds.leerConsulta(tx, anteriorID)
+	if err != nil {
+		return err
+	}
+	if err := ds.completarAnterior(tx, rucID, consultaID, anteriorID, anterior); err != nil {
+		return err
+	}
+	nuevo, err := ds.leerConsulta(tx, consultaID)
+	if err != nil {
+		return err
 	}
 
-	// 11. Insertar cantidad trabajadores
-	if ruc.CantidadTrabajadores != nil {
-		if err := ds.insertCantidadTrabajadores(tx, rucID, ruc.CantidadTrabajadores); err != nil {
-			return fmt.Errorf("error inserting cantidad trabajadores: %w", err)
+	for _, c := range diff.Comparar(anterior, nuevo) {
+		_, err := tx.Exec(`
+			INSERT INTO ruc_cambios (ruc_id, consulta_anterior_id, consulta_id, tipo, clave, anterior, nuevo)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			rucID, anteriorID, consultaID, string(c.Tipo),
+			ds.nullString(c.Clave), ds.nullString(c.Anterior), ds.nullString(c.Nuevo))
+		if err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// 12. Insertar actas probatorias
-	if ruc.ActasProbatorias != nil {
-		if err := ds.insertActasProbatorias(tx, rucID, ruc.ActasProbatorias); err != nil {
-			return fmt.Errorf("error inserting actas probatorias: %w", err)
+// completarAnterior agrega a anterior las secciones que la consulta anteriorID
+// no tiene (se pidieron otras con --sections o no estaban disponibles) con los
+// datos de la última consulta previa a consultaID que las guardó, para que un
+// cambio ocurrido entre consultas completas no se pierda por una selectiva
+func (ds *DatabaseService) completarAnterior(tx *sql.Tx, rucID, consultaID, anteriorID int64, anterior *models.RUCCompleto) error {
+	datos := make(map[string]int64)
+	err := leerFilas(tx, func(filas *sql.Rows) error {
+		var seccion string
+		var datosID int64
+		if err := filas.Scan(&seccion, &datosID); err != nil {
+			return err
 		}
+		datos[seccion] = datosID
+		return nil
+	}, `
+		SELECT DISTINCT ON (s.seccion) s.seccion, s.datos_consulta_id
+		FROM ruc_consulta_secciones s
+		JOIN ruc_consultas c ON c.id = s.consulta_id
+		WHERE s.ruc_id = $1
+		  AND s.datos_consulta_id IS NOT NULL
+		  AND (c.fecha_consulta, c.id) < (SELECT fecha_consulta, id FROM ruc_consultas WHERE id = $2)
+		  AND NOT EXISTS (
+			SELECT 1 FROM ruc_consulta_secciones a
+			WHERE a.consulta_id = $3 AND a.seccion = s.seccion AND a.datos_consulta_id IS NOT NULL)
+		ORDER BY s.seccion, c.fecha_consulta DESC, c.id DESC`,
+		rucID, consultaID, anteriorID)
+	if err != nil {
+		return fmt.Errorf("error reading secciones anteriores: %w", err)
 	}
 
-	// 13. Insertar facturas físicas
-	if ruc.FacturasFisicas != nil {
-		if err := ds.insertFacturasFisicas(tx, rucID, ruc.FacturasFisicas); err != nil {
-			return fmt.Errorf("error inserting facturas fisicas: %w", err)
+	for _, sec := range seccionesGuardadas() {
+		if datos[sec.Clave] == 0 {
+			continue
+		}
+		if err := sec.leer(ds, tx, datos[sec.Clave], anterior); err != nil {
+			return fmt.Errorf("error reading %s: %w", sec.nombre(), err)
 		}
 	}
+	return nil
+}
 
-	// 14. Insertar reactiva peru
-	if ruc.ReactivaPeru != nil {
-		if err := ds.insertReactivaPeru(tx, rucID, ruc.ReactivaPeru); err != nil {
-			return fmt.Errorf("error inserting reactiva peru: %w", err)
+// seccionGuardada es una sección del registro con lo necesario para guardarla
+// y leerla: su Escritor y su Lector si es una sección propia o, si es de SUNAT,
+// su entrada en seccionesSUNAT
+type seccionGuardada struct {
+	secciones.Seccion
+	sunat seccionSUNAT
+}
+
+// seccionesGuardadas retorna, en el orden del registro, las secciones que se
+// guardan en la base. InsertRUCCompleto, InsertRUCCompletoLote y la lectura
+// recorren esta lista.
+func seccionesGuardadas() []seccionGuardada {
+	var guardadas []seccionGuardada
+	for _, sec := range secciones.Todas() {
+		sunat, ok := seccionesSUNAT[sec.Clave]
+		if sec.Escritor == nil && !ok {
+			continue
 		}
+		guardadas = append(guardadas, seccionGuardada{Seccion: sec, sunat: sunat})
+	}
+	return guardadas
+}
+
+// nombre es la clave de la sección para los mensajes de error
+func (s seccionGuardada) nombre() string {
+	return strings.ReplaceAll(s.Clave, "_", " ")
+}
+
+// escribir guarda la sección y retorna la consulta cuyas filas tienen sus
+// datos: consultaID, o una anterior si los datos no cambiaron; 0 si no hay
+// datos. El Escritor de una sección propia siempre guarda con consultaID.
+func (s seccionGuardada) escribir(ds *DatabaseService, tx *sql.Tx, rucID, consultaID int64, ruc *models.RUCCompleto) (int64, error) {
+	if s.Escritor != nil {
+		return consultaID, s.Escritor(tx, rucID, consultaID, ruc)
+	}
+	f := s.sunat.foto(ds, ruc)
+	if f == nil {
+		return 0, nil
+	}
+	return ds.insertFoto(tx, rucID, consultaID, f)
+}
+
+// leer asigna en ruc los datos de la sección que guardó la consulta consultaID
+func (s seccionGuardada) leer(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) error {
+	if s.Escritor != nil {
+		return s.Lector(tx, consultaID, ruc)
 	}
+	return s.sunat.leer(ds, tx, consultaID, ruc)
+}
+
+// foto son las filas que guarda una sección cuando sus datos cambiaron: la
+// fila de su tabla principal, con ruc_id, consulta_id y huella, y las filas de
+// detalle que cuelgan de ella
+type foto struct {
+	tabla    string
+	datos    any // lo que se resume en la huella
+	columnas []string
+	valores  []interface{}
+	detalles []detalle
+	// personas son los representantes a registrar en personas antes de
+	// insertar los detalles, que los referencian con su clavePersona
+	personas []models.RepresentanteLegal
+}
+
+func (f *foto) columnasPrincipal() []string {
+	return append([]string{"ruc_id", "consulta_id", "huella"}, f.columnas...)
+}
+
+func (f *foto) filaPrincipal(rucID, consultaID int64, huella string) []interface{} {
+	return append([]interface{}{rucID, consultaID, huella}, f.valores...)
+}
+
+// detalle son filas que referencian a la fila principal de la foto por la
+// columna fk; clave son las columnas de su clave natural, nil si no tiene
+type detalle struct {
+	tabla, fk string
+	columnas  []string
+	clave     []string
+	filas     [][]interface{}
+}
+
+// columnasConFK retorna fk, las columnas y, si hay clave natural, repeticion
+func (d *detalle) columnasConFK() []string {
+	columnas := append([]string{d.fk}, d.columnas...)
+	if d.clave != nil {
+		columnas = append(columnas, "repeticion")
+	}
+	return columnas
+}
 
-	// 15. Insertar programa covid19
-	if ruc.ProgramaCovid19 != nil {
-		if err := ds.insertProgramaCovid19(tx, rucID, ruc.ProgramaCovid19); err != nil {
-			return fmt.Errorf("error inserting programa covid19: %w", err)
+// filasDe retorna las filas con el id de la fila principal y, en lugar de
+// cada clavePersona, el id de la persona. Una fila que repite la clave
+// natural de otra lleva repeticion 2, 3, ...: SUNAT puede mostrarla dos
+// veces y no se descarta.
+func (d *detalle) filasDe(id int64, personas map[clavePersona]int64) [][]interface{} {
+	var indices []int
+	for _, c := range d.clave {
+		for i, columna := range d.columnas {
+			if columna == c {
+				indices = append(indices, i)
+			}
 		}
 	}
+	repeticiones := make(map[string]int)
+
+	filas := make([][]interface{}, len(d.filas))
+	for i, f := range d.filas {
+		fila := make([]interface{}, 0, len(f)+2)
+		fila = append(fila, id)
+		for _, v := range f {
+			if k, ok := v.(clavePersona); ok {
+				v = personas[k]
+			}
+			fila = append(fila, v)
+		}
+		if d.clave != nil {
+			var clave strings.Builder
+			for _, j := range indices {
+				fmt.Fprintf(&clave, "%v\x00", f[j])
+			}
+			repeticiones[clave.String()]++
+			fila = append(fila, repeticiones[clave.String()])
+		}
+		filas[i] = fila
+	}
+	return filas
+}
+
+// seccionSUNAT es cómo se guarda y se lee una sección de SUNAT: foto arma sus
+// filas con los datos de la consulta (nil si no se consultó) y leer asigna en
+// ruc los datos que guardó la consulta consultaID
+type seccionSUNAT struct {
+	foto func(ds *DatabaseService, ruc *models.RUCCompleto) *foto
+	leer func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) error
+}
+
+// seccionesSUNAT tiene la foto y la lectura de cada sección de SUNAT del
+// registro de pkg/secciones, por clave
+var seccionesSUNAT = map[string]seccionSUNAT{
+	"informacion_historica": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.InformacionHistorica == nil {
+				return nil
+			}
+			return ds.fotoInformacionHistorica(ruc.InformacionHistorica)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.InformacionHistorica, err = ds.leerInformacionHistorica(tx, consultaID)
+			return err
+		},
+	},
+	"deuda_coactiva": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.DeudaCoactiva == nil {
+				return nil
+			}
+			return ds.fotoDeudaCoactiva(ruc.DeudaCoactiva)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.DeudaCoactiva, err = ds.leerDeudaCoactiva(tx, consultaID)
+			return err
+		},
+	},
+	"omisiones_tributarias": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.OmisionesTributarias == nil {
+				return nil
+			}
+			return ds.fotoOmisionesTributarias(ruc.OmisionesTributarias)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.OmisionesTributarias, err = ds.leerOmisionesTributarias(tx, consultaID)
+			return err
+		},
+	},
+	"cantidad_trabajadores": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.CantidadTrabajadores == nil {
+				return nil
+			}
+			return ds.fotoCantidadTrabajadores(ruc.CantidadTrabajadores)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.CantidadTrabajadores, err = ds.leerCantidadTrabajadores(tx, consultaID)
+			return err
+		},
+	},
+	"actas_probatorias": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.ActasProbatorias == nil {
+				return nil
+			}
+			return ds.fotoActasProbatorias(ruc.ActasProbatorias)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.ActasProbatorias, err = ds.leerActasProbatorias(tx, consultaID)
+			return err
+		},
+	},
+	"facturas_fisicas": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.FacturasFisicas == nil {
+				return nil
+			}
+			return ds.fotoFacturasFisicas(ruc.FacturasFisicas)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.FacturasFisicas, err = ds.leerFacturasFisicas(tx, consultaID)
+			return err
+		},
+	},
+	"reactiva_peru": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.ReactivaPeru == nil {
+				return nil
+			}
+			return ds.fotoReactivaPeru(ruc.ReactivaPeru)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.ReactivaPeru, err = ds.leerReactivaPeru(tx, consultaID)
+			return err
+		},
+	},
+	"garantias_covid19": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.ProgramaCovid19 == nil {
+				return nil
+			}
+			return ds.fotoProgramaCovid19(ruc.ProgramaCovid19)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.ProgramaCovid19, err = ds.leerProgramaCovid19(tx, consultaID)
+			return err
+		},
+	},
+	"representantes_legales": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.RepresentantesLegales == nil {
+				return nil
+			}
+			return ds.fotoRepresentantesLegales(ruc.RepresentantesLegales)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.RepresentantesLegales, err = ds.leerRepresentantesLegales(tx, consultaID)
+			return err
+		},
+	},
+	"establecimientos_anexos": {
+		foto: func(ds *DatabaseService, ruc *models.RUCCompleto) *foto {
+			if ruc.EstablecimientosAnexos == nil {
+				return nil
+			}
+			return ds.fotoEstablecimientosAnexos(ruc.EstablecimientosAnexos)
+		},
+		leer: func(ds *DatabaseService, tx *sql.Tx, consultaID int64, ruc *models.RUCCompleto) (err error) {
+			ruc.EstablecimientosAnexos, err = ds.leerEstablecimientosAnexos(tx, consultaID)
+			return err
+		},
+	},
+}
+
+// insertFoto guarda la foto de una sección y retorna la consulta que tiene sus
+// datos: consultaID, o la consulta anterior que ya guardó los mismos datos
+func (ds *DatabaseService) insertFoto(tx *sql.Tx, rucID, consultaID int64, f *foto) (int64, error) {
+	h, datosID, err := ds.buscarFoto(tx, f.tabla, rucID, f.datos)
+	if err != nil || datosID != 0 {
+		return datosID, err
+	}
+
+	personas, err := ds.upsertPersonas(tx, f.personas)
+	if err != nil {
+		return 0, err
+	}
+
+	// Insertar registro principal
+	var id int64
+	args := f.filaPrincipal(rucID, consultaID, h)
+	err = tx.QueryRow(`
+		INSERT INTO `+f.tabla+` (`+strings.Join(f.columnasPrincipal(), ", ")+`)
+		VALUES (`+marcadores(1, len(args))+`) RETURNING id`,
+		args...).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
 
-	// 16. Insertar representantes legales
-	if ruc.RepresentantesLegales != nil {
-		if err := ds.insertRepresentantesLegales(tx, rucID, ruc.RepresentantesLegales); err != nil {
-			return fmt.Errorf("error inserting representantes legales: %w", err)
+	// Insertar el detalle, una sentencia por tabla
+	for _, d := range f.detalles {
+		if err := insertFilas(tx, d.tabla, d.columnasConFK(), d.filasDe(id, personas), ""); err != nil {
+			return 0, err
 		}
 	}
 
-	// 17. Insertar establecimientos anexos
-	if ruc.EstablecimientosAnexos != nil {
-		if err := ds.insertEstablecimientosAnexos(tx, rucID, ruc.EstablecimientosAnexos); err != nil {
-			return fmt.Errorf("error inserting establecimientos anexos: %w", err)
+	return consultaID, nil
+}
+
+// maxParametros es el límite de parámetros de una sentencia en PostgreSQL
+const maxParametros = 65535
+
+// insertFilas inserta las filas con INSERT de varias filas, las necesarias para
+// no pasar de maxParametros; sufijo va al final de cada sentencia (ON CONFLICT ...)
+func insertFilas(tx *sql.Tx, tabla string, columnas []string, filas [][]interface{}, sufijo string) error {
+	porSentencia := maxParametros / len(columnas)
+	for len(filas) > 0 {
+		n := min(len(filas), porSentencia)
+		args := make([]interface{}, 0, n*len(columnas))
+		for _, fila := range filas[:n] {
+			args = append(args, fila...)
 		}
+		if _, err := tx.Exec(sentenciaFilas(tabla, columnas, n, sufijo), args...); err != nil {
+			return err
+		}
+		filas = filas[n:]
 	}
+	return nil
+}
 
-	return tx.Commit()
+// sentenciaFilas arma "INSERT INTO tabla (columnas) VALUES (...), (...) sufijo" con n filas
+func sentenciaFilas(tabla string, columnas []string, n int, sufijo string) string {
+	var b strings.Builder
+	b.WriteString("INSERT INTO " + tabla + " (" + strings.Join(columnas, ", ") + ") VALUES ")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(" + marcadores(1+i*len(columnas), len(columnas)) + ")")
+	}
+	if sufijo != "" {
+		b.WriteString(" " + sufijo)
+	}
+	return b.String()
 }
 
+// insertRUCInformacionBasica crea el RUC o actualiza su ficha con la de esta
+// consulta. Si la ficha no cambió la fila no se toca.
 func (ds *DatabaseService) insertRUCInformacionBasica(tx *sql.Tx, ruc *models.RUCCompleto) (int64, error) {
 	query := `
-	INSERT INTO ruc_informacion_basica (
-		ruc, razon_social, tipo_contribuyente, tipo_documento, nombre_comercial,
-		fecha_inscripcion, fecha_inicio_actividades, estado, condicion,
-		domicilio_fiscal, sistema_emision, actividad_comercio_exterior,
-		sistema_contabilidad, emisor_electronico_desde, afiliado_ple
-	) VALUES (
-		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
-	)
+	INSERT INTO ruc_informacion_basica (ruc, ` + columnasFicha + `)
+	VALUES ($1, ` + marcadores(2, len(camposFicha)) + `)
 	ON CONFLICT (ruc) DO UPDATE SET
-		razon_social = EXCLUDED.razon_social,
-		tipo_contribuyente = EXCLUDED.tipo_contribuyente,
-		tipo_documento = EXCLUDED.tipo_documento,
-		nombre_comercial = EXCLUDED.nombre_comercial,
-		fecha_inscripcion = EXCLUDED.fecha_inscripcion,
-		fecha_inicio_actividades = EXCLUDED.fecha_inicio_actividades,
-		estado = EXCLUDED.estado,
-		condicion = EXCLUDED.condicion,
-		domicilio_fiscal = EXCLUDED.domicilio_fiscal,
-		sistema_emision = EXCLUDED.sistema_emision,
-		actividad_comercio_exterior = EXCLUDED.actividad_comercio_exterior,
-		sistema_contabilidad = EXCLUDED.sistema_contabilidad,
-		emisor_electronico_desde = EXCLUDED.emisor_electronico_desde,
-		afiliado_ple = EXCLUDED.afiliado_ple,
+		` + actualizarFicha + `,
 		updated_at = CURRENT_TIMESTAMP
+	WHERE ` + fichaCambiada + `
 	RETURNING id`
 
+	args := append([]interface{}{ruc.InformacionBasica.RUC}, ds.valoresFicha(&ruc.InformacionBasica)...)
+
 	var rucID int64
-	err := tx.QueryRow(query,
-		ruc.InformacionBasica.RUC,
-		ds.nullString(ruc.InformacionBasica.RazonSocial),
-		ds.nullString(ruc.InformacionBasica.TipoContribuyente),
-		ds.nullString(ruc.InformacionBasica.TipoDocumento),
-		ds.nullString(ruc.InformacionBasica.NombreComercial),
-		ds.parseDate(ruc.InformacionBasica.FechaInscripcion),
-		ds.parseDate(ruc.InformacionBasica.FechaInicioActividades),
-		ds.nullString(ruc.InformacionBasica.Estado),
-		ds.nullString(ruc.InformacionBasica.Condicion),
-		ds.nullString(ruc.InformacionBasica.DomicilioFiscal),
-		ds.nullString(ruc.InformacionBasica.SistemaEmision),
-		ds.nullString(ruc.InformacionBasica.ActividadComercioExterior),
-		ds.nullString(ruc.InformacionBasica.SistemaContabilidad),
-		ds.parseDate(ruc.InformacionBasica.EmisorElectronicoDesde),
-		ds.nullString(ruc.InformacionBasica.AfiliadoPLE),
-	).Scan(&rucID)
+	err := tx.QueryRow(query, args...).Scan(&rucID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.QueryRow(`SELECT id FROM ruc_informacion_basica WHERE ruc = $1`,
+			ruc.InformacionBasica.RUC).Scan(&rucID)
+	}
 
 	return rucID, err
 }
 
-func (ds *DatabaseService) insertActividadesEconomicas(tx *sql.Tx, rucID int64, actividades []string) error {
-	_, err := tx.Exec("DELETE FROM ruc_actividades_economicas WHERE ruc_id = $1", rucID)
+// insertFicha guarda en ruc_fichas una ficha que ninguna consulta anterior del
+// RUC tenía, junto con sus actividades, comprobantes y padrones
+func (ds *DatabaseService) insertFicha(tx *sql.Tx, rucID, consultaID int64, huella string, info *models.RUCInfo) error {
+	args := append([]interface{}{consultaID, rucID, huella}, ds.valoresFicha(info)...)
+	_, err := tx.Exec(`
+		INSERT INTO ruc_fichas (consulta_id, ruc_id, huella, `+columnasFicha+`)
+		VALUES ($1, $2, $3, `+marcadores(4, len(camposFicha))+`)`,
+		args...)
 	if err != nil {
-		return err
+		return fmt.Errorf("error inserting ficha: %w", err)
 	}
 
-	for _, actividad := range actividades {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_actividades_economicas (ruc_id, actividad_economica)
-			VALUES ($1, $2)`,
-			rucID, actividad)
-		if err != nil {
-			return err
+	for _, l := range ds.listasFicha(rucID, consultaID, info) {
+		if err := insertFilas(tx, l.tabla, l.columnas, l.filas, ""); err != nil {
+			return fmt.Errorf("error inserting %s: %w", l.nombre, err)
 		}
 	}
 	return nil
 }
 
-func (ds *DatabaseService) insertComprobantesPago(tx *sql.Tx, rucID int64, comprobantes []string) error {
-	_, err := tx.Exec("DELETE FROM ruc_comprobantes_pago WHERE ruc_id = $1", rucID)
+// buscarFoto calcula la huella de v y busca en tabla una consulta anterior del
+// RUC que ya guardó esos mismos datos; retorna la huella y el consulta_id de
+// esa consulta, o 0 si no hay
+func (ds *DatabaseService) buscarFoto(tx *sql.Tx, tabla string, rucID int64, v any) (string, int64, error) {
+	h, err := huella(v)
 	if err != nil {
-		return err
+		return "", 0, err
 	}
-
-	for _, comprobante := range comprobantes {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_comprobantes_pago (ruc_id, comprobante_pago)
-			VALUES ($1, $2)`,
-			rucID, comprobante)
-		if err != nil {
-			return err
-		}
+	var consultaID int64
+	err = tx.QueryRow(`SELECT consulta_id FROM `+tabla+` WHERE ruc_id = $1 AND huella = $2`,
+		rucID, h).Scan(&consultaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return h, 0, nil
 	}
-	return nil
+	return h, consultaID, err
 }
 
-func (ds *DatabaseService) insertSistemasEmisionElectronica(tx *sql.Tx, rucID int64, sistemas []string) error {
-	_, err := tx.Exec("DELETE FROM ruc_sistemas_emision_electronica WHERE ruc_id = $1", rucID)
+// huella es el SHA-256 del JSON de v; los mismos datos dan la misma huella
+func huella(v any) (string, error) {
+	b, err := json.Marshal(v)
 	if err != nil {
-		return err
+		return "", err
 	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
 
-	for _, sistema := range sistemas {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_sistemas_emision_electronica (ruc_id, sistema_emision)
-			VALUES ($1, $2)`,
-			rucID, sistema)
-		if err != nil {
-			return err
-		}
+// camposFicha son las columnas de la ficha, con el mismo nombre en
+// ruc_informacion_basica y ruc_fichas; valoresFicha da sus valores en este orden
+var camposFicha = append([]string{
+	"razon_social", "tipo_contribuyente", "tipo_documento", "numero_documento", "nombre_titular",
+	"nombre_comercial", "fecha_inscripcion", "fecha_inicio_actividades", "estado", "condicion",
+	"domicilio_fiscal", "sistema_emision", "actividad_comercio_exterior", "sistema_contabilidad",
+	"emisor_electronico_desde", "afiliado_ple",
+}, camposDomicilio...)
+
+var (
+	columnasFicha   = strings.Join(camposFicha, ", ")
+	actualizarFicha = asignarExcluded(camposFicha)
+	fichaCambiada   = distintoDeExcluded("ruc_informacion_basica", camposFicha)
+)
+
+func (ds *DatabaseService) valoresFicha(info *models.RUCInfo) []interface{} {
+	valores := []interface{}{
+		ds.nullString(info.RazonSocial),
+		ds.nullString(info.TipoContribuyente),
+		ds.nullString(string(info.TipoDocumento)),
+		ds.nullString(info.NumeroDocumento),
+		ds.nullString(info.NombreTitular),
+		ds.nullString(info.NombreComercial),
+		info.FechaInscripcion,
+		info.FechaInicioActividades,
+		ds.nullString(info.Estado),
+		ds.nullString(info.Condicion),
+		ds.nullString(info.DomicilioFiscal),
+		ds.nullString(info.SistemaEmision),
+		ds.nullString(info.ActividadComercioExterior),
+		ds.nullString(info.SistemaContabilidad),
+		info.EmisorElectronicoDesde,
+		info.AfiliadoPLE,
 	}
-	return nil
+	return append(valores, ds.valoresDomicilio(info.DomicilioFiscalDetalle)...)
 }
 
-func (ds *DatabaseService) insertComprobantesElectronicos(tx *sql.Tx, rucID int64, comprobantes []string) error {
-	_, err := tx.Exec("DELETE FROM ruc_comprobantes_electronicos WHERE ruc_id = $1", rucID)
-	if err != nil {
-		return err
+// lista son las filas de una tabla que se insertan juntas
+type lista struct {
+	nombre, tabla string
+	columnas      []string
+	filas         [][]interface{}
+}
+
+// listasFicha son las actividades económicas, comprobantes de pago, sistemas
+// de emisión electrónica, comprobantes electrónicos y padrones de la ficha
+func (ds *DatabaseService) listasFicha(rucID, consultaID int64, info *models.RUCInfo) []lista {
+	actividades := lista{nombre: "actividades economicas", tabla: "ruc_actividades_economicas",
+		columnas: []string{"ruc_id", "consulta_id", "actividad_economica", "tipo", "orden",
+			"codigo_ciiu", "revision_ciiu", "descripcion", "seccion_ciiu", "division_ciiu"}}
+	for _, actividad := range info.ActividadesEconomicas {
+		// Una fila sin código CIIU reconocido guarda NULL en tipo, código y revisión
+		revision := sql.NullInt64{Int64: int64(actividad.RevisionCIIU), Valid: actividad.RevisionCIIU != 0}
+		actividades.filas = append(actividades.filas, []interface{}{
+			rucID, consultaID, actividad.String(), ds.nullString(string(actividad.Tipo)), actividad.Orden,
+			ds.nullString(actividad.CodigoCIIU), revision, actividad.Descripcion,
+			ds.nullString(actividad.SeccionCIIU), ds.nullString(actividad.DivisionCIIU)})
 	}
 
-	for _, comprobante := range comprobantes {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_comprobantes_electronicos (ruc_id, comprobante_electronico)
-			VALUES ($1, $2)`,
-			rucID, comprobante)
-		if err != nil {
-			return err
+	listas := []lista{actividades}
+	textos := []struct {
+		nombre, tabla, columna string
+		valores                []string
+	}{
+		{"comprobantes pago", "ruc_comprobantes_pago", "comprobante_pago", info.ComprobantesPago},
+		{"sistemas emision electronica", "ruc_sistemas_emision_electronica", "sistema_emision", info.SistemaEmisionElectronica},
+		{"comprobantes electronicos", "ruc_comprobantes_electronicos", "comprobante_electronico", info.ComprobantesElectronicos},
+		{"padrones", "ruc_padrones", "padron", info.Padrones},
+	}
+	for _, t := range textos {
+		l := lista{nombre: t.nombre, tabla: t.tabla, columnas: []string{"ruc_id", "consulta_id", t.columna}}
+		for _, valor := range t.valores {
+			l.filas = append(l.filas, []interface{}{rucID, consultaID, valor})
 		}
+		listas = append(listas, l)
 	}
-	return nil
+	return listas
 }
 
-func (ds *DatabaseService) insertPadrones(tx *sql.Tx, rucID int64, padrones []string) error {
-	_, err := tx.Exec("DELETE FROM ruc_padrones WHERE ruc_id = $1", rucID)
+// insertConsulta registra la consulta con la consulta que tiene su ficha
+// (fichaID); si fichaID es 0 la ficha es nueva y la guarda esta misma consulta
+func (ds *DatabaseService) insertConsulta(tx *sql.Tx, rucID, fichaID int64, ruc *models.RUCCompleto) (int64, error) {
+	var consultaID int64
+	err := tx.QueryRow(`SELECT nextval(pg_get_serial_sequence('ruc_consultas', 'id'))`).Scan(&consultaID)
 	if err != nil {
-		return err
+		return 0, err
 	}
-
-	for _, padron := range padrones {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_padrones (ruc_id, padron)
-			VALUES ($1, $2)`,
-			rucID, padron)
-		if err != nil {
-			return err
-		}
+	if fichaID == 0 {
+		fichaID = consultaID
 	}
-	return nil
+
+	_, err = tx.Exec(`
+		INSERT INTO ruc_consultas (id, ruc_id, fecha_consulta, version_api, ficha_consulta_id)
+		VALUES ($1, $2, $3, $4, $5)`,
+		consultaID, rucID, ruc.FechaConsulta, ruc.VersionAPI, fichaID)
+	return consultaID, err
 }
 
-func (ds *DatabaseService) insertConsulta(tx *sql.Tx, rucID int64, ruc *models.RUCCompleto) error {
-	_, err := tx.Exec(`
-		INSERT INTO ruc_consultas (ruc_id, fecha_consulta, version_api)
-		VALUES ($1, $2, $3)`,
-		rucID, ruc.FechaConsulta, ruc.VersionAPI)
-	return err
+// insertEstadoSecciones guarda el estado de cada sección, su paginación y, en
+// datos_consulta_id, la consulta que tiene sus datos (NULL si la sección no
+// guardó datos)
+func (ds *DatabaseService) insertEstadoSecciones(tx *sql.Tx, rucID, consultaID int64, ruc *models.RUCCompleto, datos map[string]int64) error {
+	return insertFilas(tx, "ruc_consulta_secciones", columnasEstadoSecciones,
+		filasEstadoSecciones(rucID, consultaID, ruc, datos), "")
 }
 
-func (ds *DatabaseService) insertInformacionHistorica(tx *sql.Tx, rucID int64, info *models.InformacionHistorica) error {
-	// Insertar registro principal
-	var histID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_informacion_historica (ruc_id)
-		VALUES ($1) RETURNING id`, rucID).Scan(&histID)
-	if err != nil {
-		return err
+var columnasEstadoSecciones = []string{"ruc_id", "consulta_id", "seccion", "estado", "datos_consulta_id",
+	"paginacion_detectada", "paginas", "filas", "total_reportado", "verificado"}
+
+// filasEstadoSecciones arma una fila por sección de EstadoSecciones. La
+// paginación está en RUCCompleto por Nombre de la sección y se guarda en la
+// fila de su clave; la de una sección sin estado no se guarda.
+func filasEstadoSecciones(rucID, consultaID int64, ruc *models.RUCCompleto, datos map[string]int64) [][]interface{} {
+	filas := make([][]interface{}, 0, len(ruc.EstadoSecciones))
+	for seccion, estado := range ruc.EstadoSecciones {
+		datosID := sql.NullInt64{Int64: datos[seccion], Valid: datos[seccion] != 0}
+		fila := []interface{}{rucID, consultaID, seccion, string(estado), datosID}
+
+		var detectada sql.NullBool
+		var paginas, filasPag, total sql.NullInt64
+		var verificado sql.NullBool
+		if sec, ok := secciones.Buscar(seccion); ok {
+			if d, ok := ruc.DeteccionPaginacion[sec.Nombre]; ok {
+				detectada = sql.NullBool{Bool: d, Valid: true}
+			}
+			if p, ok := ruc.Paginacion[sec.Nombre]; ok {
+				paginas = sql.NullInt64{Int64: int64(p.Paginas), Valid: true}
+				filasPag = sql.NullInt64{Int64: int64(p.Filas), Valid: true}
+				total = sql.NullInt64{Int64: int64(p.TotalReportado), Valid: true}
+				verificado = sql.NullBool{Bool: p.Verificado, Valid: true}
+			}
+		}
+		filas = append(filas, append(fila, detectada, paginas, filasPag, total, verificado))
 	}
+	return filas
+}
 
-	// Insertar razones sociales históricas
+func (ds *DatabaseService) fotoInformacionHistorica(info *models.InformacionHistorica) *foto {
+	// Razones sociales históricas
+	razones := detalle{tabla: "ruc_razones_sociales_historicas", fk: "informacion_historica_id",
+		columnas: []string{"nombre", "fecha_de_baja"},
+		clave:    []string{"nombre", "fecha_de_baja"}}
 	for _, razon := range info.RazonesSociales {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_razones_sociales_historicas (informacion_historica_id, nombre, fecha_de_baja)
-			VALUES ($1, $2, $3)`,
-			histID, ds.nullString(razon.Nombre), ds.parseDate(razon.FechaDeBaja))
-		if err != nil {
-			return err
-		}
+		razones.filas = append(razones.filas, []interface{}{ds.nullString(razon.Nombre), razon.FechaDeBaja})
 	}
 
-	// Insertar condiciones históricas
+	// Condiciones históricas
+	condiciones := detalle{tabla: "ruc_condiciones_historicas", fk: "informacion_historica_id",
+		columnas: []string{"condicion", "desde", "hasta"},
+		clave:    []string{"condicion", "desde"}}
 	for _, condicion := range info.Condiciones {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_condiciones_historicas (informacion_historica_id, condicion, desde, hasta)
-			VALUES ($1, $2, $3, $4)`,
-			histID, ds.nullString(condicion.Condicion),
-			ds.parseDate(condicion.Desde), ds.parseDate(condicion.Hasta))
-		if err != nil {
-			return err
-		}
+		condiciones.filas = append(condiciones.filas, []interface{}{
+			ds.nullString(condicion.Condicion), condicion.Desde, condicion.Hasta})
 	}
 
-	// Insertar domicilios históricos
+	// Domicilios históricos
+	domicilios := detalle{tabla: "ruc_domicilios_fiscales_historicos", fk: "informacion_historica_id",
+		columnas: append([]string{"direccion", "fecha_de_baja"}, camposDomicilio...),
+		clave:    []string{"direccion", "fecha_de_baja"}}
 	for _, domicilio := range info.Domicilios {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_domicilios_fiscales_historicos (informacion_historica_id, direccion, fecha_de_baja)
-			VALUES ($1, $2, $3)`,
-			histID, ds.nullString(domicilio.Direccion), ds.parseDate(domicilio.FechaDeBaja))
-		if err != nil {
-			return err
-		}
+		fila := []interface{}{ds.nullString(domicilio.Direccion), domicilio.FechaDeBaja}
+		domicilios.filas = append(domicilios.filas, append(fila, ds.valoresDomicilio(domicilio.DireccionDetalle)...))
 	}
 
-	return nil
+	return &foto{tabla: "ruc_informacion_historica", datos: info,
+		detalles: []detalle{razones, condiciones, domicilios}}
 }
 
-func (ds *DatabaseService) insertDeudaCoactiva(tx *sql.Tx, rucID int64, deuda *models.DeudaCoactiva) error {
-	// Insertar registro principal
-	var deudaID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_deuda_coactiva (ruc_id, total_deuda, cantidad_documentos)
-		VALUES ($1, $2, $3) RETURNING id`,
-		rucID, deuda.TotalDeuda, deuda.CantidadDocumentos).Scan(&deudaID)
-	if err != nil {
-		return err
+func (ds *DatabaseService) fotoDeudaCoactiva(deuda *models.DeudaCoactiva) *foto {
+	// Detalle de deudas; SUNAT no muestra el documento, así que no hay clave natural
+	deudas := detalle{tabla: "ruc_detalle_deudas", fk: "deuda_coactiva_id",
+		columnas: []string{"monto", "moneda", "periodo_tributario", "fecha_inicio_cobranza", "entidad"}}
+	for _, detalle := range deuda.Deudas {
+		deudas.filas = append(deudas.filas, []interface{}{
+			detalle.Monto, moneda(detalle.Monto), detalle.PeriodoTributario,
+			detalle.FechaInicioCobranza, detalle.Entidad})
 	}
 
-	// Insertar detalle de deudas
-	for _, detalle := range deuda.Deudas {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_detalle_deudas (deuda_coactiva_id, monto, periodo_tributario, fecha_inicio_cobranza, entidad)
-			VALUES ($1, $2, $3, $4, $5)`,
-			deudaID, detalle.Monto, detalle.PeriodoTributario,
-			ds.parseDate(detalle.FechaInicioCobranza), detalle.Entidad)
-		if err != nil {
-			return err
-		}
+	// Las filas que no se pudieron interpretar se guardan para revisarlas
+	invalidas := detalle{tabla: "ruc_deuda_filas_invalidas", fk: "deuda_coactiva_id",
+		columnas: []string{"fila", "texto", "error"}}
+	for _, fila := range deuda.FilasInvalidas {
+		invalidas.filas = append(invalidas.filas, []interface{}{fila.Fila, fila.Texto, fila.Error})
 	}
 
-	return nil
+	return &foto{tabla: "ruc_deuda_coactiva", datos: deuda,
+		columnas: []string{"total_deuda", "moneda", "cantidad_documentos"},
+		valores:  []interface{}{deuda.TotalDeuda, moneda(deuda.TotalDeuda), deuda.CantidadDocumentos},
+		detalles: []detalle{deudas, invalidas}}
 }
 
-func (ds *DatabaseService) insertOmisionesTributarias(tx *sql.Tx, rucID int64, omisiones *models.OmisionesTributarias) error {
-	// Insertar registro principal
-	var omisionesID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_omisiones_tributarias (ruc_id, tiene_omisiones, cantidad_omisiones)
-		VALUES ($1, $2, $3) RETURNING id`,
-		rucID, omisiones.TieneOmisiones, omisiones.CantidadOmisiones).Scan(&omisionesID)
-	if err != nil {
-		return err
+// camposDomicilio son las columnas de models.Domicilio, con el mismo nombre en
+// ruc_informacion_basica, ruc_domicilios_fiscales_historicos y ruc_establecimientos
+var camposDomicilio = []string{
+	"tipo_via", "nombre_via", "numero", "interior", "manzana", "lote",
+	"tipo_urbanizacion", "urbanizacion", "departamento", "provincia", "distrito", "ubigeo",
+}
+
+var columnasDomicilio = strings.Join(camposDomicilio, ", ")
+
+// asignarExcluded retorna "c = EXCLUDED.c, ..." para el ON CONFLICT DO UPDATE
+func asignarExcluded(campos []string) string {
+	asignaciones := make([]string, len(campos))
+	for i, c := range campos {
+		asignaciones[i] = c + " = EXCLUDED." + c
 	}
+	return strings.Join(asignaciones, ",\n\t\t")
+}
 
-	// Insertar detalle de omisiones
-	for _, omision := range omisiones.Omisiones {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_omisiones (omisiones_tributarias_id, periodo, tributo, tipo_declaracion, fecha_vencimiento, estado)
-			VALUES ($1, $2, $3, $4, $5, $6)`,
-			omisionesID, omision.Periodo, omision.Tributo, omision.TipoDeclaracion,
-			ds.parseDate(omision.FechaVencimiento), omision.Estado)
-		if err != nil {
-			return err
-		}
+// valoresDomicilio retorna los valores de camposDomicilio, todos NULL si no hay dirección
+func (ds *DatabaseService) valoresDomicilio(d *models.Domicilio) []interface{} {
+	if d == nil {
+		d = &models.Domicilio{}
+	}
+	return []interface{}{
+		ds.nullString(d.TipoVia), ds.nullString(d.NombreVia), ds.nullString(d.Numero),
+		ds.nullString(d.Interior), ds.nullString(d.Manzana), ds.nullString(d.Lote),
+		ds.nullString(d.TipoUrbanizacion), ds.nullString(d.Urbanizacion),
+		ds.nullString(d.Departamento), ds.nullString(d.Provincia), ds.nullString(d.Distrito),
+		ds.nullString(d.Ubigeo),
 	}
+}
 
-	return nil
+// distintoDeExcluded retorna "(t.c, ...) IS DISTINCT FROM (EXCLUDED.c, ...)"
+// para no actualizar en el ON CONFLICT una fila que no cambió
+func distintoDeExcluded(tabla string, campos []string) string {
+	actuales := make([]string, len(campos))
+	nuevos := make([]string, len(campos))
+	for i, c := range campos {
+		actuales[i] = tabla + "." + c
+		nuevos[i] = "EXCLUDED." + c
+	}
+	return "(" + strings.Join(actuales, ", ") + ") IS DISTINCT FROM (" + strings.Join(nuevos, ", ") + ")"
 }
 
-func (ds *DatabaseService) insertCantidadTrabajadores(tx *sql.Tx, rucID int64, trabajadores *models.CantidadTrabajadores) error {
-	// Insertar registro principal
-	var trabajadoresID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_cantidad_trabajadores (ruc_id)
-		VALUES ($1) RETURNING id`, rucID).Scan(&trabajadoresID)
-	if err != nil {
-		return err
+// marcadores retorna "$desde, $desde+1, ..." con n parámetros
+func marcadores(desde, n int) string {
+	m := make([]string, n)
+	for i := range m {
+		m[i] = fmt.Sprintf("$%d", desde+i)
 	}
+	return strings.Join(m, ", ")
+}
 
-	// Insertar períodos disponibles
-	for _, periodo := range trabajadores.PeriodosDisponibles {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_periodos_disponibles_trabajadores (cantidad_trabajadores_id, periodo)
-			VALUES ($1, $2)`,
-			trabajadoresID, periodo)
-		if err != nil {
-			return err
-		}
+// moneda retorna la moneda del monto; PEN si no tiene (total sin deudas)
+func moneda(m models.Monto) string {
+	if m.Moneda == "" {
+		return string(models.MonedaPEN)
 	}
+	return string(m.Moneda)
+}
 
-	// Insertar detalle por período
-	for _, detalle := range trabajadores.DetallePorPeriodo {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_detalle_trabajadores (
-				cantidad_trabajadores_id, periodo, cantidad_trabajadores, 
-				cantidad_prestadores_servicio, cantidad_pensionistas, total
-			) VALUES ($1, $2, $3, $4, $5, $6)`,
-			trabajadoresID, detalle.Periodo, detalle.CantidadTrabajadores,
-			detalle.CantidadPrestadoresServicio, detalle.CantidadPensionistas, detalle.Total)
-		if err != nil {
-			return err
-		}
+func (ds *DatabaseService) fotoOmisionesTributarias(omisiones *models.OmisionesTributarias) *foto {
+	detalleOmisiones := detalle{tabla: "ruc_omisiones", fk: "omisiones_tributarias_id",
+		columnas: []string{"periodo", "tributo", "tipo_declaracion", "fecha_vencimiento", "estado"},
+		clave:    []string{"periodo", "tributo"}}
+	for _, omision := range omisiones.Omisiones {
+		detalleOmisiones.filas = append(detalleOmisiones.filas, []interface{}{
+			omision.Periodo, omision.Tributo, omision.TipoDeclaracion,
+			omision.FechaVencimiento, omision.Estado})
 	}
 
-	return nil
+	return &foto{tabla: "ruc_omisiones_tributarias", datos: omisiones,
+		columnas: []string{"tiene_omisiones", "cantidad_omisiones"},
+		valores:  []interface{}{omisiones.TieneOmisiones, omisiones.CantidadOmisiones},
+		detalles: []detalle{detalleOmisiones}}
 }
 
-func (ds *DatabaseService) insertActasProbatorias(tx *sql.Tx, rucID int64, actas *models.ActasProbatorias) error {
-	// Insertar registro principal
-	var actasID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_actas_probatorias (ruc_id, tiene_actas, cantidad_actas)
-		VALUES ($1, $2, $3) RETURNING id`,
-		rucID, actas.TieneActas, actas.CantidadActas).Scan(&actasID)
-	if err != nil {
-		return err
+func (ds *DatabaseService) fotoCantidadTrabajadores(trabajadores *models.CantidadTrabajadores) *foto {
+	// Períodos disponibles
+	periodos := detalle{tabla: "ruc_periodos_disponibles_trabajadores", fk: "cantidad_trabajadores_id",
+		columnas: []string{"periodo"},
+		clave:    []string{"periodo"}}
+	for _, periodo := range trabajadores.PeriodosDisponibles {
+		periodos.filas = append(periodos.filas, []interface{}{periodo})
+	}
+
+	// Detalle por período
+	porPeriodo := detalle{tabla: "ruc_detalle_trabajadores", fk: "cantidad_trabajadores_id",
+		columnas: []string{"periodo", "cantidad_trabajadores",
+			"cantidad_prestadores_servicio", "cantidad_pensionistas", "total"},
+		clave: []string{"periodo"}}
+	for _, detalle := range trabajadores.DetallePorPeriodo {
+		porPeriodo.filas = append(porPeriodo.filas, []interface{}{
+			detalle.Periodo, detalle.CantidadTrabajadores,
+			detalle.CantidadPrestadoresServicio, detalle.CantidadPensionistas, detalle.Total})
 	}
 
-	// Insertar detalle de actas
+	return &foto{tabla: "ruc_cantidad_trabajadores", datos: trabajadores,
+		detalles: []detalle{periodos, porPeriodo}}
+}
+
+func (ds *DatabaseService) fotoActasProbatorias(actas *models.ActasProbatorias) *foto {
+	detalleActas := detalle{tabla: "ruc_actas", fk: "actas_probatorias_id",
+		columnas: []string{"numero_acta", "fecha_acta", "lugar_intervencion",
+			"articulo_numeral", "descripcion_infraccion", "numero_ri_roz", "tipo_ri_roz", "acta_reconocimiento"},
+		clave: []string{"numero_acta"}}
 	for _, acta := range actas.Actas {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_actas (
-				actas_probatorias_id, numero_acta, fecha_acta, lugar_intervencion,
-				articulo_numeral, descripcion_infraccion, numero_ri_roz, tipo_ri_roz, acta_reconocimiento
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-			actasID, acta.NumeroActa, ds.parseDate(acta.FechaActa), acta.LugarIntervencion,
+		detalleActas.filas = append(detalleActas.filas, []interface{}{
+			acta.NumeroActa, acta.FechaActa, acta.LugarIntervencion,
 			acta.ArticuloNumeral, acta.DescripcionInfraccion, acta.NumeroRIROZ,
-			acta.TipoRIROZ, acta.ActaReconocimiento)
-		if err != nil {
-			return err
-		}
+			acta.TipoRIROZ, acta.ActaReconocimiento})
 	}
 
-	return nil
+	return &foto{tabla: "ruc_actas_probatorias", datos: actas,
+		columnas: []string{"tiene_actas", "cantidad_actas"},
+		valores:  []interface{}{actas.TieneActas, actas.CantidadActas},
+		detalles: []detalle{detalleActas}}
 }
 
-func (ds *DatabaseService) insertFacturasFisicas(tx *sql.Tx, rucID int64, facturas *models.FacturasFisicas) error {
-	// Insertar registro principal
-	var facturasID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_facturas_fisicas (ruc_id, tiene_autorizacion)
-		VALUES ($1, $2) RETURNING id`,
-		rucID, facturas.TieneAutorizacion).Scan(&facturasID)
-	if err != nil {
-		return err
-	}
+func (ds *DatabaseService) fotoFacturasFisicas(facturas *models.FacturasFisicas) *foto {
+	columnas := []string{"numero_autorizacion", "fecha_autorizacion",
+		"tipo_comprobante", "serie", "numero_inicial", "numero_final"}
 
-	// Insertar facturas autorizadas
+	// Facturas autorizadas
+	autorizadas := detalle{tabla: "ruc_facturas_autorizadas", fk: "facturas_fisicas_id",
+		columnas: columnas,
+		clave:    []string{"numero_autorizacion", "serie", "numero_inicial"}}
 	for _, autorizada := range facturas.Autorizaciones {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_facturas_autorizadas (
-				facturas_fisicas_id, numero_autorizacion, fecha_autorizacion, 
-				tipo_comprobante, serie, numero_inicial, numero_final
-			) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-			facturasID, autorizada.NumeroAutorizacion, ds.parseDate(autorizada.FechaAutorizacion),
-			autorizada.TipoComprobante, autorizada.Serie, autorizada.NumeroInicial, autorizada.NumeroFinal)
-		if err != nil {
-			return err
-		}
+		autorizadas.filas = append(autorizadas.filas, []interface{}{
+			autorizada.NumeroAutorizacion, autorizada.FechaAutorizacion,
+			autorizada.TipoComprobante, autorizada.Serie, autorizada.NumeroInicial, autorizada.NumeroFinal})
 	}
 
-	// Insertar facturas canceladas o de baja
+	// Facturas canceladas o de baja
+	canceladas := detalle{tabla: "ruc_facturas_canceladas_bajas", fk: "facturas_fisicas_id",
+		columnas: columnas,
+		clave:    []string{"numero_autorizacion", "serie", "numero_inicial"}}
 	for _, cancelada := range facturas.CanceladasOBajas {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_facturas_canceladas_bajas (
-				facturas_fisicas_id, numero_autorizacion, fecha_autorizacion,
-				tipo_comprobante, serie, numero_inicial, numero_final
-			) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
-			facturasID, cancelada.NumeroAutorizacion, ds.parseDate(cancelada.FechaAutorizacion),
-			cancelada.TipoComprobante, cancelada.Serie, cancelada.NumeroInicial, cancelada.NumeroFinal)
-		if err != nil {
-			return err
-		}
+		canceladas.filas = append(canceladas.filas, []interface{}{
+			cancelada.NumeroAutorizacion, cancelada.FechaAutorizacion,
+			cancelada.TipoComprobante, cancelada.Serie, cancelada.NumeroInicial, cancelada.NumeroFinal})
 	}
 
-	return nil
+	return &foto{tabla: "ruc_facturas_fisicas", datos: facturas,
+		columnas: []string{"tiene_autorizacion"},
+		valores:  []interface{}{facturas.TieneAutorizacion},
+		detalles: []detalle{autorizadas, canceladas}}
 }
 
-func (ds *DatabaseService) insertReactivaPeru(tx *sql.Tx, rucID int64, reactiva *models.ReactivaPeru) error {
-	_, err := tx.Exec(`
-		INSERT INTO ruc_reactiva_peru (ruc_id, razon_social, tiene_deuda_coactiva, fecha_actualizacion, referencia_legal)
-		VALUES ($1, $2, $3, $4, $5)`,
-		rucID, ds.nullString(reactiva.RazonSocial), reactiva.TieneDeudaCoactiva,
-		ds.parseDate(reactiva.FechaActualizacion), ds.nullString(reactiva.ReferenciaLegal))
-	return err
+func (ds *DatabaseService) fotoReactivaPeru(reactiva *models.ReactivaPeru) *foto {
+	return &foto{tabla: "ruc_reactiva_peru", datos: reactiva,
+		columnas: []string{"razon_social", "tiene_deuda_coactiva", "fecha_actualizacion", "referencia_legal"},
+		valores: []interface{}{ds.nullString(reactiva.RazonSocial), reactiva.TieneDeudaCoactiva,
+			reactiva.FechaActualizacion, ds.nullString(reactiva.ReferenciaLegal)}}
 }
 
-func (ds *DatabaseService) insertProgramaCovid19(tx *sql.Tx, rucID int64, covid *models.ProgramaCovid19) error {
-	_, err := tx.Exec(`
-		INSERT INTO ruc_programa_covid19 (ruc_id, razon_social, participa_programa, tiene_deuda_coactiva, fecha_actualizacion, base_legal)
-		VALUES ($1, $2, $3, $4, $5, $6)`,
-		rucID, ds.nullString(covid.RazonSocial), covid.ParticipaPrograma, covid.TieneDeudaCoactiva,
-		ds.parseDate(covid.FechaActualizacion), ds.nullString(covid.BaseLegal))
-	return err
+func (ds *DatabaseService) fotoProgramaCovid19(covid *models.ProgramaCovid19) *foto {
+	return &foto{tabla: "ruc_programa_covid19", datos: covid,
+		columnas: []string{"razon_social", "participa_programa", "tiene_deuda_coactiva", "fecha_actualizacion", "base_legal"},
+		valores: []interface{}{ds.nullString(covid.RazonSocial), covid.ParticipaPrograma, covid.TieneDeudaCoactiva,
+			covid.FechaActualizacion, ds.nullString(covid.BaseLegal)}}
 }
 
-func (ds *DatabaseService) insertRepresentantesLegales(tx *sql.Tx, rucID int64, representantes *models.RepresentantesLegales) error {
-	// Insertar registro principal
-	var representantesID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_representantes_legales (ruc_id)
-		VALUES ($1) RETURNING id`, rucID).Scan(&representantesID)
-	if err != nil {
-		return err
-	}
-
-	// Insertar representantes
+func (ds *DatabaseService) fotoRepresentantesLegales(representantes *models.RepresentantesLegales) *foto {
+	reps := detalle{tabla: "ruc_representantes", fk: "representantes_legales_id",
+		columnas: []string{"persona_id", "tipo_documento", "numero_documento",
+			"nombre_completo", "apellidos", "nombres", "cargo", "cargo_sunat",
+			"fecha_desde", "fecha_hasta", "vigente"},
+		clave: []string{"tipo_documento", "numero_documento", "cargo_sunat"}}
 	for _, rep := range representantes.Representantes {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_representantes (
-				representantes_legales_id, tipo_documento, numero_documento,
-				nombre_completo, cargo, fecha_desde, fecha_hasta, vigente
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-			representantesID, rep.TipoDocumento, rep.NumeroDocumento, rep.NombreCompleto,
-			rep.Cargo, ds.parseDate(rep.FechaDesde), ds.parseDate(rep.FechaHasta), rep.Vigente)
-		if err != nil {
-			return err
+		// persona_id se resuelve al insertar (detalle.filasDe); NULL sin documento reconocido
+		var persona interface{}
+		if k, ok := clavePersonaDe(rep); ok {
+			persona = k
 		}
+		reps.filas = append(reps.filas, []interface{}{
+			persona, ds.nullString(string(rep.TipoDocumento)), rep.NumeroDocumento,
+			rep.NombreCompleto, ds.nullString(rep.Apellidos), ds.nullString(rep.Nombres),
+			ds.nullString(string(rep.Cargo)), ds.nullString(rep.CargoSUNAT),
+			rep.FechaDesde, rep.FechaHasta, rep.Vigente})
 	}
 
-	return nil
+	return &foto{tabla: "ruc_representantes_legales", datos: representantes,
+		detalles: []detalle{reps}, personas: representantes.Representantes}
 }
 
-func (ds *DatabaseService) insertEstablecimientosAnexos(tx *sql.Tx, rucID int64, establecimientos *models.EstablecimientosAnexos) error {
-	// Insertar registro principal
-	var establecimientosID int64
-	err := tx.QueryRow(`
-		INSERT INTO ruc_establecimientos_anexos (ruc_id, cantidad_anexos)
-		VALUES ($1, $2) RETURNING id`,
-		rucID, establecimientos.CantidadAnexos).Scan(&establecimientosID)
-	if err != nil {
-		return err
-	}
+// clavePersona identifica a una persona en personas por su documento
+type clavePersona struct {
+	tipoDocumento, numeroDocumento string
+}
 
-	// Insertar establecimientos
-	for _, est := range establecimientos.Establecimientos {
-		_, err := tx.Exec(`
-			INSERT INTO ruc_establecimientos (
-				establecimientos_anexos_id, codigo, tipo_establecimiento, direccion, actividad_economica
-			) VALUES ($1, $2, $3, $4, $5)`,
-			establecimientosID, est.Codigo, est.TipoEstablecimiento,
-			ds.nullString(est.Direccion), ds.nullString(est.ActividadEconomica))
-		if err != nil {
-			return err
-		}
+// clavePersonaDe retorna la clave del representante; sin tipo de documento
+// reconocido no se puede identificar a la persona
+func clavePersonaDe(rep models.RepresentanteLegal) (clavePersona, bool) {
+	if rep.TipoDocumento == "" {
+		return clavePersona{}, false
 	}
-
-	return nil
+	return clavePersona{string(rep.TipoDocumento), rep.NumeroDocumento}, true
 }
 
-// Helper functions
-func (ds *DatabaseService) nullString(s string) interface{} {
-	if s == "" || s == "-" || s == "No hay información" {
-		return nil
+// upsertPersonas registra a los representantes en personas y retorna el id de
+// cada uno por su clave. El nombre más reciente reemplaza al anterior; un
+// nombre sin separar no borra apellidos y nombres ya conocidos.
+func (ds *DatabaseService) upsertPersonas(tx *sql.Tx, reps []models.RepresentanteLegal) (map[clavePersona]int64, error) {
+	// Una persona repetida va una sola vez: ON CONFLICT DO UPDATE no puede
+	// tocar dos veces la misma fila en una sentencia
+	var claves []clavePersona
+	personas := make(map[clavePersona]*models.RepresentanteLegal)
+	for _, rep := range reps {
+		k, ok := clavePersonaDe(rep)
+		if !ok {
+			continue
+		}
+		p, repetida := personas[k]
+		if !repetida {
+			p = &models.RepresentanteLegal{}
+			personas[k] = p
+			claves = append(claves, k)
+		}
+		p.NombreCompleto = rep.NombreCompleto
+		if rep.Apellidos != "" {
+			p.Apellidos = rep.Apellidos
+		}
+		if rep.Nombres != "" {
+			p.Nombres = rep.Nombres
+		}
 	}
-	return s
-}
 
-func (ds *DatabaseService) parseDate(dateStr string) interface{} {
-	if dateStr == "" || dateStr == "-" || dateStr == "No hay información" {
-		return nil
+	ids := make(map[clavePersona]int64, len(claves))
+	if len(claves) == 0 {
+		return ids, nil
 	}
 
-	formats := []string{
-		"02/01/2006",
-		"2006-01-02",
-		"01/02/2006",
+	filas := make([][]interface{}, len(claves))
+	tipos := make([]string, len(claves))
+	numeros := make([]string, len(claves))
+	for i, k := range claves {
+		p := personas[k]
+		filas[i] = []interface{}{k.tipoDocumento, k.numeroDocumento, p.NombreCompleto,
+			ds.nullString(p.Apellidos), ds.nullString(p.Nombres)}
+		tipos[i], numeros[i] = k.tipoDocumento, k.numeroDocumento
+	}
+	err := insertFilas(tx, "personas",
+		[]string{"tipo_documento", "numero_documento", "nombre_completo", "apellidos", "nombres"}, filas, `
+		ON CONFLICT (tipo_documento, numero_documento) DO UPDATE SET
+			nombre_completo = EXCLUDED.nombre_completo,
+			apellidos = COALESCE(EXCLUDED.apellidos, personas.apellidos),
+			nombres = COALESCE(EXCLUDED.nombres, personas.nombres),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE (personas.nombre_completo, personas.apellidos, personas.nombres) IS DISTINCT FROM
+			(EXCLUDED.nombre_completo, COALESCE(EXCLUDED.apellidos, personas.apellidos), COALESCE(EXCLUDED.nombres, personas.nombres))`)
+	if err != nil {
+		return nil, fmt.Errorf("error upserting personas: %w", err)
 	}
 
-	for _, format := range formats {
-		if date, err := time.Parse(format, dateStr); err == nil {
-			return date
+	// Las personas que no cambiaron no pasan por el UPDATE: los id se leen aparte
+	rows, err := tx.Query(`
+		SELECT tipo_documento, numero_documento, id FROM personas
+		WHERE (tipo_documento, numero_documento) IN (SELECT * FROM unnest($1::text[], $2::text[]))`,
+		pq.Array(tipos), pq.Array(numeros))
+	if err != nil {
+		return nil, fmt.Errorf("error reading personas: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k clavePersona
+		var id int64
+		if err := rows.Scan(&k.tipoDocumento, &k.numeroDocumento, &id); err != nil {
+			return nil, fmt.Errorf("error reading personas: %w", err)
 		}
+		ids[k] = id
 	}
+	return ids, rows.Err()
+}
 
-	return nil
+func (ds *DatabaseService) fotoEstablecimientosAnexos(establecimientos *models.EstablecimientosAnexos) *foto {
+	detalleEstablecimientos := detalle{tabla: "ruc_establecimientos", fk: "establecimientos_anexos_id",
+		columnas: append([]string{"codigo", "tipo_establecimiento", "direccion", "actividad_economica"},
+			camposDomicilio...),
+		clave: []string{"codigo"}}
+	for _, est := range establecimientos.Establecimientos {
+		fila := []interface{}{est.Codigo, est.TipoEstablecimiento,
+			ds.nullString(est.Direccion), ds.nullString(est.ActividadEconomica)}
+		detalleEstablecimientos.filas = append(detalleEstablecimientos.filas,
+			append(fila, ds.valoresDomicilio(est.DireccionDetalle)...))
+	}
+
+	return &foto{tabla: "ruc_establecimientos_anexos", datos: establecimientos,
+		columnas: []string{"cantidad_anexos"},
+		valores:  []interface{}{establecimientos.CantidadAnexos},
+		detalles: []detalle{detalleEstablecimientos}}
 }
 
-func (ds *DatabaseService) GetRUCByNumber(rucNumber string) (*models.RUCCompleto, error) {
-	return nil, fmt.Errorf("GetRUCByNumber not implemented for normalized schema")
+// Helper functions
+func (ds *DatabaseService) nullString(s string) interface{} {
+	if s == "" || s == "-" || s == "No hay información" {
+		return nil
+	}
+	return s
 }
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/diff"
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/secciones"
+)
+
+// Una consulta con --sections que no pide la deuda no debe ocultar la deuda
+// nueva que aparece entre la consulta completa anterior y la siguiente
+func TestInsertCambiosConsultaSelectiva(t *testing.T) {
+	ds := baseDePrueba(t)
+	inicio := time.Now().Add(-time.Hour)
+
+	completa := consultasDePrueba(1)[0]
+	completa.FechaConsulta = inicio
+	if err := ds.InsertRUCCompleto(completa); err != nil {
+		t.Fatal(err)
+	}
+
+	selectiva := *completa
+	selectiva.FechaConsulta = inicio.Add(time.Minute)
+	selectiva.DeudaCoactiva = nil
+	selectiva.EstadoSecciones = map[string]models.EstadoSeccion{"deuda_coactiva": models.SeccionNoSolicitada}
+	for clave := range completa.EstadoSecciones {
+		if clave != "deuda_coactiva" {
+			selectiva.EstadoSecciones[clave] = models.SeccionConsultada
+		}
+	}
+	if err := ds.InsertRUCCompleto(&selectiva); err != nil {
+		t.Fatal(err)
+	}
+
+	nueva := *completa
+	nueva.FechaConsulta = inicio.Add(2 * time.Minute)
+	nueva.DeudaCoactiva = &models.DeudaCoactiva{Deudas: append(append([]models.DetalleDeuda(nil), completa.DeudaCoactiva.Deudas...),
+		models.DetalleDeuda{Monto: models.Soles(90000), PeriodoTributario: models.NuevoPeriodo(2024, time.June),
+			FechaInicioCobranza: models.NuevaFecha(2024, time.September, 2), Entidad: "SUNAT"})}
+	if err := ds.InsertRUCCompleto(&nueva); err != nil {
+		t.Fatal(err)
+	}
+
+	cambios, err := ds.GetCambios(completa.InformacionBasica.RUC, inicio)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nuevas int
+	for _, c := range cambios {
+		if c.Tipo == diff.DeudaNueva {
+			nuevas++
+		}
+	}
+	if nuevas != 1 {
+		t.Errorf("se esperaba 1 deuda nueva, cambios: %v", cambios)
+	}
+}
+
+// Cada sección de SUNAT del registro debe guardarse y leerse
+func TestSeccionesGuardadas(t *testing.T) {
+	guardadas := make(map[string]bool)
+	for _, sec := range seccionesGuardadas() {
+		guardadas[sec.Clave] = true
+	}
+	for _, sec := range secciones.Todas() {
+		if !guardadas[sec.Clave] {
+			t.Errorf("%s no está en seccionesSUNAT ni tiene Escritor", sec.Clave)
+		}
+	}
+	if len(seccionesSUNAT) != len(guardadas) {
+		t.Errorf("seccionesSUNAT tiene %d secciones y el registro %d", len(seccionesSUNAT), len(guardadas))
+	}
+}
+
+func TestListasFichaActividadSinCodigo(t *testing.T) {
+	ds := &DatabaseService{}
+	info := &models.RUCInfo{ActividadesEconomicas: []models.ActividadEconomica{
+		{Tipo: models.ActividadPrincipal, CodigoCIIU: "6920", RevisionCIIU: 4, Descripcion: "ACTIVIDADES DE CONTABILIDAD"},
+		{Descripcion: "ACTIVIDAD SIN FORMATO"},
+	}}
+	actividades := ds.listasFicha(1, 2, info)[0]
+
+	// tipo, codigo_ciiu y revision_ciiu
+	conCodigo, sinCodigo := actividades.filas[0], actividades.filas[1]
+	if conCodigo[3] != "principal" || conCodigo[5] != "6920" || conCodigo[6] != (sql.NullInt64{Int64: 4, Valid: true}) {
+		t.Errorf("actividad con código = %v", conCodigo)
+	}
+	if sinCodigo[3] != nil || sinCodigo[5] != nil || sinCodigo[6] != (sql.NullInt64{}) {
+		t.Errorf("actividad sin código = %v, se esperaba NULL en tipo, código y revisión", sinCodigo)
+	}
+}
+
+// Volver a consultar un RUC sin cambios, uno por uno o en lote, no debe agregar
+// filas; una consulta con un cambio sí
+func TestConsultaRepetidaNoAgregaFilas(t *testing.T) {
+	ds := baseDePrueba(t)
+	inicio := time.Now().Add(-time.Hour)
+
+	primera := consultasDePrueba(1)[0]
+	primera.FechaConsulta = inicio
+	if err := ds.InsertRUCCompleto(primera); err != nil {
+		t.Fatal(err)
+	}
+	filas := func() int {
+		var n int
+		err := ds.db.QueryRow(`
+			SELECT (SELECT count(*) FROM ruc_consultas c WHERE c.ruc_id = b.id)
+			     + (SELECT count(*) FROM ruc_consulta_secciones s WHERE s.ruc_id = b.id)
+			FROM ruc_informacion_basica b WHERE b.ruc = $1`,
+			primera.InformacionBasica.RUC).Scan(&n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	antes := filas()
+
+	repetida := *primera
+	repetida.FechaConsulta = inicio.Add(time.Minute)
+	if err := ds.InsertRUCCompleto(&repetida); err != nil {
+		t.Fatal(err)
+	}
+	if n := filas(); n != antes {
+		t.Errorf("InsertRUCCompleto: %d filas tras repetir la consulta, se esperaban %d", n, antes)
+	}
+
+	enLote := *primera
+	enLote.FechaConsulta = inicio.Add(2 * time.Minute)
+	if r := ds.InsertRUCCompletoLote([]*models.RUCCompleto{&enLote}, 0); r[0].Err != nil {
+		t.Fatal(r[0].Err)
+	}
+	if n := filas(); n != antes {
+		t.Errorf("InsertRUCCompletoLote: %d filas tras repetir la consulta, se esperaban %d", n, antes)
+	}
+
+	cambiada := *primera
+	cambiada.FechaConsulta = inicio.Add(3 * time.Minute)
+	cambiada.InformacionBasica.Condicion = "NO HABIDO"
+	if err := ds.InsertRUCCompleto(&cambiada); err != nil {
+		t.Fatal(err)
+	}
+	if n := filas(); n <= antes {
+		t.Errorf("%d filas tras una consulta con cambios, se esperaban más de %d", n, antes)
+	}
+}
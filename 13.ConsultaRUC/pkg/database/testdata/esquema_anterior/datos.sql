@@ -0,0 +1,48 @@
+-- Filas como las guardaba el scraper con database/prueba.sql: la última ficha
+-- del RUC y, por cada consulta, las consultas adicionales con el mismo
+-- created_at que su fila en ruc_consultas. Lo usa TestMigrarEsquemaAnterior.
+
+INSERT INTO ruc_informacion_basica (id, ruc, razon_social, tipo_contribuyente, tipo_documento,
+    fecha_inscripcion, fecha_inicio_actividades, estado, condicion, domicilio_fiscal,
+    emisor_electronico_desde, afiliado_ple, created_at, updated_at)
+VALUES (7, '10428447851', 'PEREZ GOMEZ JUAN', 'PERSONA NATURAL SIN NEGOCIO', 'DNI 42844785 - PEREZ GOMEZ, JUAN',
+    '2010-03-15', '2010-03-15', 'ACTIVO', 'HABIDO', 'AV. LOS PINOS NRO. 123 LIMA - LIMA - MIRAFLORES',
+    '2019-01-01', '01/01/2013', '2024-01-10 09:00:00', '2024-02-10 09:00:00');
+
+INSERT INTO ruc_consultas (id, ruc_id, fecha_consulta, version_api, created_at) VALUES
+    (3, 7, '2024-01-10 09:00:00', '1.0.0', '2024-01-10 09:00:00'),
+    (5, 7, '2024-02-10 09:00:00', '1.0.0', '2024-02-10 09:00:00');
+
+-- La ficha solo queda la de la última consulta
+INSERT INTO ruc_actividades_economicas (ruc_id, actividad_economica, created_at) VALUES
+    (7, 'Principal - 6920 - ACTIVIDADES DE CONTABILIDAD', '2024-02-10 09:00:00'),
+    (7, 'Secundaria 1 - 7020 - ACTIVIDADES DE CONSULTORÍA DE GESTIÓN', '2024-02-10 09:00:00'),
+    (7, 'NO REGISTRA', '2024-02-10 09:00:00');
+INSERT INTO ruc_comprobantes_pago (ruc_id, comprobante_pago, created_at) VALUES
+    (7, 'RECIBO POR HONORARIOS', '2024-02-10 09:00:00');
+
+INSERT INTO ruc_deuda_coactiva (id, ruc_id, total_deuda, cantidad_documentos, created_at) VALUES
+    (11, 7, 1500.00, 1, '2024-01-10 09:00:00'),
+    (12, 7, 0, 0, '2024-02-10 09:00:00');
+INSERT INTO ruc_detalle_deudas (deuda_coactiva_id, monto, periodo_tributario, fecha_inicio_cobranza, entidad) VALUES
+    (11, 1500.00, '202312', '2024-01-05', 'SUNAT');
+
+-- Dos filas con el mismo periodo y tributo, y un periodo ilegible
+INSERT INTO ruc_omisiones_tributarias (id, ruc_id, tiene_omisiones, cantidad_omisiones, created_at) VALUES
+    (21, 7, true, 3, '2024-02-10 09:00:00');
+INSERT INTO ruc_omisiones (omisiones_tributarias_id, periodo, tributo, tipo_declaracion, fecha_vencimiento, estado) VALUES
+    (21, '202311', 'RENTA', 'PDT 621', '2023-12-18', 'OMISO'),
+    (21, '202311', 'RENTA', 'PDT 621', '2023-12-18', 'OMISO'),
+    (21, 'SIN PERIODO', 'IGV', 'PDT 621', NULL, 'OMISO');
+
+INSERT INTO ruc_representantes_legales (id, ruc_id, created_at) VALUES
+    (31, 7, '2024-02-10 09:00:00');
+INSERT INTO ruc_representantes (representantes_legales_id, tipo_documento, numero_documento, nombre_completo,
+    cargo, fecha_desde, vigente) VALUES
+    (31, 'DNI', '42844785', 'PEREZ GOMEZ, JUAN', 'TITULAR-GERENTE', '2010-03-15', true);
+
+-- Una sección sin consulta con su created_at
+INSERT INTO ruc_establecimientos_anexos (id, ruc_id, cantidad_anexos, created_at) VALUES
+    (41, 7, 1, '2024-03-01 12:00:00');
+INSERT INTO ruc_establecimientos (establecimientos_anexos_id, codigo, tipo_establecimiento, direccion) VALUES
+    (41, '0001', 'LOCAL COMERCIAL', 'JR. UNION NRO. 456 LIMA - LIMA - LIMA');
@@ -0,0 +1,230 @@
+// Package diff compara dos consultas de un mismo RUC y lista los cambios que
+// interesan para monitorear a un contribuyente: estado, condición, razón
+// social, domicilio fiscal, deudas coactivas, omisiones, actas,
+// representantes legales y establecimientos anexos.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+// Tipo es la clase de cambio entre dos consultas
+type Tipo string
+
+const (
+	CambioEstado          Tipo = "estado"
+	CambioCondicion       Tipo = "condicion"
+	CambioRazonSocial     Tipo = "razon_social"
+	CambioDomicilioFiscal Tipo = "domicilio_fiscal"
+
+	DeudaNueva     Tipo = "deuda_nueva"
+	DeudaMonto     Tipo = "deuda_monto" // misma deuda con otro monto
+	DeudaCancelada Tipo = "deuda_cancelada"
+
+	OmisionNueva    Tipo = "omision_nueva"
+	OmisionResuelta Tipo = "omision_resuelta"
+
+	ActaNueva Tipo = "acta_nueva"
+
+	RepresentanteAlta Tipo = "representante_alta"
+	RepresentanteCese Tipo = "representante_cese"
+
+	EstablecimientoNuevo Tipo = "establecimiento_nuevo"
+	EstablecimientoBaja  Tipo = "establecimiento_baja"
+)
+
+// Cambio es una diferencia entre dos consultas. Clave identifica la fila en
+// las secciones con varias filas (periodo y tributo de una omisión, código de
+// un establecimiento, ...); Anterior y Nuevo son los valores como texto, vacíos
+// si la fila no existía en esa consulta.
+type Cambio struct {
+	Tipo     Tipo   `json:"tipo"`
+	Clave    string `json:"clave,omitempty"`
+	Anterior string `json:"anterior,omitempty"`
+	Nuevo    string `json:"nuevo,omitempty"`
+}
+
+func (c Cambio) String() string {
+	s := string(c.Tipo)
+	if c.Clave != "" {
+		s += " " + c.Clave
+	}
+	return fmt.Sprintf("%s: %q → %q", s, c.Anterior, c.Nuevo)
+}
+
+// Comparar lista los cambios de anterior a nuevo. Una sección que falta en
+// alguna de las dos consultas (no se pidió o no estaba disponible) no se
+// compara, porque no se sabe qué mostraba SUNAT.
+func Comparar(anterior, nuevo *models.RUCCompleto) []Cambio {
+	var cambios []Cambio
+	cambios = append(cambios, compararFicha(&anterior.InformacionBasica, &nuevo.InformacionBasica)...)
+
+	if anterior.DeudaCoactiva != nil && nuevo.DeudaCoactiva != nil {
+		cambios = append(cambios, compararDeudas(anterior.DeudaCoactiva.Deudas, nuevo.DeudaCoactiva.Deudas)...)
+	}
+	if anterior.OmisionesTributarias != nil && nuevo.OmisionesTributarias != nil {
+		cambios = append(cambios, compararFilas(anterior.OmisionesTributarias.Omisiones, nuevo.OmisionesTributarias.Omisiones,
+			claveOmision, OmisionNueva, OmisionResuelta, func(o models.Omision) string { return o.Estado })...)
+	}
+	if anterior.ActasProbatorias != nil && nuevo.ActasProbatorias != nil {
+		// Las actas no desaparecen: solo interesan las nuevas
+		cambios = append(cambios, compararFilas(anterior.ActasProbatorias.Actas, nuevo.ActasProbatorias.Actas,
+			claveActa, ActaNueva, "", func(a models.ActaProbatoria) string { return a.DescripcionInfraccion })...)
+	}
+	if anterior.RepresentantesLegales != nil && nuevo.RepresentantesLegales != nil {
+		cambios = append(cambios, compararRepresentantes(anterior.RepresentantesLegales.Representantes,
+			nuevo.RepresentantesLegales.Representantes)...)
+	}
+	if anterior.EstablecimientosAnexos != nil && nuevo.EstablecimientosAnexos != nil {
+		cambios = append(cambios, compararFilas(anterior.EstablecimientosAnexos.Establecimientos, nuevo.EstablecimientosAnexos.Establecimientos,
+			claveEstablecimiento, EstablecimientoNuevo, EstablecimientoBaja, describirEstablecimiento)...)
+	}
+	return cambios
+}
+
+func compararFicha(anterior, nuevo *models.RUCInfo) []Cambio {
+	campos := []struct {
+		tipo            Tipo
+		anterior, nuevo string
+	}{
+		{CambioEstado, anterior.Estado, nuevo.Estado},
+		{CambioCondicion, anterior.Condicion, nuevo.Condicion},
+		{CambioRazonSocial, anterior.RazonSocial, nuevo.RazonSocial},
+		{CambioDomicilioFiscal, anterior.DomicilioFiscal, nuevo.DomicilioFiscal},
+	}
+
+	var cambios []Cambio
+	for _, c := range campos {
+		a, n := normalizar(c.anterior), normalizar(c.nuevo)
+		if a != n {
+			cambios = append(cambios, Cambio{Tipo: c.tipo, Anterior: a, Nuevo: n})
+		}
+	}
+	return cambios
+}
+
+// compararDeudas identifica cada deuda por periodo, fecha de inicio de
+// cobranza y entidad, ya que SUNAT no muestra el número de documento
+func compararDeudas(anteriores, nuevas []models.DetalleDeuda) []Cambio {
+	claveDeuda := func(d models.DetalleDeuda) string {
+		return d.PeriodoTributario.String() + " " + d.FechaInicioCobranza.String() + " " + normalizar(d.Entidad)
+	}
+	montoDeuda := func(d models.DetalleDeuda) string { return d.Monto.String() }
+
+	cambios := compararFilas(anteriores, nuevas, claveDeuda, DeudaNueva, DeudaCancelada, montoDeuda)
+
+	_, previas := porClave(anteriores, claveDeuda)
+	claves, actuales := porClave(nuevas, claveDeuda)
+	for _, k := range claves {
+		a, ok := previas[k]
+		if !ok {
+			continue
+		}
+		if n := actuales[k]; a.Monto != n.Monto {
+			cambios = append(cambios, Cambio{Tipo: DeudaMonto, Clave: k, Anterior: a.Monto.String(), Nuevo: n.Monto.String()})
+		}
+	}
+	return cambios
+}
+
+// compararRepresentantes identifica a cada representante por su documento y
+// cargo. Hay alta si aparece vigente y antes no estaba o no estaba vigente, y
+// cese si estaba vigente y ya no aparece o dejó de estarlo.
+func compararRepresentantes(anteriores, nuevos []models.RepresentanteLegal) []Cambio {
+	claves, previos := porClave(anteriores, claveRepresentante)
+	clavesNuevas, actuales := porClave(nuevos, claveRepresentante)
+
+	var cambios []Cambio
+	for _, k := range clavesNuevas {
+		n := actuales[k]
+		a, estaba := previos[k]
+		if n.Vigente && (!estaba || !a.Vigente) {
+			cambios = append(cambios, Cambio{Tipo: RepresentanteAlta, Clave: k, Nuevo: n.NombreCompleto})
+		}
+	}
+	for _, k := range claves {
+		a := previos[k]
+		n, esta := actuales[k]
+		if a.Vigente && (!esta || !n.Vigente) {
+			cambios = append(cambios, Cambio{Tipo: RepresentanteCese, Clave: k, Anterior: a.NombreCompleto})
+		}
+	}
+	return cambios
+}
+
+// compararFilas lista como alta las filas de nuevas cuya clave no estaba en
+// anteriores y como baja las de anteriores que ya no están (ninguna si baja
+// es ""); describir da el texto de Anterior o Nuevo
+func compararFilas[T any](anteriores, nuevas []T, clave func(T) string, alta, baja Tipo, describir func(T) string) []Cambio {
+	claves, previas := porClave(anteriores, clave)
+	clavesNuevas, actuales := porClave(nuevas, clave)
+
+	var cambios []Cambio
+	for _, k := range clavesNuevas {
+		if _, ok := previas[k]; !ok {
+			cambios = append(cambios, Cambio{Tipo: alta, Clave: k, Nuevo: normalizar(describir(actuales[k]))})
+		}
+	}
+	for _, k := range claves {
+		if _, ok := actuales[k]; !ok && baja != "" {
+			cambios = append(cambios, Cambio{Tipo: baja, Clave: k, Anterior: normalizar(describir(previas[k]))})
+		}
+	}
+	return cambios
+}
+
+// porClave indexa las filas por su clave y retorna las claves en el orden de
+// las filas. Una clave repetida se numera ("clave #2") para no perder filas.
+func porClave[T any](filas []T, clave func(T) string) ([]string, map[string]T) {
+	claves := make([]string, 0, len(filas))
+	indice := make(map[string]T, len(filas))
+	for _, f := range filas {
+		k := clave(f)
+		for n := 2; ; n++ {
+			if _, repetida := indice[k]; !repetida {
+				break
+			}
+			k = fmt.Sprintf("%s #%d", clave(f), n)
+		}
+		claves = append(claves, k)
+		indice[k] = f
+	}
+	return claves, indice
+}
+
+func claveOmision(o models.Omision) string {
+	return o.Periodo.String() + " " + normalizar(o.Tributo)
+}
+
+func claveActa(a models.ActaProbatoria) string {
+	return normalizar(a.NumeroActa)
+}
+
+func claveRepresentante(r models.RepresentanteLegal) string {
+	cargo := string(r.Cargo)
+	if cargo == "" {
+		cargo = normalizar(r.CargoSUNAT)
+	}
+	return strings.TrimSpace(string(r.TipoDocumento) + " " + r.NumeroDocumento + " " + cargo)
+}
+
+func claveEstablecimiento(e models.EstablecimientoAnexo) string {
+	return normalizar(e.Codigo)
+}
+
+func describirEstablecimiento(e models.EstablecimientoAnexo) string {
+	return normalizar(e.TipoEstablecimiento + " " + e.Direccion)
+}
+
+// normalizar quita los espacios repetidos y trata "-" como vacío, igual que
+// la base de datos, para no informar cambios que solo son de formato
+func normalizar(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "-" || strings.EqualFold(s, "No hay información") {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/documento"
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+func rucBase() *models.RUCCompleto {
+	return &models.RUCCompleto{
+		InformacionBasica: models.RUCInfo{
+			RUC:             "20606316977",
+			RazonSocial:     "EMPRESA S.A.C.",
+			Estado:          "ACTIVO",
+			Condicion:       "HABIDO",
+			DomicilioFiscal: "AV. LOS OLIVOS 123",
+		},
+		DeudaCoactiva: &models.DeudaCoactiva{
+			Deudas: []models.DetalleDeuda{
+				{Monto: models.Soles(125050), PeriodoTributario: models.NuevoPeriodo(2023, time.December),
+					FechaInicioCobranza: models.NuevaFecha(2024, time.March, 1), Entidad: "SUNAT"},
+			},
+		},
+		OmisionesTributarias: &models.OmisionesTributarias{
+			Omisiones: []models.Omision{
+				{Periodo: models.NuevoPeriodo(2024, time.January), Tributo: "IGV", Estado: "PENDIENTE"},
+			},
+		},
+		RepresentantesLegales: &models.RepresentantesLegales{
+			Representantes: []models.RepresentanteLegal{
+				{TipoDocumento: documento.DNI, NumeroDocumento: "41234567", NombreCompleto: "PEREZ, JUAN",
+					Cargo: models.CargoGerenteGeneral, Vigente: true},
+			},
+		},
+		EstablecimientosAnexos: &models.EstablecimientosAnexos{
+			Establecimientos: []models.EstablecimientoAnexo{
+				{Codigo: "0001", TipoEstablecimiento: "SUCURSAL", Direccion: "JR. UNION 456"},
+			},
+		},
+	}
+}
+
+func TestCompararSinCambios(t *testing.T) {
+	if cambios := Comparar(rucBase(), rucBase()); len(cambios) != 0 {
+		t.Errorf("se esperaban 0 cambios, hay %v", cambios)
+	}
+
+	// "-" y los espacios repetidos no son cambios
+	nuevo := rucBase()
+	nuevo.InformacionBasica.DomicilioFiscal = "AV.  LOS OLIVOS 123"
+	anterior := rucBase()
+	anterior.InformacionBasica.Condicion = "-"
+	nuevo.InformacionBasica.Condicion = ""
+	if cambios := Comparar(anterior, nuevo); len(cambios) != 0 {
+		t.Errorf("se esperaban 0 cambios, hay %v", cambios)
+	}
+}
+
+func TestComparar(t *testing.T) {
+	anterior := rucBase()
+	nuevo := rucBase()
+	nuevo.InformacionBasica.Estado = "BAJA DE OFICIO"
+	nuevo.InformacionBasica.Condicion = "NO HABIDO"
+	nuevo.InformacionBasica.RazonSocial = "EMPRESA NUEVA S.A.C."
+	nuevo.DeudaCoactiva.Deudas[0].Monto = models.Soles(200000)
+	nuevo.DeudaCoactiva.Deudas = append(nuevo.DeudaCoactiva.Deudas, models.DetalleDeuda{
+		Monto: models.Soles(5000), PeriodoTributario: models.NuevoPeriodo(2024, time.June),
+		FechaInicioCobranza: models.NuevaFecha(2024, time.September, 2), Entidad: "SUNAT"})
+	nuevo.OmisionesTributarias.Omisiones = []models.Omision{
+		{Periodo: models.NuevoPeriodo(2024, time.February), Tributo: "RENTA", Estado: "PENDIENTE"},
+	}
+	nuevo.RepresentantesLegales.Representantes[0].Vigente = false
+	nuevo.RepresentantesLegales.Representantes = append(nuevo.RepresentantesLegales.Representantes,
+		models.RepresentanteLegal{TipoDocumento: documento.DNI, NumeroDocumento: "76366932",
+			NombreCompleto: "GOMEZ, ANA", Cargo: models.CargoGerenteGeneral, Vigente: true})
+	nuevo.EstablecimientosAnexos.Establecimientos = append(nuevo.EstablecimientosAnexos.Establecimientos,
+		models.EstablecimientoAnexo{Codigo: "0002", TipoEstablecimiento: "LOCAL COMERCIAL", Direccion: "AV. PERU 1"})
+
+	want := []Cambio{
+		{Tipo: CambioEstado, Anterior: "ACTIVO", Nuevo: "BAJA DE OFICIO"},
+		{Tipo: CambioCondicion, Anterior: "HABIDO", Nuevo: "NO HABIDO"},
+		{Tipo: CambioRazonSocial, Anterior: "EMPRESA S.A.C.", Nuevo: "EMPRESA NUEVA S.A.C."},
+		{Tipo: DeudaNueva, Clave: "202406 02/09/2024 SUNAT", Nuevo: "50.00 PEN"},
+		{Tipo: DeudaMonto, Clave: "202312 01/03/2024 SUNAT", Anterior: "1250.50 PEN", Nuevo: "2000.00 PEN"},
+		{Tipo: OmisionNueva, Clave: "202402 RENTA", Nuevo: "PENDIENTE"},
+		{Tipo: OmisionResuelta, Clave: "202401 IGV", Anterior: "PENDIENTE"},
+		{Tipo: RepresentanteAlta, Clave: "DNI 76366932 GERENTE GENERAL", Nuevo: "GOMEZ, ANA"},
+		{Tipo: RepresentanteCese, Clave: "DNI 41234567 GERENTE GENERAL", Anterior: "PEREZ, JUAN"},
+		{Tipo: EstablecimientoNuevo, Clave: "0002", Nuevo: "LOCAL COMERCIAL AV. PERU 1"},
+	}
+	if got := Comparar(anterior, nuevo); !reflect.DeepEqual(got, want) {
+		t.Errorf("Comparar:\n got %v\nwant %v", got, want)
+	}
+}
+
+func TestCompararSeccionAusente(t *testing.T) {
+	nuevo := rucBase()
+	nuevo.DeudaCoactiva = nil
+	nuevo.RepresentantesLegales = nil
+	if cambios := Comparar(rucBase(), nuevo); len(cambios) != 0 {
+		t.Errorf("una sección no consultada no debe informar cambios: %v", cambios)
+	}
+}
+
+func TestPorClaveRepetida(t *testing.T) {
+	claves, _ := porClave([]string{"a", "b", "a"}, func(s string) string { return s })
+	if want := []string{"a", "b", "a #2"}; !reflect.DeepEqual(claves, want) {
+		t.Errorf("porClave = %v, se esperaba %v", claves, want)
+	}
+}
@@ -0,0 +1,182 @@
+// Package documento reconoce los documentos de identidad que muestra SUNAT
+// (DNI, carné de extranjería, pasaporte, ...) con la lista de tipos del
+// catálogo 06 de SUNAT, y valida su número según el tipo.
+package documento
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/consulta-ruc-scraper/pkg/ruc"
+)
+
+var (
+	// ErrTipoDesconocido indica una etiqueta de documento que no está en la lista
+	ErrTipoDesconocido = errors.New("tipo de documento desconocido")
+	// ErrNumeroInvalido indica un número que no corresponde al tipo de documento
+	ErrNumeroInvalido = errors.New("número de documento inválido")
+	// ErrFormato indica un texto que no es "TIPO NÚMERO - TITULAR"
+	ErrFormato = errors.New("documento de identidad inválido, se esperaba \"TIPO NÚMERO - TITULAR\"")
+)
+
+// Tipo es el tipo de documento de identidad. Su valor es la abreviatura que
+// se guarda en JSON y en la base de datos; CodigoSUNAT da el código del catálogo 06.
+type Tipo string
+
+const (
+	DNI               Tipo = "DNI"
+	CarneExtranjeria  Tipo = "CE"
+	Pasaporte         Tipo = "PASAPORTE"
+	RUC               Tipo = "RUC"
+	CedulaDiplomatica Tipo = "CDI"
+	PTP               Tipo = "PTP" // permiso temporal de permanencia
+	CPP               Tipo = "CPP" // carné de permiso temporal de permanencia
+	NoDomiciliado     Tipo = "NO_DOMICILIADO"
+)
+
+type datosTipo struct {
+	codigo      string
+	descripcion string
+}
+
+var tipos = map[Tipo]datosTipo{
+	DNI:               {"1", "Documento Nacional de Identidad"},
+	CarneExtranjeria:  {"4", "Carné de Extranjería"},
+	RUC:               {"6", "Registro Único de Contribuyentes"},
+	Pasaporte:         {"7", "Pasaporte"},
+	CedulaDiplomatica: {"A", "Cédula Diplomática de Identidad"},
+	PTP:               {"F", "Permiso Temporal de Permanencia"},
+	CPP:               {"H", "Carné de Permiso Temporal de Permanencia"},
+	NoDomiciliado:     {"0", "Documento Tributario No Domiciliado sin RUC"},
+}
+
+// etiquetas son los textos con que SUNAT nombra cada tipo, ya simplificados
+// (mayúsculas, sin tildes ni puntos)
+var etiquetas = map[string]Tipo{
+	"DNI":                                  DNI,
+	"DOC NACIONAL DE IDENTIDAD":            DNI,
+	"DOCUMENTO NACIONAL DE IDENTIDAD":      DNI,
+	"LIBRETA ELECTORAL":                    DNI,
+	"LIBRETA ELECTORAL / DNI":              DNI,
+	"LE / DNI":                             DNI,
+	"CE":                                   CarneExtranjeria,
+	"CARNET DE EXTRANJERIA":                CarneExtranjeria,
+	"CARNE DE EXTRANJERIA":                 CarneExtranjeria,
+	"CARNET EXTRANJERIA":                   CarneExtranjeria,
+	"CARNET EXT":                           CarneExtranjeria,
+	"CARNE EXT":                            CarneExtranjeria,
+	"PASAPORTE":                            Pasaporte,
+	"PAS":                                  Pasaporte,
+	"PASS":                                 Pasaporte,
+	"RUC":                                  RUC,
+	"REG UNICO DE CONTRIBUYENTES":          RUC,
+	"CDI":                                  CedulaDiplomatica,
+	"CED DIPLOMATICA DE IDENTIDAD":         CedulaDiplomatica,
+	"CEDULA DIPLOMATICA DE IDENTIDAD":      CedulaDiplomatica,
+	"PTP":                                  PTP,
+	"PERMISO TEMPORAL DE PERMANENCIA":      PTP,
+	"PERMISO TEMP PERMANENCIA":             PTP,
+	"CPP":                                  CPP,
+	"CARNE DE PERMISO TEMP DE PERMANENCIA": CPP,
+	"CARNE DE PERMISO TEMPORAL DE PERMANENCIA":  CPP,
+	"CARNET DE PERMISO TEMPORAL DE PERMANENCIA": CPP,
+	"DOC TRIB NO DOM SIN RUC":                   NoDomiciliado,
+	"DOCTRIBNODOMSINRUC":                        NoDomiciliado,
+}
+
+// ParseTipo reconoce una etiqueta de SUNAT ("DNI", "CARNET DE EXTRANJERIA",
+// "C.E.", "PASAPORTE", ...) sin distinguir mayúsculas, tildes ni puntos
+func ParseTipo(etiqueta string) (Tipo, error) {
+	if t, ok := etiquetas[simplificar(etiqueta)]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("%q: %w", etiqueta, ErrTipoDesconocido)
+}
+
+// CodigoSUNAT retorna el código del tipo en el catálogo 06 de SUNAT ("1" el DNI)
+func (t Tipo) CodigoSUNAT() string {
+	return tipos[t].codigo
+}
+
+// Descripcion retorna el nombre del tipo, p. ej. "Carné de Extranjería"
+func (t Tipo) Descripcion() string {
+	if d, ok := tipos[t]; ok {
+		return d.descripcion
+	}
+	return "Desconocido"
+}
+
+// patronNumero son los números de documento distintos de DNI y RUC:
+// alfanuméricos de 4 a 15 caracteres
+var patronNumero = regexp.MustCompile(`^[0-9A-Z]{4,15}$`)
+
+// Documento es un documento de identidad con su número validado
+type Documento struct {
+	Tipo   Tipo
+	Numero string
+}
+
+// Nuevo valida el número según el tipo: el DNI tiene 8 dígitos, el RUC se
+// valida con pkg/ruc y los demás son alfanuméricos de 4 a 15 caracteres
+func Nuevo(tipo Tipo, numero string) (Documento, error) {
+	if _, ok := tipos[tipo]; !ok {
+		return Documento{}, fmt.Errorf("%q: %w", string(tipo), ErrTipoDesconocido)
+	}
+	numero = strings.ToUpper(strings.TrimSpace(numero))
+
+	var valido bool
+	switch tipo {
+	case DNI:
+		valido = len(numero) == 8 && strings.Trim(numero, "0123456789") == ""
+	case RUC:
+		valido = ruc.Valido(numero)
+	default:
+		valido = patronNumero.MatchString(numero)
+	}
+	if !valido {
+		return Documento{}, fmt.Errorf("%s %q: %w", tipo, numero, ErrNumeroInvalido)
+	}
+	return Documento{Tipo: tipo, Numero: numero}, nil
+}
+
+// Parse reconoce la etiqueta y valida el número, p. ej. Parse("C.E.", "001234567")
+func Parse(etiqueta, numero string) (Documento, error) {
+	tipo, err := ParseTipo(etiqueta)
+	if err != nil {
+		return Documento{}, err
+	}
+	return Nuevo(tipo, numero)
+}
+
+// ParseIdentidad separa el campo "Tipo de Documento" de la ficha RUC, p. ej.
+// "DNI 76366932 - TARRILLO MARRUFO, YAMELITH MARILYN", en el documento y el
+// nombre del titular (vacío si SUNAT no lo muestra)
+func ParseIdentidad(s string) (Documento, string, error) {
+	texto := strings.Join(strings.Fields(s), " ")
+	documento, titular, _ := strings.Cut(texto, " - ")
+	campos := strings.Fields(documento)
+	if len(campos) < 2 {
+		return Documento{}, "", fmt.Errorf("%q: %w", s, ErrFormato)
+	}
+
+	d, err := Parse(strings.Join(campos[:len(campos)-1], " "), campos[len(campos)-1])
+	if err != nil {
+		return Documento{}, "", err
+	}
+	return d, strings.TrimSpace(titular), nil
+}
+
+// String retorna "DNI 76366932"
+func (d Documento) String() string {
+	return string(d.Tipo) + " " + d.Numero
+}
+
+var normalizar = strings.NewReplacer("Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", ".", "", "/", " / ")
+
+// simplificar pasa a mayúsculas y quita tildes y puntos: "Carné Ext." → "CARNE EXT",
+// "D.N.I." → "DNI"
+func simplificar(s string) string {
+	return strings.Join(strings.Fields(normalizar.Replace(strings.ToUpper(s))), " ")
+}
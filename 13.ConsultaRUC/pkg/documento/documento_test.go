@@ -0,0 +1,61 @@
+package documento
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTipo(t *testing.T) {
+	casos := map[string]Tipo{
+		"DNI":                           DNI,
+		"D.N.I.":                        DNI,
+		"L.E / DNI":                     DNI,
+		"Carné de Extranjería":          CarneExtranjeria,
+		"C.E.":                          CarneExtranjeria,
+		"PASAPORTE":                     Pasaporte,
+		"CED. DIPLOMATICA DE IDENTIDAD": CedulaDiplomatica,
+		"PERMISO TEMP. PERMANENCIA":     PTP,
+		"DOC.TRIB.NO.DOM.SIN.RUC":       NoDomiciliado,
+	}
+	for etiqueta, esperado := range casos {
+		if tipo, err := ParseTipo(etiqueta); err != nil || tipo != esperado {
+			t.Errorf("ParseTipo(%q) = %q, %v; se esperaba %q", etiqueta, tipo, err, esperado)
+		}
+	}
+	if _, err := ParseTipo("LICENCIA DE CONDUCIR"); !errors.Is(err, ErrTipoDesconocido) {
+		t.Errorf("ParseTipo(LICENCIA DE CONDUCIR) aceptado: %v", err)
+	}
+	if DNI.CodigoSUNAT() != "1" || CarneExtranjeria.CodigoSUNAT() != "4" || Pasaporte.CodigoSUNAT() != "7" {
+		t.Error("códigos del catálogo 06 incorrectos")
+	}
+}
+
+func TestParseIdentidad(t *testing.T) {
+	d, titular, err := ParseIdentidad("DNI  76366932  - TARRILLO MARRUFO, YAMELITH MARILYN")
+	if err != nil || d != (Documento{DNI, "76366932"}) || titular != "TARRILLO MARRUFO, YAMELITH MARILYN" {
+		t.Errorf("ParseIdentidad(DNI) = %v, %q, %v", d, titular, err)
+	}
+
+	d, titular, err = ParseIdentidad("CARNET DE EXTRANJERIA 001234567 - SMITH, JOHN")
+	if err != nil || d != (Documento{CarneExtranjeria, "001234567"}) || titular != "SMITH, JOHN" {
+		t.Errorf("ParseIdentidad(CE) = %v, %q, %v", d, titular, err)
+	}
+
+	d, titular, err = ParseIdentidad("PASAPORTE ab123456")
+	if err != nil || d != (Documento{Pasaporte, "AB123456"}) || titular != "" {
+		t.Errorf("ParseIdentidad(PASAPORTE) = %v, %q, %v", d, titular, err)
+	}
+
+	errores := map[string]error{
+		"76366932 - TARRILLO":     ErrFormato,
+		"DNI 7636693 - TARRILLO":  ErrNumeroInvalido,
+		"RUC 10763669323":         ErrNumeroInvalido,
+		"LICENCIA 12345 - PEREZ":  ErrTipoDesconocido,
+		"PASAPORTE AB-12 - PEREZ": ErrNumeroInvalido,
+	}
+	for texto, esperado := range errores {
+		if _, _, err := ParseIdentidad(texto); !errors.Is(err, esperado) {
+			t.Errorf("ParseIdentidad(%q) = %v; se esperaba %v", texto, err, esperado)
+		}
+	}
+}
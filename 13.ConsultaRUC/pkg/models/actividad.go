@@ -0,0 +1,100 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/consulta-ruc-scraper/pkg/ciiu"
+)
+
+// ErrActividadInvalida indica una fila de actividad económica que no sigue el
+// formato "Principal - 6920 - DESCRIPCIÓN"
+var ErrActividadInvalida = errors.New("actividad económica inválida, se esperaba \"Tipo - código CIIU - descripción\"")
+
+// TipoActividad distingue la actividad principal de las secundarias
+type TipoActividad string
+
+const (
+	ActividadPrincipal  TipoActividad = "principal"
+	ActividadSecundaria TipoActividad = "secundaria"
+)
+
+// patronActividad reconoce "Principal - 6920 - ...", "Secundaria 1 - 7020 - ..."
+// y la variante antigua "Principal - CIIU 74130 - ..." (con "Rev.3" opcional)
+var patronActividad = regexp.MustCompile(`(?i)^(principal|secundaria)(?:\s+(\d))?\s*-\s*(?:ciiu\s*(?:rev\.?\s*([34])\s*)?)?(\d{4,5})\s*-\s*(.+)$`)
+
+// ActividadEconomica es una fila de "Actividad(es) Económica(s)" de la ficha
+// RUC. Una fila con otro formato queda solo con Descripcion (la fila entera).
+type ActividadEconomica struct {
+	Tipo         TipoActividad `json:"tipo"`
+	Orden        int           `json:"orden,omitempty"` // 1 o 2 en las secundarias; 0 en la principal
+	CodigoCIIU   string        `json:"codigo_ciiu"`
+	RevisionCIIU int           `json:"revision_ciiu"` // 3 o 4; 0 sin código
+	Descripcion  string        `json:"descripcion"`
+	SeccionCIIU  string        `json:"seccion_ciiu,omitempty"`  // del catálogo pkg/ciiu; vacío si el código no está
+	DivisionCIIU string        `json:"division_ciiu,omitempty"` // del catálogo pkg/ciiu; vacío si el código no está
+}
+
+// ParseActividadEconomica interpreta una fila de la ficha. SUNAT no indica la
+// revisión CIIU salvo en fichas antiguas ("CIIU Rev.3"); cuando falta se deduce
+// del código: la Rev.3 de SUNAT usa 5 dígitos y la Rev.4 usa 4.
+func ParseActividadEconomica(s string) (ActividadEconomica, error) {
+	m := patronActividad.FindStringSubmatch(strings.Join(strings.Fields(s), " "))
+	if m == nil {
+		return ActividadEconomica{}, fmt.Errorf("%q: %w", s, ErrActividadInvalida)
+	}
+
+	a := ActividadEconomica{
+		Tipo:         TipoActividad(strings.ToLower(m[1])),
+		CodigoCIIU:   m[4],
+		RevisionCIIU: 4,
+		Descripcion:  strings.TrimSpace(m[5]),
+	}
+	if len(a.CodigoCIIU) == 5 {
+		a.RevisionCIIU = 3
+	}
+	if m[3] != "" {
+		a.RevisionCIIU, _ = strconv.Atoi(m[3])
+	}
+
+	switch {
+	case a.Tipo == ActividadPrincipal && m[2] != "":
+		return ActividadEconomica{}, fmt.Errorf("%q: la actividad principal no lleva número: %w", s, ErrActividadInvalida)
+	case a.Tipo == ActividadSecundaria && m[2] == "":
+		a.Orden = 1
+	case a.Tipo == ActividadSecundaria:
+		a.Orden, _ = strconv.Atoi(m[2])
+	}
+
+	if c, err := ciiu.Obtener(ciiu.Revision(a.RevisionCIIU), a.CodigoCIIU); err == nil {
+		a.SeccionCIIU = c.Seccion
+		a.DivisionCIIU = c.Division
+	}
+	return a, nil
+}
+
+// EnCatalogo indica si el código CIIU está en el catálogo de su revisión
+func (a ActividadEconomica) EnCatalogo() bool {
+	return a.SeccionCIIU != ""
+}
+
+// EsPrincipal indica si es la actividad principal del contribuyente
+func (a ActividadEconomica) EsPrincipal() bool {
+	return a.Tipo == ActividadPrincipal
+}
+
+// String retorna la fila con el formato de la ficha, p. ej. "Secundaria 1 - 7020 - DESCRIPCIÓN",
+// o la fila original si no tiene código CIIU
+func (a ActividadEconomica) String() string {
+	if a.CodigoCIIU == "" {
+		return a.Descripcion
+	}
+	tipo := "Principal"
+	if a.Tipo == ActividadSecundaria {
+		tipo = fmt.Sprintf("Secundaria %d", a.Orden)
+	}
+	return fmt.Sprintf("%s - %s - %s", tipo, a.CodigoCIIU, a.Descripcion)
+}
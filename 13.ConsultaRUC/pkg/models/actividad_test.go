@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseActividadEconomica(t *testing.T) {
+	casos := map[string]ActividadEconomica{
+		"Principal - 6920 - ACTIVIDADES DE CONTABILIDAD, TENEDURÍA DE LIBROS Y AUDITORÍA; CONSULTORÍA FISCAL": {
+			Tipo: ActividadPrincipal, CodigoCIIU: "6920", RevisionCIIU: 4,
+			Descripcion: "ACTIVIDADES DE CONTABILIDAD, TENEDURÍA DE LIBROS Y AUDITORÍA; CONSULTORÍA FISCAL",
+			SeccionCIIU: "M", DivisionCIIU: "69",
+		},
+		"Secundaria 2  -  7020 - ACTIVIDADES DE CONSULTORÍA DE GESTIÓN": {
+			Tipo: ActividadSecundaria, Orden: 2, CodigoCIIU: "7020", RevisionCIIU: 4,
+			Descripcion: "ACTIVIDADES DE CONSULTORÍA DE GESTIÓN",
+			SeccionCIIU: "M", DivisionCIIU: "70",
+		},
+		"Secundaria - 74130 - INVESTIGACION DE MERCADOS": {
+			Tipo: ActividadSecundaria, Orden: 1, CodigoCIIU: "74130", RevisionCIIU: 3,
+			Descripcion: "INVESTIGACION DE MERCADOS",
+			SeccionCIIU: "K", DivisionCIIU: "74",
+		},
+		"Principal - CIIU Rev.3 7412 - ACTIVIDADES DE CONTABILIDAD": {
+			Tipo: ActividadPrincipal, CodigoCIIU: "7412", RevisionCIIU: 3,
+			Descripcion: "ACTIVIDADES DE CONTABILIDAD",
+			SeccionCIIU: "K", DivisionCIIU: "74",
+		},
+	}
+	for texto, esperada := range casos {
+		a, err := ParseActividadEconomica(texto)
+		if err != nil || a != esperada {
+			t.Errorf("ParseActividadEconomica(%q) = %+v, %v; se esperaba %+v", texto, a, err, esperada)
+		}
+	}
+
+	for _, texto := range []string{"", "ACTIVIDADES DE CONTABILIDAD", "Principal - 692 - X", "Principal 1 - 6920 - X", "Terciaria - 6920 - X", "Principal - 6920 -"} {
+		if _, err := ParseActividadEconomica(texto); !errors.Is(err, ErrActividadInvalida) {
+			t.Errorf("ParseActividadEconomica(%q) aceptado: %v", texto, err)
+		}
+	}
+}
+
+func TestActividadFueraDeCatalogo(t *testing.T) {
+	a, err := ParseActividadEconomica("Principal - 6999 - ACTIVIDAD NUEVA")
+	if err != nil || a.EnCatalogo() || a.SeccionCIIU != "" {
+		t.Errorf("ParseActividadEconomica = %+v, %v; se esperaba una actividad fuera del catálogo", a, err)
+	}
+}
+
+func TestActividadEconomicaString(t *testing.T) {
+	texto := "Secundaria 1 - 7020 - ACTIVIDADES DE CONSULTORÍA DE GESTIÓN"
+	a, err := ParseActividadEconomica(texto)
+	if err != nil || a.String() != texto || a.EsPrincipal() {
+		t.Errorf("String = %q, %v", a.String(), err)
+	}
+}
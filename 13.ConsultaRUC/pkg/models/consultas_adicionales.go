@@ -1,5 +1,7 @@
 package models
 
+import "github.com/consulta-ruc-scraper/pkg/documento"
+
 // InformacionHistorica representa los cambios históricos del RUC
 type InformacionHistorica struct {
 	RazonesSociales []RazonSocialHistorica     `json:"razones_sociales"`
@@ -9,32 +11,34 @@ type InformacionHistorica struct {
 
 type RazonSocialHistorica struct {
 	Nombre      string `json:"nombre"`        // o "No hay Información"
-	FechaDeBaja string `json:"fecha_de_baja"` // "-" si no hay
+	FechaDeBaja Fecha  `json:"fecha_de_baja"` // desconocida si SUNAT muestra "-"
 }
 
 type CondicionHistorica struct {
 	Condicion string `json:"condicion"` // Ej: "HABIDO" o "-"
-	Desde     string `json:"desde"`     // Ej: "13/08/2020"
-	Hasta     string `json:"hasta"`     // Ej: "01/07/2022"
+	Desde     Fecha  `json:"desde"`
+	Hasta     Fecha  `json:"hasta"`
 }
 
 type DomicilioFiscalHistorico struct {
-	Direccion   string `json:"direccion"`
-	FechaDeBaja string `json:"fecha_de_baja"` // Ej: "01/07/2022"
+	Direccion        string     `json:"direccion"`
+	DireccionDetalle *Domicilio `json:"direccion_detalle,omitempty"`
+	FechaDeBaja      Fecha      `json:"fecha_de_baja"`
 }
 
 // DeudaCoactiva representa las deudas en cobranza coactiva
 type DeudaCoactiva struct {
-	TotalDeuda         float64        `json:"total_deuda"`
+	TotalDeuda         Monto          `json:"total_deuda"` // suma exacta de Deudas; no incluye FilasInvalidas
 	CantidadDocumentos int            `json:"cantidad_documentos"`
 	Deudas             []DetalleDeuda `json:"deudas"`
+	FilasInvalidas     []FilaInvalida `json:"filas_invalidas,omitempty"` // filas cuyo monto no se pudo interpretar
 }
 
 // DetalleDeuda representa una fila de deuda
 type DetalleDeuda struct {
-	Monto               float64 `json:"monto"`
-	PeriodoTributario   string  `json:"periodo_tributario"`
-	FechaInicioCobranza string  `json:"fecha_inicio_cobranza"`
+	Monto               Monto   `json:"monto"`
+	PeriodoTributario   Periodo `json:"periodo_tributario"`
+	FechaInicioCobranza Fecha   `json:"fecha_inicio_cobranza"`
 	Entidad             string  `json:"entidad"`
 }
 
@@ -46,25 +50,25 @@ type OmisionesTributarias struct {
 }
 
 type Omision struct {
-	Periodo          string `json:"periodo"`
-	Tributo          string `json:"tributo"`
-	TipoDeclaracion  string `json:"tipo_declaracion"`
-	FechaVencimiento string `json:"fecha_vencimiento"`
-	Estado           string `json:"estado"`
+	Periodo          Periodo `json:"periodo"`
+	Tributo          string  `json:"tributo"`
+	TipoDeclaracion  string  `json:"tipo_declaracion"`
+	FechaVencimiento Fecha   `json:"fecha_vencimiento"`
+	Estado           string  `json:"estado"`
 }
 
 // CantidadTrabajadores representa información de trabajadores y prestadores
 type CantidadTrabajadores struct {
-	PeriodosDisponibles []string              `json:"periodos_disponibles"`
+	PeriodosDisponibles []Periodo             `json:"periodos_disponibles"`
 	DetallePorPeriodo   []DetalleTrabajadores `json:"detalle_por_periodo"`
 }
 
 type DetalleTrabajadores struct {
-	Periodo                     string `json:"periodo"`
-	CantidadTrabajadores        int    `json:"cantidad_trabajadores"`
-	CantidadPrestadoresServicio int    `json:"cantidad_prestadores_servicio"`
-	CantidadPensionistas        int    `json:"cantidad_pensionistas"`
-	Total                       int    `json:"total"`
+	Periodo                     Periodo `json:"periodo"`
+	CantidadTrabajadores        int     `json:"cantidad_trabajadores"`
+	CantidadPrestadoresServicio int     `json:"cantidad_prestadores_servicio"`
+	CantidadPensionistas        int     `json:"cantidad_pensionistas"`
+	Total                       int     `json:"total"`
 }
 
 // ActasProbatorias representa las actas probatorias del contribuyente
@@ -76,7 +80,7 @@ type ActasProbatorias struct {
 
 type ActaProbatoria struct {
 	NumeroActa            string `json:"numero_acta"`            // Nº Acta Probatoria
-	FechaActa             string `json:"fecha_acta"`             // Fecha de Acta Probatoria
+	FechaActa             Fecha  `json:"fecha_acta"`             // Fecha de Acta Probatoria
 	LugarIntervencion     string `json:"lugar_intervencion"`     // Lugar de Intervención
 	ArticuloNumeral       string `json:"articulo_numeral"`       // Artículo y Numeral de la infracción
 	DescripcionInfraccion string `json:"descripcion_infraccion"` // Descripción de la infracción
@@ -94,7 +98,7 @@ type FacturasFisicas struct {
 
 type FacturaAutorizada struct {
 	NumeroAutorizacion string `json:"numero_autorizacion"`
-	FechaAutorizacion  string `json:"fecha_autorizacion"`
+	FechaAutorizacion  Fecha  `json:"fecha_autorizacion"`
 	TipoComprobante    string `json:"tipo_comprobante"`
 	Serie              string `json:"serie"`
 	NumeroInicial      string `json:"numero_inicial"`
@@ -103,7 +107,7 @@ type FacturaAutorizada struct {
 
 type FacturaBajaOCancelada struct {
 	NumeroAutorizacion string `json:"numero_autorizacion"`
-	FechaAutorizacion  string `json:"fecha_autorizacion"`
+	FechaAutorizacion  Fecha  `json:"fecha_autorizacion"`
 	TipoComprobante    string `json:"tipo_comprobante"`
 	Serie              string `json:"serie"`
 	NumeroInicial      string `json:"numero_inicial"`
@@ -114,8 +118,8 @@ type FacturaBajaOCancelada struct {
 type ReactivaPeru struct {
 	RazonSocial        string `json:"razon_social"`
 	TieneDeudaCoactiva bool   `json:"tiene_deuda_coactiva"` // true si dice "SÍ", false si dice "NO"
-	FechaActualizacion string `json:"fecha_actualizacion"`  // "06/08/2025"
-	ReferenciaLegal    string `json:"referencia_legal"`     // "Decreto Legislativo N° 1455"
+	FechaActualizacion Fecha  `json:"fecha_actualizacion"`
+	ReferenciaLegal    string `json:"referencia_legal"` // "Decreto Legislativo N° 1455"
 }
 
 // ProgramaCovid19 representa información del programa de garantías COVID-19
@@ -123,8 +127,8 @@ type ProgramaCovid19 struct {
 	RazonSocial        string `json:"razon_social"`
 	ParticipaPrograma  bool   `json:"participa_programa"`
 	TieneDeudaCoactiva bool   `json:"tiene_deuda_coactiva"`
-	FechaActualizacion string `json:"fecha_actualizacion"` // formato como "06/08/2025"
-	BaseLegal          string `json:"base_legal"`          // ejemplo: "Ley N° 31050"
+	FechaActualizacion Fecha  `json:"fecha_actualizacion"`
+	BaseLegal          string `json:"base_legal"` // ejemplo: "Ley N° 31050"
 }
 
 // RepresentantesLegales representa los representantes legales
@@ -133,13 +137,16 @@ type RepresentantesLegales struct {
 }
 
 type RepresentanteLegal struct {
-	TipoDocumento   string `json:"tipo_documento"`
-	NumeroDocumento string `json:"numero_documento"`
-	NombreCompleto  string `json:"nombre_completo"`
-	Cargo           string `json:"cargo"`
-	FechaDesde      string `json:"fecha_desde"`
-	FechaHasta      string `json:"fecha_hasta,omitempty"`
-	Vigente         bool   `json:"vigente"`
+	TipoDocumento   documento.Tipo `json:"tipo_documento"` // vacío si SUNAT muestra un tipo que no está en pkg/documento
+	NumeroDocumento string         `json:"numero_documento"`
+	NombreCompleto  string         `json:"nombre_completo"`
+	Apellidos       string         `json:"apellidos,omitempty"` // de "APELLIDOS, NOMBRES"; vacíos si no hay coma
+	Nombres         string         `json:"nombres,omitempty"`
+	Cargo           Cargo          `json:"cargo"`
+	CargoSUNAT      string         `json:"cargo_sunat"` // texto original, p. ej. "GTE. GENERAL"
+	FechaDesde      Fecha          `json:"fecha_desde"`
+	FechaHasta      Fecha          `json:"fecha_hasta,omitzero"`
+	Vigente         bool           `json:"vigente"` // VigenteAl a la fecha de la consulta
 }
 
 // EstablecimientosAnexos representa los establecimientos anexos
@@ -149,8 +156,9 @@ type EstablecimientosAnexos struct {
 }
 
 type EstablecimientoAnexo struct {
-	Codigo              string `json:"codigo"`
-	TipoEstablecimiento string `json:"tipo_establecimiento"`
-	Direccion           string `json:"direccion"`
-	ActividadEconomica  string `json:"actividad_economica"`
+	Codigo              string     `json:"codigo"`
+	TipoEstablecimiento string     `json:"tipo_establecimiento"`
+	Direccion           string     `json:"direccion"`
+	DireccionDetalle    *Domicilio `json:"direccion_detalle,omitempty"`
+	ActividadEconomica  string     `json:"actividad_economica"`
 }
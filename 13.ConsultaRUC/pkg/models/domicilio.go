@@ -0,0 +1,116 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/consulta-ruc-scraper/pkg/ubigeo"
+)
+
+// Domicilio es una dirección de SUNAT separada en sus partes, p. ej.
+// "CAL. CENTENARIO NRO. 156 URB. LAS LADERAS LIMA - LIMA - LA MOLINA"
+type Domicilio struct {
+	TipoVia          string `json:"tipo_via,omitempty"` // abreviatura de SUNAT: "AV.", "JR.", "CAL.", ...
+	NombreVia        string `json:"nombre_via,omitempty"`
+	Numero           string `json:"numero,omitempty"` // "156" o "S/N"
+	Interior         string `json:"interior,omitempty"`
+	Manzana          string `json:"manzana,omitempty"`
+	Lote             string `json:"lote,omitempty"`
+	TipoUrbanizacion string `json:"tipo_urbanizacion,omitempty"` // "URB.", "A.H.", "ASOC.", ...
+	Urbanizacion     string `json:"urbanizacion,omitempty"`
+	Departamento     string `json:"departamento,omitempty"`
+	Provincia        string `json:"provincia,omitempty"`
+	Distrito         string `json:"distrito,omitempty"`
+	Ubigeo           string `json:"ubigeo,omitempty"` // código INEI del distrito; vacío si no está en pkg/ubigeo
+}
+
+// tiposVia son las abreviaturas con que SUNAT empieza la dirección
+var tiposVia = map[string]bool{
+	"AV.": true, "JR.": true, "CAL.": true, "PJ.": true, "PSJE.": true, "CAR.": true,
+	"PROL.": true, "ALAM.": true, "MAL.": true, "OVAL.": true, "PQUE.": true, "CAM.": true,
+}
+
+// tiposUrbanizacion son las abreviaturas de la zona (urbanización, asentamiento humano, ...)
+var tiposUrbanizacion = map[string]bool{
+	"URB.": true, "A.H.": true, "ASOC.": true, "COO.": true, "P.J.": true, "RES.": true,
+	"C.P.": true, "Z.I.": true, "U.V.": true, "CAS.": true, "FND.": true,
+}
+
+// ParseDomicilio separa una dirección de SUNAT en sus partes y resuelve el
+// ubigeo con pkg/ubigeo. SUNAT escribe la ubicación al final como
+// "DEPARTAMENTO - PROVINCIA - DISTRITO", con el departamento pegado al resto de
+// la dirección. Retorna nil si no hay dirección ("-" o vacío); las partes que
+// no se reconocen quedan en NombreVia.
+func ParseDomicilio(s string) *Domicilio {
+	texto := strings.Join(strings.Fields(s), " ")
+	if texto == "" || texto == "-" {
+		return nil
+	}
+
+	d := &Domicilio{}
+	direccion := texto
+	if partes := strings.Split(texto, " - "); len(partes) >= 3 {
+		n := len(partes)
+		if departamento, resto, ok := separarDepartamento(strings.Join(partes[:n-2], " - ")); ok {
+			d.Departamento = departamento
+			d.Provincia = partes[n-2]
+			d.Distrito = partes[n-1]
+			direccion = resto
+		}
+	}
+	d.separarVia(direccion)
+
+	if d.Departamento != "" {
+		if l, err := ubigeo.Buscar(d.Departamento, d.Provincia, d.Distrito); err == nil {
+			d.Ubigeo = l.Codigo
+		}
+	}
+	return d
+}
+
+// separarDepartamento busca al final de texto el nombre de un departamento
+// (hasta 4 palabras, como "PROV. CONST. DEL CALLAO") y lo separa del resto
+func separarDepartamento(texto string) (departamento, resto string, ok bool) {
+	palabras := strings.Fields(texto)
+	for k := min(4, len(palabras)); k >= 1; k-- {
+		candidato := strings.Join(palabras[len(palabras)-k:], " ")
+		if _, err := ubigeo.Departamento(candidato); err == nil {
+			return candidato, strings.Join(palabras[:len(palabras)-k], " "), true
+		}
+	}
+	return "", "", false
+}
+
+// separarVia reparte las palabras de la dirección según las abreviaturas que
+// las preceden (NRO., INT., MZA., URB., ...)
+func (d *Domicilio) separarVia(direccion string) {
+	palabras := strings.Fields(direccion)
+	if len(palabras) > 0 && tiposVia[palabras[0]] {
+		d.TipoVia = palabras[0]
+		palabras = palabras[1:]
+	}
+
+	valores := map[*string][]string{}
+	campo := &d.NombreVia
+	for _, p := range palabras {
+		switch {
+		case p == "NRO.":
+			campo = &d.Numero
+		case p == "INT." || p == "DPTO." || p == "OFIC.":
+			campo = &d.Interior
+		case p == "MZA." || p == "MZ.":
+			campo = &d.Manzana
+		case p == "LOTE." || p == "LT.":
+			campo = &d.Lote
+		case tiposUrbanizacion[p]:
+			d.TipoUrbanizacion = p
+			campo = &d.Urbanizacion
+		default:
+			valores[campo] = append(valores[campo], p)
+		}
+	}
+	for campo, v := range valores {
+		if valor := strings.Join(v, " "); valor != "-" {
+			*campo = valor
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestParseDomicilio(t *testing.T) {
+	casos := map[string]Domicilio{
+		"CAL. CENTENARIO NRO. 156 URB. LAS LADERAS DE MELGAREJO LIMA - LIMA - LA MOLINA": {
+			TipoVia: "CAL.", NombreVia: "CENTENARIO", Numero: "156",
+			TipoUrbanizacion: "URB.", Urbanizacion: "LAS LADERAS DE MELGAREJO",
+			Departamento: "LIMA", Provincia: "LIMA", Distrito: "LA MOLINA", Ubigeo: "150114",
+		},
+		"AV. JAVIER PRADO ESTE NRO. 4200 INT. 502 URB. FUNDO MONTERRICO CHICO LIMA - LIMA - SANTIAGO DE SURCO": {
+			TipoVia: "AV.", NombreVia: "JAVIER PRADO ESTE", Numero: "4200", Interior: "502",
+			TipoUrbanizacion: "URB.", Urbanizacion: "FUNDO MONTERRICO CHICO",
+			Departamento: "LIMA", Provincia: "LIMA", Distrito: "SANTIAGO DE SURCO", Ubigeo: "150140",
+		},
+		"A.H. LOS JARDINES MZA. B LOTE. 12 PROV. CONST. DEL CALLAO - PROV. CONST. DEL CALLAO - VENTANILLA": {
+			TipoUrbanizacion: "A.H.", Urbanizacion: "LOS JARDINES", Manzana: "B", Lote: "12",
+			Departamento: "PROV. CONST. DEL CALLAO", Provincia: "PROV. CONST. DEL CALLAO", Distrito: "VENTANILLA", Ubigeo: "070106",
+		},
+		// la ubicación se reconoce aunque la dirección tenga " - "
+		"JR. LOS OLIVOS NRO. - INT. 3 SAN MARTIN - SAN MARTIN - TARAPOTO": {
+			TipoVia: "JR.", NombreVia: "LOS OLIVOS", Interior: "3",
+			Departamento: "SAN MARTIN", Provincia: "SAN MARTIN", Distrito: "TARAPOTO", Ubigeo: "220901",
+		},
+		"AV. GRAU NRO. 10 PIURA - SULLANA - BELLAVISTA": {
+			TipoVia: "AV.", NombreVia: "GRAU", Numero: "10",
+			Departamento: "PIURA", Provincia: "SULLANA", Distrito: "BELLAVISTA", Ubigeo: "200602",
+		},
+		// sin ubicación reconocible
+		"CARRETERA CENTRAL KM 5": {NombreVia: "CARRETERA CENTRAL KM 5"},
+	}
+	for texto, esperado := range casos {
+		d := ParseDomicilio(texto)
+		if d == nil || *d != esperado {
+			t.Errorf("ParseDomicilio(%q) = %+v; se esperaba %+v", texto, d, esperado)
+		}
+	}
+
+	for _, texto := range []string{"", "-", "  "} {
+		if d := ParseDomicilio(texto); d != nil {
+			t.Errorf("ParseDomicilio(%q) = %+v; se esperaba nil", texto, d)
+		}
+	}
+}
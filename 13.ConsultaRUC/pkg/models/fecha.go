@@ -0,0 +1,147 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// formatoSUNAT es el único formato de fecha que muestra SUNAT (dd/mm/aaaa)
+	formatoSUNAT = "02/01/2006"
+	// formatoISO es el formato de la fecha en JSON y en la base de datos
+	formatoISO = "2006-01-02"
+)
+
+// ErrFechaInvalida indica un texto que no es "-", vacío, "No hay Información" ni dd/mm/aaaa
+var ErrFechaInvalida = errors.New("fecha inválida, se esperaba dd/mm/aaaa")
+
+// Fecha es una fecha de calendario (sin hora) de las páginas de SUNAT. El
+// valor cero es una fecha desconocida: SUNAT la muestra como "-", como
+// "No hay Información" o deja la celda vacía. En JSON se escribe como
+// "aaaa-mm-dd" o null, y en la base de datos como DATE o NULL.
+type Fecha struct {
+	t time.Time
+}
+
+// NuevaFecha crea una fecha conocida
+func NuevaFecha(anio int, mes time.Month, dia int) Fecha {
+	return Fecha{t: time.Date(anio, mes, dia, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseFecha interpreta una fecha de SUNAT. Solo acepta dd/mm/aaaa; los textos
+// que SUNAT usa para "sin dato" devuelven una fecha desconocida sin error.
+func ParseFecha(s string) (Fecha, error) {
+	s = strings.TrimSpace(s)
+	if fechaDesconocida(s) {
+		return Fecha{}, nil
+	}
+	if len(s) != len(formatoSUNAT) {
+		return Fecha{}, fmt.Errorf("%q: %w", s, ErrFechaInvalida)
+	}
+	t, err := time.Parse(formatoSUNAT, s)
+	if err != nil {
+		return Fecha{}, fmt.Errorf("%q: %w", s, ErrFechaInvalida)
+	}
+	return Fecha{t: t}, nil
+}
+
+func fechaDesconocida(s string) bool {
+	switch strings.ToLower(s) {
+	case "", "-", "--", "no hay información", "no hay informacion":
+		return true
+	}
+	return false
+}
+
+// Conocida indica si la fecha tiene valor
+func (f Fecha) Conocida() bool {
+	return !f.t.IsZero()
+}
+
+// IsZero permite usar la opción omitzero de encoding/json
+func (f Fecha) IsZero() bool {
+	return f.t.IsZero()
+}
+
+// Time retorna la fecha a medianoche UTC; false si es desconocida
+func (f Fecha) Time() (time.Time, bool) {
+	return f.t, f.Conocida()
+}
+
+// String retorna la fecha en formato SUNAT (dd/mm/aaaa) o "-" si es desconocida
+func (f Fecha) String() string {
+	if !f.Conocida() {
+		return "-"
+	}
+	return f.t.Format(formatoSUNAT)
+}
+
+// MarshalJSON escribe "aaaa-mm-dd" o null
+func (f Fecha) MarshalJSON() ([]byte, error) {
+	if !f.Conocida() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.t.Format(formatoISO))
+}
+
+// UnmarshalJSON acepta null, "aaaa-mm-dd" y, por compatibilidad con JSON
+// anteriores, los textos de SUNAT que acepta ParseFecha
+func (f *Fecha) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = Fecha{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("fecha: %w", err)
+	}
+	if t, err := time.Parse(formatoISO, s); err == nil {
+		*f = Fecha{t: t}
+		return nil
+	}
+	v, err := ParseFecha(s)
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// Value guarda la fecha como "aaaa-mm-dd"; la fecha desconocida se guarda como NULL
+func (f Fecha) Value() (driver.Value, error) {
+	if !f.Conocida() {
+		return nil, nil
+	}
+	return f.t.Format(formatoISO), nil
+}
+
+// Scan lee columnas DATE (time.Time) o de texto en formato aaaa-mm-dd
+func (f *Fecha) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*f = Fecha{}
+		return nil
+	case time.Time:
+		*f = NuevaFecha(v.Year(), v.Month(), v.Day())
+		return nil
+	case string:
+		return f.scanTexto(v)
+	case []byte:
+		return f.scanTexto(string(v))
+	}
+	return fmt.Errorf("fecha: no se puede leer %T", src)
+}
+
+func (f *Fecha) scanTexto(s string) error {
+	if len(s) >= len(formatoISO) {
+		if t, err := time.Parse(formatoISO, s[:len(formatoISO)]); err == nil {
+			*f = Fecha{t: t}
+			return nil
+		}
+	}
+	return fmt.Errorf("fecha: %q: %w", s, ErrFechaInvalida)
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseFecha(t *testing.T) {
+	f, err := ParseFecha(" 13/08/2020 ")
+	if err != nil || f != NuevaFecha(2020, time.August, 13) {
+		t.Fatalf("ParseFecha = %v, %v", f, err)
+	}
+
+	for _, s := range []string{"", "-", "No hay Información"} {
+		if f, err := ParseFecha(s); err != nil || f.Conocida() {
+			t.Errorf("ParseFecha(%q) = %v, %v; se esperaba desconocida", s, f, err)
+		}
+	}
+
+	// Solo dd/mm/aaaa: no se adivina entre formatos
+	for _, s := range []string{"2020-08-13", "8/13/2020", "13/8/2020", "31/02/2020", "13/08/20"} {
+		if _, err := ParseFecha(s); !errors.Is(err, ErrFechaInvalida) {
+			t.Errorf("ParseFecha(%q) aceptado: %v", s, err)
+		}
+	}
+}
+
+func TestFechaJSON(t *testing.T) {
+	v := struct {
+		Desde Fecha `json:"desde"`
+		Hasta Fecha `json:"hasta"`
+		Baja  Fecha `json:"baja,omitzero"`
+	}{Desde: NuevaFecha(2020, time.August, 13)}
+
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"desde":"2020-08-13","hasta":null}` {
+		t.Fatalf("Marshal = %s, %v", b, err)
+	}
+
+	v.Desde = Fecha{}
+	if err := json.Unmarshal([]byte(`{"desde":"2021-01-01","hasta":"01/07/2022"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Desde != NuevaFecha(2021, time.January, 1) || v.Hasta != NuevaFecha(2022, time.July, 1) {
+		t.Errorf("Unmarshal = %v, %v", v.Desde, v.Hasta)
+	}
+}
+
+func TestFechaSQL(t *testing.T) {
+	var f Fecha
+	if err := f.Scan(time.Date(2022, time.July, 1, 0, 0, 0, 0, time.Local)); err != nil || f != NuevaFecha(2022, time.July, 1) {
+		t.Errorf("Scan(time.Time) = %v, %v", f, err)
+	}
+	if err := f.Scan(nil); err != nil || f.Conocida() {
+		t.Errorf("Scan(nil) = %v, %v", f, err)
+	}
+	if v, _ := NuevaFecha(2022, time.July, 1).Value(); v != "2022-07-01" {
+		t.Errorf("Value = %v", v)
+	}
+	if v, _ := (Fecha{}).Value(); v != nil {
+		t.Errorf("Value de fecha desconocida = %v, se esperaba NULL", v)
+	}
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Moneda es el código ISO 4217 del monto
+type Moneda string
+
+// MonedaPEN son soles, la moneda de los montos de SUNAT
+const MonedaPEN Moneda = "PEN"
+
+var (
+	// ErrMontoInvalido indica un texto que no es un monto en soles
+	ErrMontoInvalido = errors.New("monto inválido")
+	// ErrMonedaDistinta indica una suma entre montos de distinta moneda
+	ErrMonedaDistinta = errors.New("no se pueden sumar montos de distinta moneda")
+	// ErrDesbordeMonto indica que la suma no cabe en int64 céntimos
+	ErrDesbordeMonto = errors.New("el monto excede el máximo representable")
+)
+
+// patronMonto acepta "1250", "1250.5", "1,250.50" y "-75.25": separador de
+// miles opcional (siempre en grupos de 3) y a lo más 2 decimales
+var patronMonto = regexp.MustCompile(`^(-)?(\d{1,3}(?:,\d{3})+|\d+)(?:\.(\d{1,2}))?$`)
+
+// Monto es un importe exacto en céntimos. En JSON se escribe como
+// {"importe": "1250.50", "moneda": "PEN"} y en la base de datos como NUMERIC.
+type Monto struct {
+	Centimos int64
+	Moneda   Moneda
+}
+
+// Soles crea un monto en soles a partir de céntimos
+func Soles(centimos int64) Monto {
+	return Monto{Centimos: centimos, Moneda: MonedaPEN}
+}
+
+// ParseMonto interpreta un monto de SUNAT: "S/ 1,250.50", "S/. 380.00" o
+// "12004.75". A diferencia de utils.ParseMonto, un texto que no es un monto
+// retorna error en lugar de cero.
+func ParseMonto(s string) (Monto, error) {
+	texto := strings.TrimSpace(s)
+	for _, simbolo := range []string{"S/.", "S/"} {
+		if strings.HasPrefix(texto, simbolo) {
+			texto = strings.TrimSpace(strings.TrimPrefix(texto, simbolo))
+			break
+		}
+	}
+
+	centimos, err := parseCentimos(texto)
+	if err != nil {
+		return Monto{}, fmt.Errorf("%q: %w", s, err)
+	}
+	return Soles(centimos), nil
+}
+
+// parseCentimos convierte un decimal con a lo más 2 decimales a céntimos
+func parseCentimos(texto string) (int64, error) {
+	m := patronMonto.FindStringSubmatch(texto)
+	if m == nil {
+		return 0, ErrMontoInvalido
+	}
+	enteros := strings.ReplaceAll(m[2], ",", "")
+	decimales := (m[3] + "00")[:2]
+
+	// 16 dígitos enteros ya no caben en int64 al multiplicar por 100
+	if len(enteros) > 15 {
+		return 0, ErrDesbordeMonto
+	}
+	centimos, err := strconv.ParseInt(enteros+decimales, 10, 64)
+	if err != nil {
+		return 0, ErrMontoInvalido
+	}
+	if m[1] == "-" {
+		centimos = -centimos
+	}
+	return centimos, nil
+}
+
+// Sumar retorna m + otro. El monto cero sin moneda toma la moneda del otro sumando.
+func (m Monto) Sumar(otro Monto) (Monto, error) {
+	moneda := m.Moneda
+	switch {
+	case moneda == "":
+		moneda = otro.Moneda
+	case otro.Moneda != "" && otro.Moneda != moneda:
+		return m, fmt.Errorf("%s + %s: %w", m.Moneda, otro.Moneda, ErrMonedaDistinta)
+	}
+	if (otro.Centimos > 0 && m.Centimos > math.MaxInt64-otro.Centimos) ||
+		(otro.Centimos < 0 && m.Centimos < math.MinInt64-otro.Centimos) {
+		return m, ErrDesbordeMonto
+	}
+	return Monto{Centimos: m.Centimos + otro.Centimos, Moneda: moneda}, nil
+}
+
+// Importe retorna el monto como decimal exacto, p. ej. "1250.50"
+func (m Monto) Importe() string {
+	signo := ""
+	c := m.Centimos
+	if c < 0 {
+		signo = "-"
+		c = -c // MinInt64 no es alcanzable desde parseCentimos
+	}
+	return fmt.Sprintf("%s%d.%02d", signo, c/100, c%100)
+}
+
+// String retorna el monto con su moneda, p. ej. "1250.50 PEN"
+func (m Monto) String() string {
+	if m.Moneda == "" {
+		return m.Importe()
+	}
+	return m.Importe() + " " + string(m.Moneda)
+}
+
+type montoJSON struct {
+	Importe string `json:"importe"`
+	Moneda  Moneda `json:"moneda"`
+}
+
+// MarshalJSON escribe {"importe": "1250.50", "moneda": "PEN"}
+func (m Monto) MarshalJSON() ([]byte, error) {
+	moneda := m.Moneda
+	if moneda == "" {
+		moneda = MonedaPEN
+	}
+	return json.Marshal(montoJSON{Importe: m.Importe(), Moneda: moneda})
+}
+
+// UnmarshalJSON lee el formato de MarshalJSON y, por compatibilidad con JSON
+// anteriores, un número suelto en soles
+func (m *Monto) UnmarshalJSON(data []byte) error {
+	var numero json.Number
+	if err := json.Unmarshal(data, &numero); err == nil {
+		centimos, err := parseCentimos(numero.String())
+		if err != nil {
+			return fmt.Errorf("monto %s: %w", numero, err)
+		}
+		*m = Soles(centimos)
+		return nil
+	}
+
+	var v montoJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("monto: %w", err)
+	}
+	centimos, err := parseCentimos(v.Importe)
+	if err != nil {
+		return fmt.Errorf("monto %q: %w", v.Importe, err)
+	}
+	if v.Moneda == "" {
+		v.Moneda = MonedaPEN
+	}
+	*m = Monto{Centimos: centimos, Moneda: v.Moneda}
+	return nil
+}
+
+// Value guarda el importe como texto decimal para columnas NUMERIC; la moneda
+// se guarda en su propia columna
+func (m Monto) Value() (driver.Value, error) {
+	return m.Importe(), nil
+}
+
+// Scan lee una columna NUMERIC en soles; NULL queda como el monto cero Monto{}
+func (m *Monto) Scan(src any) error {
+	var texto string
+	switch v := src.(type) {
+	case nil:
+		*m = Monto{}
+		return nil
+	case []byte:
+		texto = string(v)
+	case string:
+		texto = v
+	case int64:
+		*m = Soles(v * 100)
+		return nil
+	default:
+		return fmt.Errorf("monto: no se puede leer %T", src)
+	}
+	centimos, err := parseCentimos(texto)
+	if err != nil {
+		return fmt.Errorf("monto %q: %w", texto, err)
+	}
+	*m = Soles(centimos)
+	return nil
+}
+
+// FilaInvalida es una fila de una tabla de SUNAT que no se pudo interpretar.
+// Se informa por separado para que no se confunda con un monto cero.
+type FilaInvalida struct {
+	Fila  int    `json:"fila"`  // posición de la fila en la tabla completa, desde 1
+	Texto string `json:"texto"` // celdas originales separadas por " | "
+	Error string `json:"error"`
+}
+
+// AgregarDeuda suma el detalle al total de forma exacta. Si la suma no es
+// posible (otra moneda o desborde) retorna error y no agrega el detalle.
+func (d *DeudaCoactiva) AgregarDeuda(detalle DetalleDeuda) error {
+	total, err := d.TotalDeuda.Sumar(detalle.Monto)
+	if err != nil {
+		return err
+	}
+	d.TotalDeuda = total
+	d.Deudas = append(d.Deudas, detalle)
+	d.CantidadDocumentos = len(d.Deudas)
+	return nil
+}
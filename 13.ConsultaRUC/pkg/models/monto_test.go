@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMonto(t *testing.T) {
+	casos := map[string]int64{
+		"1250":            125000,
+		"S/ 1,250.50":     125050,
+		"S/. 380.00":      38000,
+		"S/1,234,567.8":   123456780,
+		" 0.05 ":          5,
+		"-75.25":          -7525,
+		"S/ 12,004.75":    1200475,
+		"999999999999.99": 99999999999999,
+	}
+	for texto, centimos := range casos {
+		m, err := ParseMonto(texto)
+		if err != nil || m != Soles(centimos) {
+			t.Errorf("ParseMonto(%q) = %v, %v; se esperaba %d céntimos", texto, m, err, centimos)
+		}
+	}
+
+	// Nada se interpreta como cero
+	for _, texto := range []string{"", "-", "S/", "abc", "1.234,56", "12,34", "1,2345.00", "1.005", "1e3"} {
+		if _, err := ParseMonto(texto); !errors.Is(err, ErrMontoInvalido) {
+			t.Errorf("ParseMonto(%q) aceptado: %v", texto, err)
+		}
+	}
+	if _, err := ParseMonto("1234567890123456"); !errors.Is(err, ErrDesbordeMonto) {
+		t.Errorf("ParseMonto con 16 dígitos: %v", err)
+	}
+}
+
+func TestMontoSumar(t *testing.T) {
+	// 0.1 + 0.2 es exacto en céntimos
+	total, err := Monto{}.Sumar(Soles(10))
+	if err == nil {
+		total, err = total.Sumar(Soles(20))
+	}
+	if err != nil || total != Soles(30) || total.Importe() != "0.30" {
+		t.Fatalf("Sumar = %v, %v", total, err)
+	}
+
+	if _, err := total.Sumar(Monto{Centimos: 100, Moneda: "USD"}); !errors.Is(err, ErrMonedaDistinta) {
+		t.Errorf("Sumar PEN + USD: %v", err)
+	}
+	if _, err := Soles(1 << 62).Sumar(Soles(1 << 62)); !errors.Is(err, ErrDesbordeMonto) {
+		t.Errorf("Sumar con desborde: %v", err)
+	}
+}
+
+func TestMontoJSON(t *testing.T) {
+	b, err := json.Marshal(Soles(-705))
+	if err != nil || string(b) != `{"importe":"-7.05","moneda":"PEN"}` {
+		t.Fatalf("Marshal = %s, %v", b, err)
+	}
+
+	var m Monto
+	if err := json.Unmarshal(b, &m); err != nil || m != Soles(-705) {
+		t.Errorf("Unmarshal(%s) = %v, %v", b, m, err)
+	}
+	// JSON anteriores guardaban un número
+	if err := json.Unmarshal([]byte(`1100.1`), &m); err != nil || m != Soles(110010) {
+		t.Errorf("Unmarshal(1100.1) = %v, %v", m, err)
+	}
+	if err := json.Unmarshal([]byte(`{"importe":"1.005","moneda":"PEN"}`), &m); !errors.Is(err, ErrMontoInvalido) {
+		t.Errorf("Unmarshal con 3 decimales: %v", err)
+	}
+}
+
+func TestMontoSQL(t *testing.T) {
+	v, err := Soles(125050).Value()
+	if err != nil || v != "1250.50" {
+		t.Fatalf("Value = %v, %v", v, err)
+	}
+
+	var m Monto
+	if err := m.Scan([]byte("1250.50")); err != nil || m != Soles(125050) {
+		t.Errorf("Scan = %v, %v", m, err)
+	}
+	if err := m.Scan(nil); err != nil || m != (Monto{}) {
+		t.Errorf("Scan(nil) = %v, %v", m, err)
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+// PaginacionSeccion resume el recorrido de una tabla paginada de SUNAT
+type PaginacionSeccion struct {
+	Paginas        int  `json:"paginas"`
+	Filas          int  `json:"filas"`
+	TotalReportado int  `json:"total_reportado"` // "Z" del contador "X a Y de Z"; 0 si la página no lo muestra
+	Verificado     bool `json:"verificado"`      // Filas coincide con TotalReportado
+}
+
+// AgregarPagina registra una página recorrida con sus filas extraídas
+func (p *PaginacionSeccion) AgregarPagina(filas, totalReportado int) {
+	p.Paginas++
+	p.Filas += filas
+	if totalReportado > 0 {
+		p.TotalReportado = totalReportado
+	}
+
+	if p.TotalReportado == 0 {
+		// Sin contador solo se puede confiar en una tabla de una página
+		p.Verificado = p.Paginas == 1
+	} else {
+		p.Verificado = p.Filas == p.TotalReportado
+	}
+}
+
+// TienePaginacion indica si la tabla ocupaba más de una página
+func (p PaginacionSeccion) TienePaginacion() bool {
+	return p.Paginas > 1 || p.TotalReportado > p.Filas
+}
+
+// Fusionar agrega las deudas de otra página sumando el total de forma exacta.
+// Una deuda que no se puede sumar (otra moneda) pasa a FilasInvalidas. Las
+// filas de otra se numeran a continuación de las ya fusionadas, de modo que
+// FilaInvalida.Fila no se repite entre páginas.
+func (d *DeudaCoactiva) Fusionar(otra *DeudaCoactiva) {
+	if otra == nil {
+		return
+	}
+	previas := len(d.Deudas) + len(d.FilasInvalidas)
+
+	// Las deudas válidas ocupan, en orden, las posiciones de la página que no
+	// corresponden a una fila inválida
+	fila, j := 0, 0
+	for _, deuda := range otra.Deudas {
+		fila++
+		for j < len(otra.FilasInvalidas) && otra.FilasInvalidas[j].Fila == fila {
+			fila++
+			j++
+		}
+		if err := d.AgregarDeuda(deuda); err != nil {
+			d.FilasInvalidas = append(d.FilasInvalidas, FilaInvalida{
+				Fila:  previas + fila,
+				Texto: deuda.Monto.String() + " | " + deuda.PeriodoTributario.String(),
+				Error: err.Error(),
+			})
+		}
+	}
+	for _, invalida := range otra.FilasInvalidas {
+		invalida.Fila += previas
+		d.FilasInvalidas = append(d.FilasInvalidas, invalida)
+	}
+	d.CantidadDocumentos = len(d.Deudas)
+}
+
+// Fusionar agrega las omisiones de otra página
+func (o *OmisionesTributarias) Fusionar(otra *OmisionesTributarias) {
+	if otra == nil {
+		return
+	}
+	o.Omisiones = append(o.Omisiones, otra.Omisiones...)
+	o.CantidadOmisiones = len(o.Omisiones)
+	o.TieneOmisiones = o.CantidadOmisiones > 0
+}
+
+// Fusionar agrega los periodos de otra página sin repetir PeriodosDisponibles
+func (c *CantidadTrabajadores) Fusionar(otra *CantidadTrabajadores) {
+	if otra == nil {
+		return
+	}
+	c.DetallePorPeriodo = append(c.DetallePorPeriodo, otra.DetallePorPeriodo...)
+	for _, periodo := range otra.PeriodosDisponibles {
+		existe := false
+		for _, p := range c.PeriodosDisponibles {
+			if p == periodo {
+				existe = true
+				break
+			}
+		}
+		if !existe {
+			c.PeriodosDisponibles = append(c.PeriodosDisponibles, periodo)
+		}
+	}
+}
+
+// Fusionar agrega los representantes de otra página
+func (r *RepresentantesLegales) Fusionar(otra *RepresentantesLegales) {
+	if otra == nil {
+		return
+	}
+	r.Representantes = append(r.Representantes, otra.Representantes...)
+}
+
+// Fusionar agrega los establecimientos de otra página
+func (e *EstablecimientosAnexos) Fusionar(otra *EstablecimientosAnexos) {
+	if otra == nil {
+		return
+	}
+	e.Establecimientos = append(e.Establecimientos, otra.Establecimientos...)
+	e.CantidadAnexos = len(e.Establecimientos)
+}
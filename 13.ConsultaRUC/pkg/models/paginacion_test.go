@@ -0,0 +1,36 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDeudaCoactivaFusionarNumeraFilas(t *testing.T) {
+	deuda := func(m Monto) DetalleDeuda {
+		return DetalleDeuda{Monto: m, PeriodoTributario: NuevoPeriodo(2024, time.January), Entidad: "SUNAT"}
+	}
+	// Primera página: 2 filas, la segunda ilegible
+	d := &DeudaCoactiva{FilasInvalidas: []FilaInvalida{{Fila: 2, Texto: "abc", Error: "monto inválido"}}}
+	if err := d.AgregarDeuda(deuda(Soles(100))); err != nil {
+		t.Fatal(err)
+	}
+
+	// Segunda página: fila 1 ilegible, fila 2 en otra moneda, fila 3 válida
+	otra := &DeudaCoactiva{
+		Deudas:         []DetalleDeuda{deuda(Monto{Centimos: 500, Moneda: "USD"}), deuda(Soles(200))},
+		FilasInvalidas: []FilaInvalida{{Fila: 1, Texto: "xyz", Error: "monto inválido"}},
+	}
+	d.Fusionar(otra)
+
+	var filas []int
+	for _, f := range d.FilasInvalidas {
+		filas = append(filas, f.Fila)
+	}
+	if want := []int{2, 4, 3}; !reflect.DeepEqual(filas, want) {
+		t.Errorf("FilasInvalidas en las filas %v, se esperaba %v", filas, want)
+	}
+	if d.TotalDeuda != Soles(300) || d.CantidadDocumentos != 2 {
+		t.Errorf("TotalDeuda = %v, CantidadDocumentos = %d", d.TotalDeuda, d.CantidadDocumentos)
+	}
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrPeriodoInvalido indica un texto que no es un periodo (mes y año) reconocible
+var ErrPeriodoInvalido = errors.New("periodo inválido, se esperaba aaaamm, aaaa-mm, mm/aaaa o \"MES aaaa\"")
+
+// mesesPeriodo son los nombres y abreviaturas de mes que usa SUNAT; en Perú se
+// escribe "SETIEMBRE"
+var mesesPeriodo = map[string]time.Month{
+	"ENERO": time.January, "FEBRERO": time.February, "MARZO": time.March,
+	"ABRIL": time.April, "MAYO": time.May, "JUNIO": time.June,
+	"JULIO": time.July, "AGOSTO": time.August, "SETIEMBRE": time.September,
+	"SEPTIEMBRE": time.September, "OCTUBRE": time.October, "NOVIEMBRE": time.November,
+	"DICIEMBRE": time.December,
+	// abreviaturas
+	"ENE": time.January, "FEB": time.February, "MAR": time.March, "ABR": time.April,
+	"MAY": time.May, "JUN": time.June, "JUL": time.July, "AGO": time.August,
+	"SET": time.September, "SEP": time.September, "OCT": time.October,
+	"NOV": time.November, "DIC": time.December,
+}
+
+var (
+	patronAnioMes = regexp.MustCompile(`^(\d{4})[-/]?(\d{2})$`)       // 202312, 2023-12, 2023/12
+	patronMesAnio = regexp.MustCompile(`^(\d{1,2})[-/](\d{4})$`)      // 12/2023, 12-2023
+	patronNombre  = regexp.MustCompile(`^([A-Z]+)\.?[\s/-]*(\d{4})$`) // DICIEMBRE 2023, DIC-2023
+)
+
+// Periodo es un periodo tributario o laboral (año y mes). El valor cero es un
+// periodo desconocido. En JSON se escribe como "aaaa-mm" o null, y en la base
+// de datos como DATE con el primer día del mes, para poder filtrar por rango.
+type Periodo struct {
+	anio int
+	mes  time.Month
+}
+
+// NuevoPeriodo crea un periodo conocido
+func NuevoPeriodo(anio int, mes time.Month) Periodo {
+	return Periodo{anio: anio, mes: mes}
+}
+
+// ParsePeriodo interpreta un periodo de SUNAT: "202312", "2023-12", "12/2023",
+// "DICIEMBRE 2023" o "DIC-2023". Los textos de "sin dato" que acepta
+// ParseFecha devuelven un periodo desconocido sin error.
+func ParsePeriodo(s string) (Periodo, error) {
+	texto := strings.ToUpper(strings.Join(strings.Fields(s), " "))
+	if fechaDesconocida(texto) {
+		return Periodo{}, nil
+	}
+
+	var anio, mes string
+	if m := patronAnioMes.FindStringSubmatch(texto); m != nil {
+		anio, mes = m[1], m[2]
+	} else if m := patronMesAnio.FindStringSubmatch(texto); m != nil {
+		anio, mes = m[2], m[1]
+	} else if m := patronNombre.FindStringSubmatch(texto); m != nil {
+		numero, ok := mesesPeriodo[m[1]]
+		if !ok {
+			return Periodo{}, fmt.Errorf("%q: %w", s, ErrPeriodoInvalido)
+		}
+		anio, mes = m[2], strconv.Itoa(int(numero))
+	} else {
+		return Periodo{}, fmt.Errorf("%q: %w", s, ErrPeriodoInvalido)
+	}
+
+	a, _ := strconv.Atoi(anio)
+	n, _ := strconv.Atoi(mes)
+	if n < 1 || n > 12 || a < 1900 {
+		return Periodo{}, fmt.Errorf("%q: %w", s, ErrPeriodoInvalido)
+	}
+	return NuevoPeriodo(a, time.Month(n)), nil
+}
+
+// Conocido indica si el periodo tiene valor
+func (p Periodo) Conocido() bool {
+	return p.anio != 0
+}
+
+// IsZero permite usar la opción omitzero de encoding/json
+func (p Periodo) IsZero() bool {
+	return !p.Conocido()
+}
+
+// Anio retorna el año del periodo
+func (p Periodo) Anio() int {
+	return p.anio
+}
+
+// Mes retorna el mes del periodo
+func (p Periodo) Mes() time.Month {
+	return p.mes
+}
+
+// indice numera los periodos de forma consecutiva para compararlos y restarlos
+func (p Periodo) indice() int {
+	return p.anio*12 + int(p.mes) - 1
+}
+
+func periodoDeIndice(i int) Periodo {
+	return Periodo{anio: i / 12, mes: time.Month(i%12 + 1)}
+}
+
+// Comparar retorna -1, 0 o +1 si p es anterior, igual o posterior a otro
+// (sirve para slices.SortFunc). El periodo desconocido va primero.
+func (p Periodo) Comparar(otro Periodo) int {
+	switch a, b := p.indice(), otro.indice(); {
+	case !p.Conocido() && !otro.Conocido():
+		return 0
+	case !p.Conocido():
+		return -1
+	case !otro.Conocido():
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Antes indica si p es anterior a otro
+func (p Periodo) Antes(otro Periodo) bool {
+	return p.Comparar(otro) < 0
+}
+
+// Sumar retorna el periodo desplazado n meses (n negativo retrocede). El
+// periodo desconocido sigue desconocido.
+func (p Periodo) Sumar(meses int) Periodo {
+	if !p.Conocido() {
+		return p
+	}
+	return periodoDeIndice(p.indice() + meses)
+}
+
+// MesesDesde retorna cuántos meses van de desde a p (negativo si desde es posterior)
+func (p Periodo) MesesDesde(desde Periodo) int {
+	return p.indice() - desde.indice()
+}
+
+// PrimerDia retorna el primer día del periodo; desconocida si el periodo lo es
+func (p Periodo) PrimerDia() Fecha {
+	if !p.Conocido() {
+		return Fecha{}
+	}
+	return NuevaFecha(p.anio, p.mes, 1)
+}
+
+// String retorna el periodo en el formato de SUNAT (aaaamm) o "-" si es desconocido
+func (p Periodo) String() string {
+	if !p.Conocido() {
+		return "-"
+	}
+	return fmt.Sprintf("%04d%02d", p.anio, int(p.mes))
+}
+
+// ISO retorna el periodo como "aaaa-mm", o "" si es desconocido
+func (p Periodo) ISO() string {
+	if !p.Conocido() {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d", p.anio, int(p.mes))
+}
+
+// MarshalJSON escribe "aaaa-mm" o null
+func (p Periodo) MarshalJSON() ([]byte, error) {
+	if !p.Conocido() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(p.ISO())
+}
+
+// UnmarshalJSON acepta null y los textos que acepta ParsePeriodo
+func (p *Periodo) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*p = Periodo{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("periodo: %w", err)
+	}
+	v, err := ParsePeriodo(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// Value guarda el periodo como el primer día del mes ("aaaa-mm-01") para
+// columnas DATE; el periodo desconocido se guarda como NULL
+func (p Periodo) Value() (driver.Value, error) {
+	return p.PrimerDia().Value()
+}
+
+// Scan lee columnas DATE (cualquier día del mes) o de texto aaaa-mm-dd, aaaa-mm o aaaamm
+func (p *Periodo) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*p = Periodo{}
+		return nil
+	case time.Time:
+		*p = NuevoPeriodo(v.Year(), v.Month())
+		return nil
+	case []byte:
+		return p.scanTexto(string(v))
+	case string:
+		return p.scanTexto(v)
+	}
+	return fmt.Errorf("periodo: no se puede leer %T", src)
+}
+
+func (p *Periodo) scanTexto(s string) error {
+	var f Fecha
+	if err := f.scanTexto(s); err == nil {
+		t, _ := f.Time()
+		*p = NuevoPeriodo(t.Year(), t.Month())
+		return nil
+	}
+	v, err := ParsePeriodo(s)
+	if err != nil {
+		return fmt.Errorf("periodo: %w", err)
+	}
+	*p = v
+	return nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParsePeriodo(t *testing.T) {
+	diciembre := NuevoPeriodo(2023, time.December)
+	for _, s := range []string{"202312", "2023-12", "2023/12", "12/2023", "12-2023", "DICIEMBRE 2023", "Diciembre-2023", "DIC 2023", "dic.2023"} {
+		if p, err := ParsePeriodo(s); err != nil || p != diciembre {
+			t.Errorf("ParsePeriodo(%q) = %v, %v; se esperaba 202312", s, p, err)
+		}
+	}
+	if p, err := ParsePeriodo("SETIEMBRE 2022"); err != nil || p != NuevoPeriodo(2022, time.September) {
+		t.Errorf("ParsePeriodo(SETIEMBRE 2022) = %v, %v", p, err)
+	}
+
+	for _, s := range []string{"", "-", "No hay Información"} {
+		if p, err := ParsePeriodo(s); err != nil || p.Conocido() {
+			t.Errorf("ParsePeriodo(%q) = %v, %v; se esperaba desconocido", s, p, err)
+		}
+	}
+
+	for _, s := range []string{"202313", "2023-00", "23-12", "PRIMAVERA 2023", "2023", "13/08/2020"} {
+		if _, err := ParsePeriodo(s); !errors.Is(err, ErrPeriodoInvalido) {
+			t.Errorf("ParsePeriodo(%q) aceptado: %v", s, err)
+		}
+	}
+}
+
+func TestPeriodoAritmetica(t *testing.T) {
+	p := NuevoPeriodo(2023, time.November)
+	if s := p.Sumar(3); s != NuevoPeriodo(2024, time.February) {
+		t.Errorf("Sumar(3) = %v", s)
+	}
+	if s := p.Sumar(-11); s != NuevoPeriodo(2022, time.December) {
+		t.Errorf("Sumar(-11) = %v", s)
+	}
+	if n := NuevoPeriodo(2024, time.March).MesesDesde(p); n != 4 {
+		t.Errorf("MesesDesde = %d", n)
+	}
+
+	periodos := []Periodo{NuevoPeriodo(2024, time.January), {}, NuevoPeriodo(2023, time.May), NuevoPeriodo(2023, time.December)}
+	slices.SortFunc(periodos, Periodo.Comparar)
+	esperado := []Periodo{{}, NuevoPeriodo(2023, time.May), NuevoPeriodo(2023, time.December), NuevoPeriodo(2024, time.January)}
+	if !slices.Equal(periodos, esperado) {
+		t.Errorf("orden = %v", periodos)
+	}
+}
+
+func TestPeriodoJSONySQL(t *testing.T) {
+	v := struct {
+		Periodo Periodo `json:"periodo"`
+		Otro    Periodo `json:"otro"`
+	}{Periodo: NuevoPeriodo(2024, time.March)}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"periodo":"2024-03","otro":null}` {
+		t.Fatalf("Marshal = %s, %v", b, err)
+	}
+	if err := json.Unmarshal([]byte(`{"periodo":"202401","otro":"2023-05"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Periodo != NuevoPeriodo(2024, time.January) || v.Otro != NuevoPeriodo(2023, time.May) {
+		t.Errorf("Unmarshal = %v, %v", v.Periodo, v.Otro)
+	}
+
+	if valor, err := v.Periodo.Value(); err != nil || valor != "2024-01-01" {
+		t.Errorf("Value = %v, %v", valor, err)
+	}
+	var p Periodo
+	if err := p.Scan(time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC)); err != nil || p != NuevoPeriodo(2023, time.May) {
+		t.Errorf("Scan(time) = %v, %v", p, err)
+	}
+	if err := p.Scan([]byte("2022-07-01")); err != nil || p != NuevoPeriodo(2022, time.July) {
+		t.Errorf("Scan([]byte) = %v, %v", p, err)
+	}
+	if err := p.Scan(nil); err != nil || p.Conocido() {
+		t.Errorf("Scan(nil) = %v, %v", p, err)
+	}
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Cargo es el cargo de un representante legal en un vocabulario controlado.
+// SUNAT escribe el mismo cargo de varias formas ("GTE. GENERAL",
+// "GERENTE GENERAL", "GERENTE GRAL."); el texto original queda en CargoSUNAT.
+type Cargo string
+
+const (
+	CargoGerenteGeneral     Cargo = "GERENTE GENERAL"
+	CargoGerente            Cargo = "GERENTE"
+	CargoSubgerente         Cargo = "SUBGERENTE"
+	CargoTitularGerente     Cargo = "TITULAR-GERENTE"
+	CargoPresidente         Cargo = "PRESIDENTE"
+	CargoVicepresidente     Cargo = "VICEPRESIDENTE"
+	CargoDirector           Cargo = "DIRECTOR"
+	CargoApoderado          Cargo = "APODERADO"
+	CargoAdministrador      Cargo = "ADMINISTRADOR"
+	CargoLiquidador         Cargo = "LIQUIDADOR"
+	CargoSocio              Cargo = "SOCIO"
+	CargoRepresentanteLegal Cargo = "REPRESENTANTE LEGAL"
+	CargoOtro               Cargo = "OTRO"
+)
+
+// reglasCargo se prueban en orden sobre el texto simplificado; la primera
+// regla cuyas palabras aparecen todas decide el cargo
+var reglasCargo = []struct {
+	palabras []string
+	cargo    Cargo
+}{
+	{[]string{"GERENTE", "GENERAL"}, CargoGerenteGeneral},
+	{[]string{"SUBGERENTE"}, CargoSubgerente},
+	{[]string{"TITULAR"}, CargoTitularGerente},
+	{[]string{"VICEPRESIDENTE"}, CargoVicepresidente},
+	{[]string{"PRESIDENTE"}, CargoPresidente},
+	{[]string{"DIRECTOR"}, CargoDirector},
+	{[]string{"APODERADO"}, CargoApoderado},
+	{[]string{"GERENTE"}, CargoGerente},
+	{[]string{"LIQUIDADOR"}, CargoLiquidador},
+	{[]string{"ADMINISTRADOR"}, CargoAdministrador},
+	{[]string{"SOCIO"}, CargoSocio},
+	{[]string{"REPRESENTANTE"}, CargoRepresentanteLegal},
+}
+
+// palabrasCargo expande abreviaturas y une prefijos separados
+var palabrasCargo = map[string]string{
+	"GTE": "GERENTE", "GRAL": "GENERAL", "GENER": "GENERAL", "APOD": "APODERADO",
+	"DIR": "DIRECTOR", "PDTE": "PRESIDENTE", "PRES": "PRESIDENTE", "ADM": "ADMINISTRADOR",
+	"ADMIN": "ADMINISTRADOR", "REP": "REPRESENTANTE", "REPR": "REPRESENTANTE",
+	"DIRECTORA": "DIRECTOR", "GERENTA": "GERENTE", "APODERADA": "APODERADO",
+	"PRESIDENTA": "PRESIDENTE", "ADMINISTRADORA": "ADMINISTRADOR", "LIQUIDADORA": "LIQUIDADOR",
+}
+
+var normalizarCargo = strings.NewReplacer(
+	"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", ".", " ", "-", " ", "/", " ", "(", " ", ")", " ",
+)
+
+// ParseCargo lleva el cargo de SUNAT al vocabulario controlado. Los cargos
+// que no se reconocen son CargoOtro; el texto vacío o "-" es "".
+func ParseCargo(s string) Cargo {
+	texto := strings.TrimSpace(s)
+	if texto == "" || texto == "-" {
+		return ""
+	}
+
+	var palabras []string
+	anterior := ""
+	for _, p := range strings.Fields(normalizarCargo.Replace(strings.ToUpper(texto))) {
+		if v, ok := palabrasCargo[p]; ok {
+			p = v
+		}
+		// "SUB GERENTE", "VICE PRESIDENTE"
+		if anterior == "SUB" || anterior == "VICE" {
+			palabras[len(palabras)-1] += p
+		} else {
+			palabras = append(palabras, p)
+		}
+		anterior = p
+	}
+
+	for _, r := range reglasCargo {
+		todas := true
+		for _, p := range r.palabras {
+			todas = todas && slices.Contains(palabras, p)
+		}
+		if todas {
+			return r.cargo
+		}
+	}
+	return CargoOtro
+}
+
+// SepararNombre separa un nombre de SUNAT "APELLIDOS, NOMBRES" en sus dos
+// partes. Sin coma no se puede saber dónde terminan los apellidos y retorna
+// ok = false con ambas partes vacías.
+func SepararNombre(s string) (apellidos, nombres string, ok bool) {
+	apellidos, nombres, ok = strings.Cut(s, ",")
+	if !ok {
+		return "", "", false
+	}
+	apellidos = strings.Join(strings.Fields(apellidos), " ")
+	nombres = strings.Join(strings.Fields(nombres), " ")
+	if apellidos == "" || nombres == "" {
+		return "", "", false
+	}
+	return apellidos, nombres, true
+}
+
+// VigenteAl indica si el representante ejercía el cargo en la fecha dada:
+// ya había empezado (o no se sabe desde cuándo) y no tiene fecha hasta, o
+// esta es posterior
+func (r RepresentanteLegal) VigenteAl(t time.Time) bool {
+	dia := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if desde, ok := r.FechaDesde.Time(); ok && desde.After(dia) {
+		return false
+	}
+	hasta, ok := r.FechaHasta.Time()
+	return !ok || !hasta.Before(dia)
+}
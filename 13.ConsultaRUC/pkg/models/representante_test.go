@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCargo(t *testing.T) {
+	casos := map[string]Cargo{
+		"GERENTE GENERAL":          CargoGerenteGeneral,
+		"GTE. GENERAL":             CargoGerenteGeneral,
+		"Gerente Gral.":            CargoGerenteGeneral,
+		"SUB GERENTE":              CargoSubgerente,
+		"SUBGERENTE DE FINANZAS":   CargoSubgerente,
+		"TITULAR-GERENTE":          CargoTitularGerente,
+		"PRESIDENTE DE DIRECTORIO": CargoPresidente,
+		"VICE-PRESIDENTE":          CargoVicepresidente,
+		"DIRECTORA":                CargoDirector,
+		"APODERADO ESPECIAL":       CargoApoderado,
+		"GERENTE ADMINISTRATIVO":   CargoGerente,
+		"REP. LEGAL":               CargoRepresentanteLegal,
+		"ASESOR":                   CargoOtro,
+		"-":                        "",
+	}
+	for s, esperado := range casos {
+		if c := ParseCargo(s); c != esperado {
+			t.Errorf("ParseCargo(%q) = %q; se esperaba %q", s, c, esperado)
+		}
+	}
+}
+
+func TestSepararNombre(t *testing.T) {
+	if a, n, ok := SepararNombre("FERNANDEZ  QUISPE ,MARIA ELENA"); !ok || a != "FERNANDEZ QUISPE" || n != "MARIA ELENA" {
+		t.Errorf("SepararNombre = %q, %q, %v", a, n, ok)
+	}
+	for _, s := range []string{"FERNANDEZ QUISPE MARIA", "FERNANDEZ,", ", MARIA"} {
+		if _, _, ok := SepararNombre(s); ok {
+			t.Errorf("SepararNombre(%q) aceptado", s)
+		}
+	}
+}
+
+func TestVigenteAl(t *testing.T) {
+	hoy := time.Date(2024, time.June, 15, 18, 0, 0, 0, time.Local)
+	casos := []struct {
+		desde, hasta Fecha
+		vigente      bool
+	}{
+		{NuevaFecha(2020, time.January, 1), Fecha{}, true},
+		{NuevaFecha(2020, time.January, 1), NuevaFecha(2024, time.June, 15), true},
+		{NuevaFecha(2020, time.January, 1), NuevaFecha(2023, time.December, 31), false},
+		{NuevaFecha(2024, time.July, 1), Fecha{}, false},
+		{Fecha{}, Fecha{}, true},
+	}
+	for _, c := range casos {
+		r := RepresentanteLegal{FechaDesde: c.desde, FechaHasta: c.hasta}
+		if v := r.VigenteAl(hoy); v != c.vigente {
+			t.Errorf("VigenteAl(%v → %v) = %v", c.desde, c.hasta, v)
+		}
+	}
+}
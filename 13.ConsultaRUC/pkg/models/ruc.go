@@ -1,25 +1,30 @@
 package models
 
-// Estructura actualizada RUCInfo con el nuevo campo TipoDocumento
+import "github.com/consulta-ruc-scraper/pkg/documento"
+
+// RUCInfo es la ficha RUC. El documento de identidad solo aparece en personas naturales.
 type RUCInfo struct {
-	RUC                       string   `json:"ruc"`
-	RazonSocial               string   `json:"razon_social"`
-	TipoContribuyente         string   `json:"tipo_contribuyente"`
-	TipoDocumento             string   `json:"tipo_documento"` // NUEVO CAMPO
-	NombreComercial           string   `json:"nombre_comercial"`
-	FechaInscripcion          string   `json:"fecha_inscripcion"`
-	FechaInicioActividades    string   `json:"fecha_inicio_actividades"`
-	Estado                    string   `json:"estado"`
-	Condicion                 string   `json:"condicion"`
-	DomicilioFiscal           string   `json:"domicilio_fiscal"`
-	SistemaEmision            string   `json:"sistema_emision"`
-	ActividadComercioExterior string   `json:"actividad_comercio_exterior"`
-	SistemaContabilidad       string   `json:"sistema_contabilidad"`
-	ActividadesEconomicas     []string `json:"actividades_economicas"`
-	ComprobantesPago          []string `json:"comprobantes_pago"`
-	SistemaEmisionElectronica []string `json:"sistema_emision_electronica"`
-	EmisorElectronicoDesde    string   `json:"emisor_electronico_desde"`
-	ComprobantesElectronicos  []string `json:"comprobantes_electronicos"`
-	AfiliadoPLE               string   `json:"afiliado_ple"`
-	Padrones                  []string `json:"padrones"`
+	RUC                       string               `json:"ruc"`
+	RazonSocial               string               `json:"razon_social"`
+	TipoContribuyente         string               `json:"tipo_contribuyente"`
+	TipoDocumento             documento.Tipo       `json:"tipo_documento"` // vacío si SUNAT muestra un tipo que no está en pkg/documento
+	NumeroDocumento           string               `json:"numero_documento"`
+	NombreTitular             string               `json:"nombre_titular"`
+	NombreComercial           string               `json:"nombre_comercial"`
+	FechaInscripcion          Fecha                `json:"fecha_inscripcion"`
+	FechaInicioActividades    Fecha                `json:"fecha_inicio_actividades"`
+	Estado                    string               `json:"estado"`
+	Condicion                 string               `json:"condicion"`
+	DomicilioFiscal           string               `json:"domicilio_fiscal"`
+	DomicilioFiscalDetalle    *Domicilio           `json:"domicilio_fiscal_detalle,omitempty"` // nil si la ficha muestra "-"
+	SistemaEmision            string               `json:"sistema_emision"`
+	ActividadComercioExterior string               `json:"actividad_comercio_exterior"`
+	SistemaContabilidad       string               `json:"sistema_contabilidad"`
+	ActividadesEconomicas     []ActividadEconomica `json:"actividades_economicas"`
+	ComprobantesPago          []string             `json:"comprobantes_pago"`
+	SistemaEmisionElectronica []string             `json:"sistema_emision_electronica"`
+	EmisorElectronicoDesde    Fecha                `json:"emisor_electronico_desde"`
+	ComprobantesElectronicos  []string             `json:"comprobantes_electronicos"`
+	AfiliadoPLE               Fecha                `json:"afiliado_ple"`
+	Padrones                  []string             `json:"padrones"`
 }
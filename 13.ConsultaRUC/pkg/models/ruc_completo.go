@@ -19,8 +19,26 @@ type RUCCompleto struct {
 	RepresentantesLegales  *RepresentantesLegales  `json:"representantes_legales,omitempty"`
 	EstablecimientosAnexos *EstablecimientosAnexos `json:"establecimientos_anexos,omitempty"`
 
+	// EstadoSecciones indica, por clave de sección, si se consultó, no se pidió
+	// o SUNAT no ofrecía el botón; una sección ausente del mapa no se intentó
+	EstadoSecciones map[string]EstadoSeccion `json:"estado_secciones,omitempty"`
+
+	// Adicionales guarda las secciones registradas fuera de SUNAT (ver pkg/secciones)
+	Adicionales map[string]any `json:"adicionales,omitempty"`
+
 	// Metadata
 	FechaConsulta       time.Time       `json:"fecha_consulta"`
 	VersionAPI          string          `json:"version_api"`
 	DeteccionPaginacion map[string]bool `json:"deteccion_paginacion,omitempty"`
+	// Paginacion registra, por sección, las páginas recorridas y las filas verificadas
+	Paginacion map[string]PaginacionSeccion `json:"paginacion,omitempty"`
 }
+
+// EstadoSeccion es el resultado de una consulta adicional
+type EstadoSeccion string
+
+const (
+	SeccionConsultada   EstadoSeccion = "consultada"
+	SeccionNoSolicitada EstadoSeccion = "no_solicitada"
+	SeccionNoDisponible EstadoSeccion = "no_disponible" // el botón no aparece en la ficha
+)
@@ -0,0 +1,510 @@
+package parser
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	identidad "github.com/consulta-ruc-scraper/pkg/documento"
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+var patronFecha = regexp.MustCompile(`(\d{2}/\d{2}/\d{4})`)
+
+// ParseInformacionHistorica extrae los cambios de razón social, condición y domicilio
+func ParseInformacionHistorica(r io.Reader) (*models.InformacionHistorica, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &models.InformacionHistorica{}
+
+	doc.Find("table").Each(func(i int, tabla *goquery.Selection) {
+		headers := extraerCabeceras(tabla)
+		if len(headers) == 0 {
+			return
+		}
+
+		firstHeaderText := strings.ToLower(headers[0])
+		rows := filas(tabla)
+
+		switch {
+		case strings.Contains(firstHeaderText, "nombre") || strings.Contains(firstHeaderText, "razón social") || strings.Contains(firstHeaderText, "razon social"):
+			procesarCambiosRazonSocial(rows, info)
+
+		case strings.Contains(firstHeaderText, "condición") && strings.Contains(firstHeaderText, "contribuyente"):
+			procesarCondicionContribuyente(rows, info)
+
+		case strings.Contains(firstHeaderText, "dirección") || strings.Contains(firstHeaderText, "domicilio"):
+			procesarCambiosDomicilio(rows, info)
+		}
+	})
+
+	return info, nil
+}
+
+func procesarCambiosRazonSocial(rows [][]string, info *models.InformacionHistorica) {
+	for _, cells := range rows {
+		if len(cells) >= 2 {
+			nombre := cells[0]
+			if nombre != "-" && !strings.Contains(nombre, "No hay Información") {
+				info.RazonesSociales = append(info.RazonesSociales, models.RazonSocialHistorica{
+					Nombre:      nombre,
+					FechaDeBaja: fechaOpcional("fecha de baja", cells[1]),
+				})
+			}
+		}
+	}
+}
+
+func procesarCondicionContribuyente(rows [][]string, info *models.InformacionHistorica) {
+	for _, cells := range rows {
+		if len(cells) >= 3 {
+			condicion := cells[0]
+			if condicion != "-" && condicion != "" {
+				info.Condiciones = append(info.Condiciones, models.CondicionHistorica{
+					Condicion: condicion,
+					Desde:     fechaOpcional("desde", cells[1]),
+					Hasta:     fechaOpcional("hasta", cells[2]),
+				})
+			}
+		}
+	}
+}
+
+func procesarCambiosDomicilio(rows [][]string, info *models.InformacionHistorica) {
+	for _, cells := range rows {
+		if len(cells) >= 2 {
+			direccion := cells[0]
+			if direccion != "-" && direccion != "" {
+				info.Domicilios = append(info.Domicilios, models.DomicilioFiscalHistorico{
+					Direccion:        direccion,
+					DireccionDetalle: domicilio(direccion),
+					FechaDeBaja:      fechaOpcional("fecha de baja", cells[1]),
+				})
+			}
+		}
+	}
+}
+
+// ParseDeudaCoactiva extrae las deudas en cobranza coactiva
+func ParseDeudaCoactiva(r io.Reader) (*models.DeudaCoactiva, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	deuda := &models.DeudaCoactiva{}
+
+	// Verificar si aparece el mensaje "no registra deuda coactiva"
+	alerta := doc.Find(".alert-info").First()
+	if alerta.Length() > 0 && strings.Contains(strings.ToLower(texto(alerta)), "no registra deuda coactiva") {
+		deuda.Deudas = []models.DetalleDeuda{}
+		return deuda, nil
+	}
+
+	tabla := doc.Find("table.table").First()
+	if tabla.Length() == 0 {
+		return deuda, nil
+	}
+
+	for i, cells := range filas(tabla) {
+		if len(cells) >= 4 {
+			// Un monto ilegible no se cuenta como cero: la fila se informa aparte
+			monto, err := models.ParseMonto(cells[0])
+			if err == nil {
+				err = deuda.AgregarDeuda(models.DetalleDeuda{
+					Monto:               monto,
+					PeriodoTributario:   periodoOpcional("periodo tributario", cells[1]),
+					FechaInicioCobranza: fechaOpcional("fecha de inicio de cobranza", cells[2]),
+					Entidad:             cells[3],
+				})
+			}
+			if err != nil {
+				log.Printf("[WARN] Deuda coactiva, fila %d: %v\n", i+1, err)
+				deuda.FilasInvalidas = append(deuda.FilasInvalidas, models.FilaInvalida{
+					Fila:  i + 1,
+					Texto: strings.Join(cells, " | "),
+					Error: err.Error(),
+				})
+			}
+		}
+	}
+
+	deuda.CantidadDocumentos = len(deuda.Deudas)
+	return deuda, nil
+}
+
+// ParseOmisionesTributarias extrae las omisiones tributarias
+func ParseOmisionesTributarias(r io.Reader) (*models.OmisionesTributarias, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	omis := &models.OmisionesTributarias{}
+
+	alerta := doc.Find(".alert-info").First()
+	if alerta.Length() > 0 && strings.Contains(texto(alerta), "no registra") {
+		return omis, nil
+	}
+
+	tabla := doc.Find("table.table").First()
+	if tabla.Length() == 0 {
+		return omis, nil
+	}
+
+	for _, cells := range filas(tabla) {
+		if len(cells) >= 4 {
+			omision := models.Omision{
+				Periodo:          periodoOpcional("periodo", cells[0]),
+				Tributo:          cells[1],
+				TipoDeclaracion:  cells[2],
+				FechaVencimiento: fechaOpcional("fecha de vencimiento", cells[3]),
+			}
+
+			if len(cells) >= 5 {
+				omision.Estado = cells[4]
+			}
+
+			omis.Omisiones = append(omis.Omisiones, omision)
+		}
+	}
+
+	omis.TieneOmisiones = len(omis.Omisiones) > 0
+	omis.CantidadOmisiones = len(omis.Omisiones)
+	return omis, nil
+}
+
+// ParseCantidadTrabajadores extrae la cantidad de trabajadores por periodo
+func ParseCantidadTrabajadores(r io.Reader) (*models.CantidadTrabajadores, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	trab := &models.CantidadTrabajadores{}
+
+	tabla := doc.Find("table.table").First()
+	if tabla.Length() == 0 {
+		return trab, nil
+	}
+
+	for _, cells := range filas(tabla) {
+		if len(cells) >= 4 {
+			// Parsear cantidades manejando "NE"
+			trabajadores := parseCantidadConNE(cells[1])
+			pensionistas := parseCantidadConNE(cells[2])
+			prestadores := parseCantidadConNE(cells[3])
+
+			detalle := models.DetalleTrabajadores{
+				Periodo:                     periodoOpcional("periodo", cells[0]),
+				CantidadTrabajadores:        trabajadores,
+				CantidadPensionistas:        pensionistas,
+				CantidadPrestadoresServicio: prestadores,
+				Total:                       trabajadores + pensionistas + prestadores,
+			}
+
+			trab.DetallePorPeriodo = append(trab.DetallePorPeriodo, detalle)
+
+			if !slices.Contains(trab.PeriodosDisponibles, detalle.Periodo) {
+				trab.PeriodosDisponibles = append(trab.PeriodosDisponibles, detalle.Periodo)
+			}
+		}
+	}
+
+	return trab, nil
+}
+
+// ParseActasProbatorias extrae las actas probatorias
+func ParseActasProbatorias(r io.Reader) (*models.ActasProbatorias, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	actas := &models.ActasProbatorias{}
+
+	rows := filas(doc.Find("table.table").First())
+	if len(rows) == 0 {
+		return actas, nil
+	}
+
+	// Verificar si hay mensaje de "no existe información"
+	if len(rows[0]) == 1 && strings.Contains(strings.ToLower(rows[0][0]), "no existe información") {
+		return actas, nil
+	}
+
+	for _, cells := range rows {
+		if len(cells) < 8 {
+			continue // Saltar filas incompletas
+		}
+
+		actas.Actas = append(actas.Actas, models.ActaProbatoria{
+			NumeroActa:            cells[0],
+			FechaActa:             fechaOpcional("fecha de acta", cells[1]),
+			LugarIntervencion:     cells[2],
+			ArticuloNumeral:       cells[3],
+			DescripcionInfraccion: cells[4],
+			NumeroRIROZ:           cells[5],
+			TipoRIROZ:             cells[6],
+			ActaReconocimiento:    cells[7],
+		})
+	}
+
+	actas.CantidadActas = len(actas.Actas)
+	actas.TieneActas = actas.CantidadActas > 0
+	return actas, nil
+}
+
+// ParseFacturasFisicas extrae las autorizaciones y bajas de facturas físicas
+func ParseFacturasFisicas(r io.Reader) (*models.FacturasFisicas, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	facturas := &models.FacturasFisicas{}
+	tablas := doc.Find("table.table")
+
+	// Primera tabla: "Facturas autorizadas"
+	// Último Nro Autorización | Fecha de Última Autorización | Comprobante | Serie | del | al
+	for _, cells := range filasConDatos(tablas.Eq(0)) {
+		facturas.Autorizaciones = append(facturas.Autorizaciones, models.FacturaAutorizada{
+			NumeroAutorizacion: cells[0],
+			FechaAutorizacion:  fechaOpcional("fecha de autorización", cells[1]),
+			TipoComprobante:    cells[2],
+			Serie:              cells[3],
+			NumeroInicial:      cells[4],
+			NumeroFinal:        cells[5],
+		})
+	}
+
+	// Segunda tabla: "Facturas dadas de baja y/o canceladas"
+	// Nro Orden | Fecha de baja y/o cancelación | Comprobante | Serie | del | al
+	for _, cells := range filasConDatos(tablas.Eq(1)) {
+		facturas.CanceladasOBajas = append(facturas.CanceladasOBajas, models.FacturaBajaOCancelada{
+			NumeroAutorizacion: cells[0],                                 // Usando Nro Orden como identificador
+			FechaAutorizacion:  fechaOpcional("fecha de baja", cells[1]), // Fecha de baja/cancelación
+			TipoComprobante:    cells[2],
+			Serie:              cells[3],
+			NumeroInicial:      cells[4],
+			NumeroFinal:        cells[5],
+		})
+	}
+
+	facturas.TieneAutorizacion = len(facturas.Autorizaciones) > 0 || len(facturas.CanceladasOBajas) > 0
+	return facturas, nil
+}
+
+// filasConDatos devuelve las filas de facturas con al menos 6 columnas y un
+// identificador válido, omitiendo las filas de "No hay Información"
+func filasConDatos(tabla *goquery.Selection) [][]string {
+	var resultado [][]string
+	for _, cells := range filas(tabla) {
+		if len(cells) < 6 {
+			continue
+		}
+		if cells[0] == "" || cells[0] == "NE" {
+			continue
+		}
+		resultado = append(resultado, cells)
+	}
+	return resultado
+}
+
+// ParseReactivaPeru extrae la información del programa Reactiva Perú
+func ParseReactivaPeru(r io.Reader) (*models.ReactivaPeru, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reactiva := &models.ReactivaPeru{}
+
+	// Ejemplo: "REACTIVA PERÚ DE 20606316977 - FERNANDEZ CONSULTORES SG & ASOCIADOS EIRL"
+	reactiva.RazonSocial = razonSocialTitulo(doc)
+
+	// Respuesta a "¿Tiene deuda en cobranza coactiva mayor a una (1) UIT?"
+	doc.Find(".label").Each(func(i int, label *goquery.Selection) {
+		switch texto(label) {
+		case "NO":
+			if label.HasClass("label-success") {
+				reactiva.TieneDeudaCoactiva = false
+			}
+		case "SÍ", "SI":
+			if label.HasClass("label-danger") {
+				reactiva.TieneDeudaCoactiva = true
+			}
+		}
+	})
+
+	// Fecha de actualización y referencia legal
+	doc.Find("h5").Each(func(i int, h5 *goquery.Selection) {
+		h5Text := texto(h5)
+		if strings.Contains(h5Text, "información está actualizada al") {
+			if matches := patronFecha.FindStringSubmatch(h5Text); len(matches) > 1 {
+				reactiva.FechaActualizacion = fechaOpcional("fecha de actualización", matches[1])
+			}
+		} else if strings.Contains(h5Text, "Decreto Legislativo") {
+			reactiva.ReferenciaLegal = h5Text
+		}
+	})
+
+	return reactiva, nil
+}
+
+// ParseProgramaCovid19 extrae la información del programa de garantías COVID-19
+func ParseProgramaCovid19(r io.Reader) (*models.ProgramaCovid19, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	programaCovid := &models.ProgramaCovid19{}
+
+	// Ejemplo: "PROGRAMA DE GARANTÍAS COVID-19 DE 20606316977 - FERNANDEZ CONSULTORES SG & ASOCIADOS EIRL"
+	programaCovid.RazonSocial = razonSocialTitulo(doc)
+
+	doc.Find(".label").Each(func(i int, label *goquery.Selection) {
+		switch texto(label) {
+		case "NO":
+			if label.HasClass("label-success") {
+				programaCovid.TieneDeudaCoactiva = false
+				programaCovid.ParticipaPrograma = false
+			}
+		case "SÍ", "SI":
+			if label.HasClass("label-success") {
+				programaCovid.ParticipaPrograma = true
+			} else if label.HasClass("label-danger") {
+				programaCovid.TieneDeudaCoactiva = true
+			}
+		}
+	})
+
+	doc.Find("h5").Each(func(i int, h5 *goquery.Selection) {
+		h5Text := texto(h5)
+
+		if strings.Contains(h5Text, "actualizada al") {
+			if matches := patronFecha.FindStringSubmatch(h5Text); len(matches) > 1 {
+				programaCovid.FechaActualizacion = fechaOpcional("fecha de actualización", matches[1])
+			}
+		}
+
+		// Base legal (puede ser Ley N° 31050 o similar)
+		if strings.Contains(h5Text, "Ley N°") ||
+			strings.Contains(h5Text, "Decreto Legislativo") ||
+			strings.Contains(h5Text, "Decreto Supremo") {
+			programaCovid.BaseLegal = h5Text
+		}
+	})
+
+	doc.Find(".alert-info, .alert-success, .alert-warning").Each(func(i int, alerta *goquery.Selection) {
+		alertText := strings.ToLower(texto(alerta))
+		if strings.Contains(alertText, "no participa") || strings.Contains(alertText, "sin programa") {
+			programaCovid.ParticipaPrograma = false
+		} else if strings.Contains(alertText, "participa") {
+			programaCovid.ParticipaPrograma = true
+		}
+	})
+
+	return programaCovid, nil
+}
+
+// razonSocialTitulo extrae la razón social del primer h3 con formato "... DE <ruc> - <razón social>"
+func razonSocialTitulo(doc *goquery.Document) string {
+	titulo := texto(doc.Find("h3").First())
+	if parts := strings.SplitN(titulo, " - ", 2); len(parts) == 2 {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
+
+// ParseRepresentantesLegales extrae los representantes legales
+func ParseRepresentantesLegales(r io.Reader) (*models.RepresentantesLegales, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	representantes := &models.RepresentantesLegales{}
+
+	doc.Find("table").Each(func(i int, tabla *goquery.Selection) {
+		// Comprobamos si esta tabla contiene los encabezados correctos
+		if !contieneCabeceras(extraerCabeceras(tabla), []string{"Documento", "Cargo", "Nombre"}) {
+			return
+		}
+
+		for _, cells := range filas(tabla) {
+			if len(cells) < 5 {
+				log.Printf("[WARN] Fila ignorada: %d columnas (esperado al menos 5)\n", len(cells))
+				continue
+			}
+
+			rep := models.RepresentanteLegal{
+				NumeroDocumento: cells[1],
+				NombreCompleto:  cells[2],
+				Cargo:           models.ParseCargo(cells[3]),
+				CargoSUNAT:      cells[3],
+				FechaDesde:      fechaOpcional("fecha desde", cells[4]),
+			}
+			if len(cells) >= 6 {
+				rep.FechaHasta = fechaOpcional("fecha hasta", cells[5])
+			}
+			rep.Vigente = rep.VigenteAl(time.Now())
+
+			if doc, err := identidad.Parse(cells[0], cells[1]); err != nil {
+				log.Printf("[WARN] Representante %q sin documento normalizado: %v\n", cells[2], err)
+			} else {
+				rep.TipoDocumento, rep.NumeroDocumento = doc.Tipo, doc.Numero
+			}
+			if apellidos, nombres, ok := models.SepararNombre(cells[2]); ok {
+				rep.Apellidos, rep.Nombres = apellidos, nombres
+			} else {
+				log.Printf("[WARN] Nombre de representante sin \"APELLIDOS, NOMBRES\": %q\n", cells[2])
+			}
+			if rep.Cargo == models.CargoOtro {
+				log.Printf("[WARN] Cargo de representante fuera del vocabulario: %q\n", cells[3])
+			}
+
+			representantes.Representantes = append(representantes.Representantes, rep)
+		}
+	})
+
+	return representantes, nil
+}
+
+// ParseEstablecimientosAnexos extrae los establecimientos anexos
+func ParseEstablecimientosAnexos(r io.Reader) (*models.EstablecimientosAnexos, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	estab := &models.EstablecimientosAnexos{}
+
+	for _, cells := range filas(doc.Find("table.table").First()) {
+		if len(cells) < 4 {
+			continue
+		}
+
+		// Validar campos críticos
+		if cells[0] != "" && cells[1] != "" {
+			estab.Establecimientos = append(estab.Establecimientos, models.EstablecimientoAnexo{
+				Codigo:              cells[0],
+				TipoEstablecimiento: cells[1],
+				Direccion:           cells[2],
+				DireccionDetalle:    domicilio(cells[2]),
+				ActividadEconomica:  cells[3],
+			})
+		}
+	}
+
+	estab.CantidadAnexos = len(estab.Establecimientos)
+	return estab, nil
+}
@@ -0,0 +1,231 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	identidad "github.com/consulta-ruc-scraper/pkg/documento"
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/ruc"
+)
+
+// ErrFichaNoEncontrada indica que el HTML no contiene la ficha RUC
+var ErrFichaNoEncontrada = errors.New("la página no contiene la ficha RUC")
+
+// ErrRUCNoExiste indica que SUNAT respondió que el RUC consultado no está registrado
+var ErrRUCNoExiste = errors.New("el RUC consultado no existe")
+
+// mensajesRUCNoExiste son los textos (en minúsculas) con que SUNAT informa un RUC no registrado
+var mensajesRUCNoExiste = []string{
+	"consultado no existe",
+	"no existe el número de ruc",
+	"no existe el numero de ruc",
+}
+
+// ParseFicha extrae la información básica de la página de resultados
+func ParseFicha(r io.Reader) (*models.RUCInfo, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	listItems := doc.Find(".list-group-item")
+	if listItems.Length() == 0 {
+		cuerpo := strings.ToLower(texto(doc.Find("body")))
+		for _, mensaje := range mensajesRUCNoExiste {
+			if strings.Contains(cuerpo, mensaje) {
+				return nil, ErrRUCNoExiste
+			}
+		}
+		return nil, ErrFichaNoEncontrada
+	}
+
+	info := &models.RUCInfo{}
+
+	listItems.Each(func(i int, item *goquery.Selection) {
+		// Check if item has the standard structure
+		rows := item.Find(".row")
+		if rows.Length() == 0 {
+			return
+		}
+
+		cols := rows.First().Find("[class*='col-sm']")
+
+		if cols.Length() >= 2 {
+			headings := cols.Eq(0).Find(".list-group-item-heading")
+
+			if headings.Length() > 0 {
+				label := texto(headings.First())
+
+				// Check for tables FIRST (before extracting text)
+				tables := cols.Eq(1).Find("table")
+				if tables.Length() > 0 {
+					extractTableData(tables.First(), label, info)
+				} else {
+					texts := cols.Eq(1).Find(".list-group-item-text, .list-group-item-heading")
+
+					if texts.Length() > 0 {
+						value := texto(texts.First())
+
+						if strings.Contains(label, "Número de RUC") {
+							// Formato: "20606316977 - RAZÓN SOCIAL"
+							parts := strings.SplitN(value, " - ", 2)
+							info.RUC = strings.TrimSpace(parts[0])
+							if len(parts) > 1 {
+								info.RazonSocial = strings.TrimSpace(parts[1])
+							}
+						} else {
+							mapFieldToStruct(label, value, info)
+						}
+					}
+				}
+			}
+		}
+
+		// Handle rows with 4 columns (2 label-value pairs)
+		if cols.Length() == 4 {
+			for _, par := range [][2]int{{0, 1}, {2, 3}} {
+				headings := cols.Eq(par[0]).Find(".list-group-item-heading")
+				texts := cols.Eq(par[1]).Find(".list-group-item-text")
+
+				if headings.Length() > 0 && texts.Length() > 0 {
+					mapFieldToStruct(texto(headings.First()), texto(texts.First()), info)
+				}
+			}
+		}
+	})
+
+	verificarDNI(info)
+	return info, nil
+}
+
+func mapFieldToStruct(label, value string, info *models.RUCInfo) {
+	label = strings.ToLower(label)
+
+	switch {
+	case strings.Contains(label, "razón social") || strings.Contains(label, "razon social"):
+		info.RazonSocial = value
+	case strings.Contains(label, "tipo contribuyente"):
+		info.TipoContribuyente = value
+	case strings.Contains(label, "tipo de documento"):
+		parseTipoDocumento(value, info)
+	case strings.Contains(label, "nombre comercial"):
+		info.NombreComercial = value
+	case strings.Contains(label, "fecha de inscripción") || strings.Contains(label, "fecha de inscripcion"):
+		info.FechaInscripcion = fechaOpcional("fecha de inscripción", value)
+	case strings.Contains(label, "fecha de inicio de actividades"):
+		info.FechaInicioActividades = fechaOpcional("fecha de inicio de actividades", value)
+	case strings.Contains(label, "estado del contribuyente"):
+		info.Estado = value
+	case strings.Contains(label, "condición del contribuyente") || strings.Contains(label, "condicion del contribuyente"):
+		info.Condicion = value
+	case strings.Contains(label, "domicilio fiscal"):
+		info.DomicilioFiscal = value
+		info.DomicilioFiscalDetalle = domicilio(value)
+	case strings.Contains(label, "sistema emisión de comprobante") || strings.Contains(label, "sistema emision de comprobante"):
+		info.SistemaEmision = value
+	case strings.Contains(label, "actividad comercio exterior"):
+		info.ActividadComercioExterior = value
+	case strings.Contains(label, "sistema contabilidad"):
+		info.SistemaContabilidad = value
+	case strings.Contains(label, "emisor electrónico desde") || strings.Contains(label, "emisor electronico desde"):
+		info.EmisorElectronicoDesde = fechaOpcional("emisor electrónico desde", value)
+	case strings.Contains(label, "comprobantes electrónicos") || strings.Contains(label, "comprobantes electronicos"):
+		// Separar por comas y convertir a array
+		if value != "" {
+			items := strings.Split(value, ",")
+			for i, item := range items {
+				items[i] = strings.TrimSpace(item)
+			}
+			info.ComprobantesElectronicos = items
+		}
+	case strings.Contains(label, "afiliado al ple desde"):
+		info.AfiliadoPLE = fechaOpcional("afiliado al PLE desde", value)
+	}
+}
+
+// parseTipoDocumento separa el documento de identidad de personas naturales,
+// p. ej. "DNI 76366932 - TARRILLO MARRUFO, YAMELITH MARILYN". Si el tipo o el
+// número no son válidos avisa y, como en los representantes legales, guarda
+// el número y el titular tal como los muestra SUNAT, sin tipo si no se reconoce.
+func parseTipoDocumento(value string, info *models.RUCInfo) {
+	doc, titular, err := identidad.ParseIdentidad(value)
+	if err != nil {
+		log.Printf("[WARN] Documento del titular sin normalizar (%q): %v\n", value, err)
+		doc, titular = identidadSinValidar(value)
+	}
+	info.TipoDocumento = doc.Tipo
+	info.NumeroDocumento = doc.Numero
+	info.NombreTitular = titular
+
+	// Si no se ha establecido RazonSocial desde el RUC, usar el nombre del documento
+	if info.RazonSocial == "" {
+		info.RazonSocial = titular
+	}
+}
+
+// identidadSinValidar separa "TIPO NÚMERO - TITULAR" sin validar el número;
+// el tipo queda vacío si no está en pkg/documento
+func identidadSinValidar(value string) (identidad.Documento, string) {
+	etiquetaNumero, titular, _ := strings.Cut(strings.Join(strings.Fields(value), " "), " - ")
+	var doc identidad.Documento
+	if campos := strings.Fields(etiquetaNumero); len(campos) >= 2 {
+		doc.Tipo, _ = identidad.ParseTipo(strings.Join(campos[:len(campos)-1], " "))
+		doc.Numero = campos[len(campos)-1]
+	}
+	return doc, strings.TrimSpace(titular)
+}
+
+// verificarDNI avisa si el DNI de la ficha no es el que contiene un RUC de persona natural
+func verificarDNI(info *models.RUCInfo) {
+	r, err := ruc.Parse(info.RUC)
+	if err != nil || info.TipoDocumento != identidad.DNI {
+		return
+	}
+	if dni, err := r.DNI(); err == nil && dni != info.NumeroDocumento {
+		log.Printf("[WARN] El RUC %s corresponde al DNI %s, pero la ficha muestra %s\n", r, dni, info.NumeroDocumento)
+	}
+}
+
+func extractTableData(table *goquery.Selection, label string, info *models.RUCInfo) {
+	items := []string{}
+
+	table.Find("tr").Each(func(i int, row *goquery.Selection) {
+		text := texto(row)
+		if text != "" && text != "NINGUNO" {
+			items = append(items, text)
+		}
+	})
+
+	if len(items) == 0 {
+		return
+	}
+
+	label = strings.ToLower(label)
+
+	switch {
+	case strings.Contains(label, "actividad") && strings.Contains(label, "económica"):
+		actividades := make([]models.ActividadEconomica, 0, len(items))
+		for _, item := range items {
+			actividad, err := models.ParseActividadEconomica(item)
+			if err != nil {
+				// Se guarda la fila tal como viene, sin código CIIU
+				log.Printf("[WARN] Actividad económica sin código CIIU: %v\n", err)
+				actividad = models.ActividadEconomica{Descripcion: item}
+			} else if !actividad.EnCatalogo() {
+				log.Printf("[WARN] Código CIIU %s (Rev.%d) no está en el catálogo\n", actividad.CodigoCIIU, actividad.RevisionCIIU)
+			}
+			actividades = append(actividades, actividad)
+		}
+		info.ActividadesEconomicas = actividades
+	case strings.Contains(label, "comprobantes de pago"):
+		info.ComprobantesPago = items
+	case strings.Contains(label, "sistema de emisión electrónica") || strings.Contains(label, "sistema de emision electronica"):
+		info.SistemaEmisionElectronica = items
+	case strings.Contains(label, "padrones"):
+		info.Padrones = items
+	}
+}
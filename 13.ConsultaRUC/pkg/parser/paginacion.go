@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	patronContador   = regexp.MustCompile(`(\d+)\s+a\s+(\d+)\s+de\s+(\d+)`)
+	patronPaginacion = regexp.MustCompile(`javascript:\s*paginacion\((\d+)\)`)
+)
+
+// Paginacion describe el paginador de las tablas de SUNAT:
+// "1 a 30 de 45    Páginas: 1 2 Siguiente"
+type Paginacion struct {
+	Desde int
+	Hasta int
+	Total int // 0 si la página no muestra el contador
+	// Paginas contiene los números enlazados con javascript:paginacion(n)
+	Paginas []int
+	// Siguiente es la página a solicitar a continuación; 0 si es la última
+	Siguiente int
+}
+
+// TieneMasPaginas indica si quedan páginas por recorrer
+func (p *Paginacion) TieneMasPaginas() bool {
+	return p.Siguiente > 0
+}
+
+// ParsePaginacion extrae el contador "X a Y de Z" y los enlaces de paginación
+func ParsePaginacion(r io.Reader) (*Paginacion, error) {
+	doc, err := documento(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Paginacion{}
+
+	// El contador suele estar en su propia celda; si no, se busca en todo el texto
+	contador := ""
+	doc.Find("td").EachWithBreak(func(i int, td *goquery.Selection) bool {
+		t := texto(td)
+		if m := patronContador.FindString(t); m != "" && m == t {
+			contador = t
+			return false
+		}
+		return true
+	})
+	if contador == "" {
+		contador = texto(doc.Find("body"))
+	}
+	if m := patronContador.FindStringSubmatch(contador); len(m) == 4 {
+		p.Desde, _ = strconv.Atoi(m[1])
+		p.Hasta, _ = strconv.Atoi(m[2])
+		p.Total, _ = strconv.Atoi(m[3])
+	}
+
+	doc.Find("a[href*='paginacion']").Each(func(i int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		m := patronPaginacion.FindStringSubmatch(href)
+		if len(m) != 2 {
+			return
+		}
+		n, _ := strconv.Atoi(m[1])
+
+		if strings.Contains(strings.ToLower(texto(a)), "siguiente") {
+			p.Siguiente = n
+		} else if !slices.Contains(p.Paginas, n) {
+			p.Paginas = append(p.Paginas, n)
+		}
+	})
+
+	// Sin enlace "Siguiente": se deduce la página actual a partir del contador
+	if p.Siguiente == 0 && p.Total > p.Hasta && p.Hasta >= p.Desde && p.Desde > 0 {
+		actual := 1 + (p.Desde-1)/(p.Hasta-p.Desde+1)
+		if slices.Contains(p.Paginas, actual+1) {
+			p.Siguiente = actual + 1
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,127 @@
+// Package parser convierte el HTML de las páginas de consulta RUC de SUNAT
+// en las estructuras de pkg/models sin depender de un navegador.
+//
+// Cada función recibe un io.Reader con el HTML completo de la página; para
+// parsear un []byte basta con envolverlo en bytes.NewReader.
+package parser
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+// documento carga el HTML en un documento goquery
+func documento(r io.Reader) (*goquery.Document, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error al parsear HTML: %w", err)
+	}
+	return doc, nil
+}
+
+// texto devuelve el texto de la selección con los espacios normalizados,
+// equivalente a lo que devuelve el navegador con innerText
+func texto(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// celdas devuelve los textos de las celdas td de una fila
+func celdas(fila *goquery.Selection) []string {
+	var textos []string
+	fila.Find("td").Each(func(i int, td *goquery.Selection) {
+		textos = append(textos, texto(td))
+	})
+	return textos
+}
+
+// filas devuelve las celdas de cada fila del cuerpo de la tabla
+func filas(tabla *goquery.Selection) [][]string {
+	var resultado [][]string
+	tabla.Find("tbody tr").Each(func(i int, fila *goquery.Selection) {
+		resultado = append(resultado, celdas(fila))
+	})
+	return resultado
+}
+
+func extraerCabeceras(tabla *goquery.Selection) []string {
+	var headers []string
+	tabla.Find("thead th").Each(func(i int, s *goquery.Selection) {
+		headers = append(headers, texto(s))
+	})
+	return headers
+}
+
+func contieneCabeceras(headers []string, requeridos []string) bool {
+	for _, req := range requeridos {
+		encontrado := false
+		for _, h := range headers {
+			if strings.Contains(strings.ToLower(h), strings.ToLower(req)) {
+				encontrado = true
+				break
+			}
+		}
+		if !encontrado {
+			return false
+		}
+	}
+	return true
+}
+
+// fechaOpcional convierte una celda de fecha con models.ParseFecha. Una fecha
+// que no se entiende no descarta la fila ni la sección: se avisa y queda
+// desconocida.
+func fechaOpcional(campo, s string) models.Fecha {
+	f, err := models.ParseFecha(s)
+	if err != nil {
+		log.Printf("[WARN] Fecha ignorada en %s: %v\n", campo, err)
+	}
+	return f
+}
+
+// periodoOpcional es fechaOpcional para los periodos (models.ParsePeriodo)
+func periodoOpcional(campo, s string) models.Periodo {
+	p, err := models.ParsePeriodo(s)
+	if err != nil {
+		log.Printf("[WARN] Periodo ignorado en %s: %v\n", campo, err)
+	}
+	return p
+}
+
+// domicilio separa una dirección con models.ParseDomicilio. Una ubicación que
+// no está en el catálogo de ubigeos no es un error de parseo: se avisa y la
+// dirección se guarda sin ubigeo.
+func domicilio(direccion string) *models.Domicilio {
+	d := models.ParseDomicilio(direccion)
+	if d != nil && d.Ubigeo == "" {
+		log.Printf("[WARN] Dirección sin ubigeo en el catálogo: %q\n", direccion)
+	}
+	return d
+}
+
+// parseCantidadConNE convierte una celda numérica manejando "NE" y similares
+func parseCantidadConNE(text string) int {
+	text = strings.TrimSpace(text)
+
+	// Casos donde se considera como "no existe" o "sin datos"
+	switch strings.ToUpper(text) {
+	case "NE", "N/A", "NO EXISTE", "SIN DATOS", "", "-":
+		return 0
+	}
+
+	// Remover caracteres no numéricos comunes (comas, espacios)
+	text = strings.ReplaceAll(text, ",", "")
+	text = strings.ReplaceAll(text, " ", "")
+
+	if num, err := strconv.Atoi(text); err == nil {
+		return num
+	}
+
+	// Si no se puede convertir, retornar 0 por defecto
+	return 0
+}
@@ -0,0 +1,469 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	identidad "github.com/consulta-ruc-scraper/pkg/documento"
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+var update = flag.Bool("update", false, "regenera los archivos golden de testdata")
+
+// paginasSeccion asocia cada página guardada en testdata/<caso>/ con su parser.
+// Las secciones paginadas (seccion != "") leen además <archivo>_2.html, _3...
+// y fusionan el resultado como lo hace el scraper.
+var paginasSeccion = []struct {
+	archivo string
+	seccion string
+	parse   func(r io.Reader, rc *models.RUCCompleto) (filas int, err error)
+}{
+	{"ficha", "", func(r io.Reader, rc *models.RUCCompleto) (int, error) {
+		info, err := ParseFicha(r)
+		if err != nil {
+			return 0, err
+		}
+		rc.InformacionBasica = *info
+		return 0, nil
+	}},
+	{"historica", "", func(r io.Reader, rc *models.RUCCompleto) (n int, err error) {
+		rc.InformacionHistorica, err = ParseInformacionHistorica(r)
+		return 0, err
+	}},
+	{"deuda", "Deuda Coactiva", func(r io.Reader, rc *models.RUCCompleto) (int, error) {
+		p, err := ParseDeudaCoactiva(r)
+		if err != nil {
+			return 0, err
+		}
+		if rc.DeudaCoactiva == nil {
+			rc.DeudaCoactiva = p
+		} else {
+			rc.DeudaCoactiva.Fusionar(p)
+		}
+		return len(p.Deudas), nil
+	}},
+	{"omisiones", "Omisiones Tributarias", func(r io.Reader, rc *models.RUCCompleto) (int, error) {
+		p, err := ParseOmisionesTributarias(r)
+		if err != nil {
+			return 0, err
+		}
+		if rc.OmisionesTributarias == nil {
+			rc.OmisionesTributarias = p
+		} else {
+			rc.OmisionesTributarias.Fusionar(p)
+		}
+		return len(p.Omisiones), nil
+	}},
+	{"trabajadores", "Cantidad de Trabajadores", func(r io.Reader, rc *models.RUCCompleto) (int, error) {
+		p, err := ParseCantidadTrabajadores(r)
+		if err != nil {
+			return 0, err
+		}
+		if rc.CantidadTrabajadores == nil {
+			rc.CantidadTrabajadores = p
+		} else {
+			rc.CantidadTrabajadores.Fusionar(p)
+		}
+		return len(p.DetallePorPeriodo), nil
+	}},
+	{"actas", "", func(r io.Reader, rc *models.RUCCompleto) (n int, err error) {
+		rc.ActasProbatorias, err = ParseActasProbatorias(r)
+		return 0, err
+	}},
+	{"facturas", "", func(r io.Reader, rc *models.RUCCompleto) (n int, err error) {
+		rc.FacturasFisicas, err = ParseFacturasFisicas(r)
+		return 0, err
+	}},
+	{"reactiva", "", func(r io.Reader, rc *models.RUCCompleto) (n int, err error) {
+		rc.ReactivaPeru, err = ParseReactivaPeru(r)
+		return 0, err
+	}},
+	{"covid", "", func(r io.Reader, rc *models.RUCCompleto) (n int, err error) {
+		rc.ProgramaCovid19, err = ParseProgramaCovid19(r)
+		return 0, err
+	}},
+	{"representantes", "Representantes Legales", func(r io.Reader, rc *models.RUCCompleto) (int, error) {
+		p, err := ParseRepresentantesLegales(r)
+		if err != nil {
+			return 0, err
+		}
+		if rc.RepresentantesLegales == nil {
+			rc.RepresentantesLegales = p
+		} else {
+			rc.RepresentantesLegales.Fusionar(p)
+		}
+		return len(p.Representantes), nil
+	}},
+	{"anexos", "Establecimientos Anexos", func(r io.Reader, rc *models.RUCCompleto) (int, error) {
+		p, err := ParseEstablecimientosAnexos(r)
+		if err != nil {
+			return 0, err
+		}
+		if rc.EstablecimientosAnexos == nil {
+			rc.EstablecimientosAnexos = p
+		} else {
+			rc.EstablecimientosAnexos.Fusionar(p)
+		}
+		return len(p.Establecimientos), nil
+	}},
+}
+
+// parsearCaso arma un RUCCompleto con todas las páginas presentes en dir
+func parsearCaso(t *testing.T, dir string) *models.RUCCompleto {
+	t.Helper()
+
+	rc := &models.RUCCompleto{}
+	for _, p := range paginasSeccion {
+		var paginacion models.PaginacionSeccion
+
+		for n := 1; ; n++ {
+			nombre := p.archivo + ".html"
+			if n > 1 {
+				nombre = fmt.Sprintf("%s_%d.html", p.archivo, n)
+			}
+
+			html, err := os.ReadFile(filepath.Join(dir, nombre))
+			if errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			filas, err := p.parse(bytes.NewReader(html), rc)
+			if err != nil {
+				t.Fatalf("%s: %v", nombre, err)
+			}
+			if p.seccion == "" {
+				break
+			}
+
+			pag, err := ParsePaginacion(bytes.NewReader(html))
+			if err != nil {
+				t.Fatalf("%s: %v", nombre, err)
+			}
+			paginacion.AgregarPagina(filas, pag.Total)
+		}
+
+		if paginacion.Paginas > 0 {
+			if rc.Paginacion == nil {
+				rc.Paginacion = make(map[string]models.PaginacionSeccion)
+			}
+			rc.Paginacion[p.seccion] = paginacion
+		}
+	}
+	return rc
+}
+
+func TestGolden(t *testing.T) {
+	casos, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, caso := range casos {
+		if !caso.IsDir() {
+			continue
+		}
+
+		t.Run(caso.Name(), func(t *testing.T) {
+			rc := parsearCaso(t, filepath.Join("testdata", caso.Name()))
+
+			obtenido, err := json.MarshalIndent(rc, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			obtenido = append(obtenido, '\n')
+
+			golden := filepath.Join("testdata", caso.Name()+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, obtenido, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			esperado, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (ejecute go test -update para generarlo)", err)
+			}
+			if !bytes.Equal(obtenido, esperado) {
+				t.Errorf("el resultado difiere de %s:\n%s", golden, diffLineas(string(esperado), string(obtenido)))
+			}
+		})
+	}
+}
+
+// diffLineas muestra las líneas que difieren entre el golden y el resultado
+func diffLineas(esperado, obtenido string) string {
+	a := strings.Split(esperado, "\n")
+	b := strings.Split(obtenido, "\n")
+
+	var sb strings.Builder
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var la, lb string
+		if i < len(a) {
+			la = a[i]
+		}
+		if i < len(b) {
+			lb = b[i]
+		}
+		if la != lb {
+			sb.WriteString("- " + la + "\n+ " + lb + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func TestParseFichaSinResultados(t *testing.T) {
+	html := `<html><body><div class="alert alert-danger">El número de RUC no es válido.</div></body></html>`
+
+	if _, err := ParseFicha(strings.NewReader(html)); !errors.Is(err, ErrFichaNoEncontrada) {
+		t.Fatalf("err = %v, se esperaba ErrFichaNoEncontrada", err)
+	}
+}
+
+func TestParseFechaInvalida(t *testing.T) {
+	// Una celda de fecha mal formada deja esa fecha desconocida sin perder las demás filas
+	html := `<table class="table"><tbody>
+		<tr><td>1,000.00</td><td>202301</td><td>2023-02-14</td><td>SUNAT</td></tr>
+		<tr><td>500.00</td><td>202302</td><td>14/03/2023</td><td>SUNAT</td></tr>
+	</tbody></table>`
+
+	deuda, err := ParseDeudaCoactiva(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deuda.Deudas) != 2 || deuda.TotalDeuda != models.Soles(150000) {
+		t.Fatalf("deudas = %+v", deuda.Deudas)
+	}
+	if deuda.Deudas[0].FechaInicioCobranza.Conocida() || deuda.Deudas[0].PeriodoTributario != models.NuevoPeriodo(2023, time.January) {
+		t.Errorf("fila con fecha inválida = %+v", deuda.Deudas[0])
+	}
+	if deuda.Deudas[1].FechaInicioCobranza != models.NuevaFecha(2023, time.March, 14) {
+		t.Errorf("fila válida = %+v", deuda.Deudas[1])
+	}
+}
+
+func TestParsePeriodoInvalido(t *testing.T) {
+	html := `<table class="table"><tbody>
+		<tr><td>TRIMESTRE 1</td><td>5</td><td>0</td><td>1</td></tr>
+		<tr><td>2024-02</td><td>6</td><td>0</td><td>1</td></tr>
+	</tbody></table>`
+
+	trabajadores, err := ParseCantidadTrabajadores(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trabajadores.DetallePorPeriodo) != 2 || trabajadores.DetallePorPeriodo[0].Periodo.Conocido() ||
+		trabajadores.DetallePorPeriodo[1].Periodo != models.NuevoPeriodo(2024, time.February) {
+		t.Errorf("detalle = %+v", trabajadores.DetallePorPeriodo)
+	}
+}
+
+func TestParseDeudaMontoInvalido(t *testing.T) {
+	html := `<table class="table"><tbody>
+		<tr><td>S/ 1,000.10</td><td>202301</td><td>14/02/2023</td><td>SUNAT</td></tr>
+		<tr><td>1.000,50</td><td>202302</td><td>14/03/2023</td><td>SUNAT</td></tr>
+		<tr><td>S/. 0.20</td><td>202303</td><td>14/04/2023</td><td>SUNAT</td></tr>
+	</tbody></table>`
+
+	deuda, err := ParseDeudaCoactiva(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deuda.TotalDeuda != models.Soles(100030) || deuda.CantidadDocumentos != 2 {
+		t.Errorf("total = %v en %d documentos; se esperaba 1000.30 PEN en 2", deuda.TotalDeuda, deuda.CantidadDocumentos)
+	}
+	if len(deuda.FilasInvalidas) != 1 || deuda.FilasInvalidas[0].Fila != 2 {
+		t.Fatalf("FilasInvalidas = %+v; se esperaba la fila 2", deuda.FilasInvalidas)
+	}
+}
+
+func TestParseRepresentantesNormalizados(t *testing.T) {
+	html := `<table class="table">
+		<thead><tr><th>Documento</th><th>Nro. Documento</th><th>Nombre</th><th>Cargo</th><th>Fecha Desde</th><th>Fecha Hasta</th></tr></thead>
+		<tbody>
+		<tr><td>C.E.</td><td>001234567</td><td>SMITH JONES, JOHN</td><td>GTE. GENERAL</td><td>01/02/2021</td><td>31/12/2022</td></tr>
+		<tr><td>LIBRETA ELECTORAL</td><td>41234567</td><td>FERNANDEZ QUISPE MARIA</td><td>SUB GERENTE</td><td>13/08/2020</td><td>-</td></tr>
+		<tr><td>LICENCIA</td><td>X1</td><td>PEREZ, ANA</td><td>ASESORA</td><td>13/08/2020</td><td>-</td></tr>
+	</tbody></table>`
+
+	reps, err := ParseRepresentantesLegales(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reps.Representantes) != 3 {
+		t.Fatalf("%d representantes; se esperaban 3", len(reps.Representantes))
+	}
+
+	ce := reps.Representantes[0]
+	if ce.TipoDocumento != identidad.CarneExtranjeria || ce.Apellidos != "SMITH JONES" || ce.Nombres != "JOHN" ||
+		ce.Cargo != models.CargoGerenteGeneral || ce.CargoSUNAT != "GTE. GENERAL" || ce.Vigente {
+		t.Errorf("representante con C.E. = %+v", ce)
+	}
+	dni := reps.Representantes[1]
+	if dni.TipoDocumento != identidad.DNI || dni.Apellidos != "" || dni.Cargo != models.CargoSubgerente || !dni.Vigente {
+		t.Errorf("representante con DNI = %+v", dni)
+	}
+	otro := reps.Representantes[2]
+	if otro.TipoDocumento != "" || otro.NumeroDocumento != "X1" || otro.Cargo != models.CargoOtro {
+		t.Errorf("representante sin documento reconocido = %+v", otro)
+	}
+}
+
+func TestParseFichaActividadInvalida(t *testing.T) {
+	html := `<div class="list-group-item"><div class="row">
+		<div class="col-sm-5"><h4 class="list-group-item-heading">Actividad(es) Económica(s):</h4></div>
+		<div class="col-sm-7"><table class="table"><tbody>
+			<tr><td>Principal - 6920 - ACTIVIDADES DE CONTABILIDAD</td></tr>
+			<tr><td>ACTIVIDADES DE ASESORAMIENTO EMPRESARIAL</td></tr>
+		</tbody></table></div>
+	</div></div>`
+
+	// La fila sin código no descarta la ficha: queda con su texto y sin CIIU
+	info, err := ParseFicha(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info.ActividadesEconomicas) != 2 {
+		t.Fatalf("%d actividades; se esperaban 2", len(info.ActividadesEconomicas))
+	}
+	if a := info.ActividadesEconomicas[1]; a.CodigoCIIU != "" || a.String() != "ACTIVIDADES DE ASESORAMIENTO EMPRESARIAL" {
+		t.Errorf("actividad sin código = %+v (%q)", a, a)
+	}
+}
+
+func TestParseFichaDocumentoInvalido(t *testing.T) {
+	casos := []struct {
+		valor  string
+		tipo   identidad.Tipo
+		numero string
+	}{
+		{"DNI 7636693 - TARRILLO MARRUFO, YAMELITH MARILYN", identidad.DNI, "7636693"},
+		{"LIBRETA MILITAR 7636693 - TARRILLO MARRUFO, YAMELITH MARILYN", "", "7636693"},
+	}
+	for _, c := range casos {
+		html := `<div class="list-group-item"><div class="row">
+		<div class="col-sm-5"><h4 class="list-group-item-heading">Tipo de Documento:</h4></div>
+		<div class="col-sm-7"><p class="list-group-item-text">` + c.valor + `</p></div>
+	</div></div>`
+
+		// El documento sin normalizar no descarta la ficha: se guarda como viene
+		info, err := ParseFicha(strings.NewReader(html))
+		if err != nil {
+			t.Fatalf("%q: %v", c.valor, err)
+		}
+		if info.TipoDocumento != c.tipo || info.NumeroDocumento != c.numero || info.NombreTitular != "TARRILLO MARRUFO, YAMELITH MARILYN" {
+			t.Errorf("%q: tipo %q, número %q, titular %q", c.valor, info.TipoDocumento, info.NumeroDocumento, info.NombreTitular)
+		}
+	}
+}
+
+func TestParseFechaInvalidaNoDescartaSeccion(t *testing.T) {
+	ficha := `<div class="list-group-item"><div class="row">
+		<div class="col-sm-5"><h4 class="list-group-item-heading">Estado del Contribuyente:</h4></div>
+		<div class="col-sm-7"><p class="list-group-item-text">ACTIVO</p></div>
+	</div></div>
+	<div class="list-group-item"><div class="row">
+		<div class="col-sm-3"><h4 class="list-group-item-heading">Fecha de Inscripción:</h4></div>
+		<div class="col-sm-3"><p class="list-group-item-text">2020-01-15</p></div>
+		<div class="col-sm-3"><h4 class="list-group-item-heading">Fecha de Inicio de Actividades:</h4></div>
+		<div class="col-sm-3"><p class="list-group-item-text">15/01/2020</p></div>
+	</div></div>`
+
+	info, err := ParseFicha(strings.NewReader(ficha))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Estado != "ACTIVO" || info.FechaInscripcion.Conocida() || info.FechaInicioActividades != models.NuevaFecha(2020, time.January, 15) {
+		t.Errorf("ficha = %+v", info)
+	}
+
+	representantes := `<table class="table">
+		<thead><tr><th>Documento</th><th>Nro. Documento</th><th>Nombre</th><th>Cargo</th><th>Fecha Desde</th></tr></thead>
+		<tbody>
+		<tr><td>DNI</td><td>41234567</td><td>PEREZ, JUAN</td><td>GERENTE GENERAL</td><td>31/02/2020</td></tr>
+		<tr><td>DNI</td><td>76366932</td><td>GOMEZ, ANA</td><td>GERENTE</td><td>13/08/2020</td></tr>
+	</tbody></table>`
+
+	reps, err := ParseRepresentantesLegales(strings.NewReader(representantes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reps.Representantes) != 2 || reps.Representantes[0].FechaDesde.Conocida() {
+		t.Errorf("representantes = %+v", reps.Representantes)
+	}
+}
+
+func TestParseFichaRUCNoExiste(t *testing.T) {
+	html := `<html><body><div class="alert alert-danger">El número de RUC 20999999990 consultado no existe.</div></body></html>`
+
+	if _, err := ParseFicha(strings.NewReader(html)); !errors.Is(err, ErrRUCNoExiste) {
+		t.Fatalf("err = %v, se esperaba ErrRUCNoExiste", err)
+	}
+}
+
+func TestParseCantidadConNE(t *testing.T) {
+	casos := map[string]int{
+		"NE":    0,
+		"ne":    0,
+		"-":     0,
+		"":      0,
+		"12":    12,
+		"1,024": 1024,
+		"abc":   0,
+	}
+
+	for texto, esperado := range casos {
+		if obtenido := parseCantidadConNE(texto); obtenido != esperado {
+			t.Errorf("parseCantidadConNE(%q) = %d, se esperaba %d", texto, obtenido, esperado)
+		}
+	}
+}
+
+func TestParsePaginacion(t *testing.T) {
+	casos := []struct {
+		archivo                  string
+		desde, hasta, total, sig int
+	}{
+		{"testdata/paginado/deuda.html", 1, 3, 5, 2},
+		{"testdata/paginado/deuda_2.html", 4, 5, 5, 0},
+		{"testdata/persona_juridica/deuda.html", 0, 0, 0, 0},
+	}
+
+	for _, c := range casos {
+		html, err := os.ReadFile(c.archivo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := ParsePaginacion(bytes.NewReader(html))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Desde != c.desde || p.Hasta != c.hasta || p.Total != c.total || p.Siguiente != c.sig {
+			t.Errorf("%s: %+v", c.archivo, p)
+		}
+	}
+}
+
+func TestParsePaginacionSinSiguiente(t *testing.T) {
+	html := `<table><tr><td>31 a 60 de 75</td><td>Páginas: <a href="javascript:paginacion(1)">1</a> 2 <a href="javascript:paginacion(3)">3</a></td></tr></table>`
+
+	p, err := ParsePaginacion(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Siguiente != 3 {
+		t.Errorf("Siguiente = %d, se esperaba 3", p.Siguiente)
+	}
+}
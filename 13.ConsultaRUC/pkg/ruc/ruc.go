@@ -0,0 +1,217 @@
+// Package ruc valida números de RUC con el dígito verificador de SUNAT
+// (módulo 11), clasifica el tipo de contribuyente por su prefijo y relaciona
+// los RUC de persona natural (prefijo 10) con el DNI de su titular.
+package ruc
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errores de validación; Parse los envuelve con el detalle del número recibido
+var (
+	ErrLongitud          = errors.New("el RUC debe tener 11 dígitos")
+	ErrPrefijo           = errors.New("prefijo de RUC desconocido")
+	ErrDigitoVerificador = errors.New("dígito verificador incorrecto")
+	ErrDNI               = errors.New("el DNI debe tener 8 dígitos")
+	ErrSinDNI            = errors.New("solo los RUC con prefijo 10 contienen un DNI")
+)
+
+// Tipo es el tipo de contribuyente según los dos primeros dígitos del RUC
+type Tipo int
+
+const (
+	TipoDesconocido Tipo = 0
+	// PersonaNatural: persona natural identificada con DNI; los dígitos 3 a 10 son el DNI
+	PersonaNatural Tipo = 10
+	// NoDomiciliado: persona natural no domiciliada o extranjera
+	NoDomiciliado Tipo = 15
+	// OtroDocumento: persona natural identificada con un documento distinto al DNI
+	OtroDocumento Tipo = 16
+	// SinDocumento: persona natural inscrita sin documento de identidad
+	SinDocumento Tipo = 17
+	// PersonaJuridica: empresas, asociaciones y demás entidades
+	PersonaJuridica Tipo = 20
+)
+
+var descripcionesTipo = map[Tipo]string{
+	PersonaNatural:  "Persona Natural",
+	NoDomiciliado:   "Persona Natural No Domiciliada",
+	OtroDocumento:   "Persona Natural con Otro Documento",
+	SinDocumento:    "Persona Natural sin Documento",
+	PersonaJuridica: "Persona Jurídica",
+}
+
+func (t Tipo) String() string {
+	if d, ok := descripcionesTipo[t]; ok {
+		return d
+	}
+	return "Desconocido"
+}
+
+// EsPersonaNatural indica si el tipo corresponde a una persona natural (10, 15, 16 y 17)
+func (t Tipo) EsPersonaNatural() bool {
+	return t == PersonaNatural || t == NoDomiciliado || t == OtroDocumento || t == SinDocumento
+}
+
+// EsPersonaJuridica indica si el tipo corresponde a una persona jurídica (20)
+func (t Tipo) EsPersonaJuridica() bool {
+	return t == PersonaJuridica
+}
+
+// pesos del módulo 11 de SUNAT para los 10 primeros dígitos
+var pesos = [10]int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+
+// DigitoVerificador calcula el dígito verificador de los 10 primeros dígitos de un RUC
+func DigitoVerificador(base string) (byte, error) {
+	if len(base) != 10 || !soloDigitos(base) {
+		return 0, fmt.Errorf("base %q: se esperaban 10 dígitos", base)
+	}
+	suma := 0
+	for i := 0; i < 10; i++ {
+		suma += int(base[i]-'0') * pesos[i]
+	}
+	digito := 11 - suma%11
+	switch digito {
+	case 10:
+		digito = 0
+	case 11:
+		digito = 1
+	}
+	return byte('0' + digito), nil
+}
+
+// RUC es un número de RUC validado. El valor cero ("") representa un RUC ausente.
+type RUC string
+
+// Parse valida s (se ignoran espacios alrededor) y retorna el RUC
+func Parse(s string) (RUC, error) {
+	s = strings.TrimSpace(s)
+	if len(s) != 11 || !soloDigitos(s) {
+		return "", fmt.Errorf("%q: %w", s, ErrLongitud)
+	}
+	if tipoPrefijo(s[:2]) == TipoDesconocido {
+		return "", fmt.Errorf("%q: %w", s, ErrPrefijo)
+	}
+	digito, _ := DigitoVerificador(s[:10])
+	if s[10] != digito {
+		return "", fmt.Errorf("%q: %w", s, ErrDigitoVerificador)
+	}
+	return RUC(s), nil
+}
+
+// Valido indica si s es un RUC válido
+func Valido(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// DesdeDNI arma el RUC de persona natural (prefijo 10) que corresponde a un DNI
+func DesdeDNI(dni string) (RUC, error) {
+	dni = strings.TrimSpace(dni)
+	if len(dni) != 8 || !soloDigitos(dni) {
+		return "", fmt.Errorf("%q: %w", dni, ErrDNI)
+	}
+	base := "10" + dni
+	digito, _ := DigitoVerificador(base)
+	return RUC(base + string(digito)), nil
+}
+
+func (r RUC) String() string {
+	return string(r)
+}
+
+// Tipo clasifica el RUC por su prefijo
+func (r RUC) Tipo() Tipo {
+	if len(r) < 2 {
+		return TipoDesconocido
+	}
+	return tipoPrefijo(string(r[:2]))
+}
+
+// DNI retorna el DNI contenido en un RUC de persona natural (prefijo 10)
+func (r RUC) DNI() (string, error) {
+	if r.Tipo() != PersonaNatural {
+		return "", fmt.Errorf("%q: %w", string(r), ErrSinDNI)
+	}
+	return string(r[2:10]), nil
+}
+
+// MarshalJSON serializa el RUC como cadena
+func (r RUC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(r))
+}
+
+// UnmarshalJSON acepta una cadena vacía o un RUC válido
+func (r *RUC) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("RUC: %w", err)
+	}
+	return r.asignar(s)
+}
+
+// Value guarda el RUC como texto; el RUC vacío se guarda como NULL
+func (r RUC) Value() (driver.Value, error) {
+	if r == "" {
+		return nil, nil
+	}
+	return string(r), nil
+}
+
+// Scan lee el RUC desde columnas de texto o numéricas (empresas_sunat.ruc es numérica)
+func (r *RUC) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*r = ""
+		return nil
+	case string:
+		return r.asignar(v)
+	case []byte:
+		return r.asignar(string(v))
+	case int64:
+		return r.asignar(fmt.Sprintf("%011d", v))
+	}
+	return fmt.Errorf("RUC: no se puede leer %T", src)
+}
+
+func (r *RUC) asignar(s string) error {
+	if strings.TrimSpace(s) == "" {
+		*r = ""
+		return nil
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
+
+func tipoPrefijo(prefijo string) Tipo {
+	switch prefijo {
+	case "10":
+		return PersonaNatural
+	case "15":
+		return NoDomiciliado
+	case "16":
+		return OtroDocumento
+	case "17":
+		return SinDocumento
+	case "20":
+		return PersonaJuridica
+	}
+	return TipoDesconocido
+}
+
+func soloDigitos(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package ruc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	validos := map[string]Tipo{
+		"20606316977": PersonaJuridica,
+		"20100070970": PersonaJuridica,
+		"20100047218": PersonaJuridica,
+		"10763669322": PersonaNatural,
+	}
+	for s, tipo := range validos {
+		r, err := Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%s): %v", s, err)
+			continue
+		}
+		if r.Tipo() != tipo {
+			t.Errorf("%s: tipo %v, se esperaba %v", s, r.Tipo(), tipo)
+		}
+	}
+
+	invalidos := map[string]error{
+		"2060631697":   ErrLongitud,
+		"2060631697A":  ErrLongitud,
+		"30606316977":  ErrPrefijo,
+		"20606316978":  ErrDigitoVerificador,
+		"10763669321":  ErrDigitoVerificador,
+		"206063169770": ErrLongitud,
+	}
+	for s, want := range invalidos {
+		if _, err := Parse(s); !errors.Is(err, want) {
+			t.Errorf("Parse(%s) = %v, se esperaba %v", s, err, want)
+		}
+	}
+}
+
+func TestDNI(t *testing.T) {
+	r, err := DesdeDNI("76366932")
+	if err != nil || r != "10763669322" {
+		t.Fatalf("DesdeDNI = %s, %v", r, err)
+	}
+	if dni, err := r.DNI(); err != nil || dni != "76366932" {
+		t.Errorf("DNI = %s, %v", dni, err)
+	}
+	if _, err := RUC("20606316977").DNI(); !errors.Is(err, ErrSinDNI) {
+		t.Errorf("un RUC 20 no debería tener DNI: %v", err)
+	}
+	if _, err := DesdeDNI("1234567"); !errors.Is(err, ErrDNI) {
+		t.Errorf("DNI de 7 dígitos aceptado: %v", err)
+	}
+}
+
+func TestJSONySQL(t *testing.T) {
+	var v struct {
+		RUC RUC `json:"ruc"`
+	}
+	if err := json.Unmarshal([]byte(`{"ruc":"20606316977"}`), &v); err != nil || v.RUC != "20606316977" {
+		t.Fatalf("Unmarshal = %s, %v", v.RUC, err)
+	}
+	if err := json.Unmarshal([]byte(`{"ruc":"20606316978"}`), &v); !errors.Is(err, ErrDigitoVerificador) {
+		t.Errorf("Unmarshal aceptó un RUC inválido: %v", err)
+	}
+	if b, _ := json.Marshal(RUC("20606316977")); string(b) != `"20606316977"` {
+		t.Errorf("Marshal = %s", b)
+	}
+
+	var r RUC
+	if err := r.Scan(int64(20100070970)); err != nil || r != "20100070970" {
+		t.Errorf("Scan(int64) = %s, %v", r, err)
+	}
+	if err := r.Scan([]byte("10763669322")); err != nil || r != "10763669322" {
+		t.Errorf("Scan([]byte) = %s, %v", r, err)
+	}
+	if v, _ := RUC("").Value(); v != nil {
+		t.Errorf("Value de RUC vacío = %v, se esperaba NULL", v)
+	}
+}
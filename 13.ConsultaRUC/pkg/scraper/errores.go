@@ -0,0 +1,130 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+)
+
+// EstadoConsulta es el estado con que main.sh registra la consulta en log_consultas.estado
+type EstadoConsulta string
+
+const (
+	EstadoExitoso       EstadoConsulta = "exitoso"
+	EstadoRevision      EstadoConsulta = "revision"
+	EstadoFallido       EstadoConsulta = "fallido"
+	EstadoErrorTerminal EstadoConsulta = "error_terminal"
+	// EstadoInexistente: SUNAT no tiene registrado el RUC; se guarda en ruc_inexistentes
+	EstadoInexistente EstadoConsulta = "inexistente"
+	// EstadoInvalido: el número no es un RUC válido y no se consulta
+	EstadoInvalido EstadoConsulta = "invalido"
+)
+
+// ErrorConsulta es una categoría de falla del scraping. Se compara con
+// errors.Is contra los valores Err* de este paquete.
+type ErrorConsulta struct {
+	motivo string
+	estado EstadoConsulta
+}
+
+func (e *ErrorConsulta) Error() string {
+	return e.motivo
+}
+
+// Estado retorna el estado de log_consultas que corresponde a la categoría
+func (e *ErrorConsulta) Estado() EstadoConsulta {
+	return e.estado
+}
+
+// Categorías de falla. Los errores de Consultar las envuelven dentro de un
+// *ScrapeError, por lo que errors.Is(err, ErrBotonNoDisponible) funciona
+// directamente sobre el error devuelto.
+var (
+	// ErrBotonNoDisponible: el botón de la sección no existe o no está visible
+	ErrBotonNoDisponible = &ErrorConsulta{"botón no disponible", EstadoFallido}
+	// ErrAplicacionSUNAT: SUNAT respondió con su página de error de aplicación
+	ErrAplicacionSUNAT = &ErrorConsulta{"SUNAT retornó una página de error", EstadoFallido}
+	// ErrURLRechazada: el firewall de SUNAT respondió "The requested URL was rejected"
+	ErrURLRechazada = &ErrorConsulta{"SUNAT rechazó la URL solicitada", EstadoFallido}
+	// ErrPaginaIncorrecta: la página abierta no corresponde a la sección (VerificarPaginaCorrecta)
+	ErrPaginaIncorrecta = &ErrorConsulta{"página incorrecta o no cargada", EstadoFallido}
+	// ErrRUCNoEncontrado: SUNAT indica que el RUC consultado no existe
+	ErrRUCNoEncontrado = &ErrorConsulta{"el RUC no existe en SUNAT", EstadoInexistente}
+	// ErrRUCInvalido: el número no pasa la validación de pkg/ruc; no se abre el navegador
+	ErrRUCInvalido = &ErrorConsulta{"RUC inválido", EstadoInvalido}
+	// ErrTimeoutNavegacion: venció el tiempo de espera de una página o de la consulta
+	ErrTimeoutNavegacion = &ErrorConsulta{"tiempo de espera agotado", EstadoFallido}
+	// ErrNavegadorCaido: se perdió la conexión con el navegador
+	ErrNavegadorCaido = &ErrorConsulta{"el navegador dejó de responder", EstadoErrorTerminal}
+	// ErrParseo: la página cargó pero su contenido no se pudo interpretar
+	ErrParseo = &ErrorConsulta{"error al interpretar la página", EstadoRevision}
+)
+
+// ScrapeError es el error que devuelve Consultar cuando una sección agota sus
+// reintentos o la consulta se cancela
+type ScrapeError struct {
+	Seccion string // nombre de la sección, "Información General" para la ficha
+	Intento int    // último intento realizado; 0 si no se llegó a consultar
+	Err     error
+}
+
+func (e *ScrapeError) Error() string {
+	return fmt.Sprintf("%s (intento %d): %v", e.Seccion, e.Intento, e.Err)
+}
+
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
+
+// Estado retorna el estado de log_consultas que corresponde al error
+func (e *ScrapeError) Estado() EstadoConsulta {
+	return Estado(e)
+}
+
+// Estado clasifica el resultado de Consultar: nil es exitoso, una categoría
+// Err* aporta su propio estado, la cancelación (Ctrl+C) es error_terminal y
+// cualquier otro error es fallido.
+func Estado(err error) EstadoConsulta {
+	var categoria *ErrorConsulta
+	switch {
+	case err == nil:
+		return EstadoExitoso
+	case errors.As(err, &categoria):
+		return categoria.estado
+	case errors.Is(err, context.Canceled):
+		return EstadoErrorTerminal
+	}
+	return EstadoFallido
+}
+
+// clasificar envuelve en ErrNavegadorCaido o ErrTimeoutNavegacion los errores
+// de rod que aún no tienen categoría
+func clasificar(err error) error {
+	var categoria *ErrorConsulta
+	switch {
+	case err == nil || errors.As(err, &categoria):
+		return err
+	case navegadorCaido(err):
+		return fmt.Errorf("%w: %w", ErrNavegadorCaido, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeoutNavegacion, err)
+	}
+	return err
+}
+
+// navegadorCaido detecta el cierre de la conexión CDP con el navegador
+func navegadorCaido(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// reintentable indica si vale la pena repetir la sección tras err
+func reintentable(err error) bool {
+	return !errors.Is(err, ErrNavegadorCaido) && !errors.Is(err, ErrRUCNoEncontrado) && !errors.Is(err, ErrRUCInvalido)
+}
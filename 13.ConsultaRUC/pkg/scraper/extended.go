@@ -1,34 +1,31 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"os"
 	"strings"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
-	"github.com/consulta-ruc-scraper/pkg/database"
 	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/ruc"
+	"github.com/consulta-ruc-scraper/pkg/secciones"
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 )
 
 // ImprimirBotonesDisponibles encuentra e imprime todos los botones disponibles en la página
-func (s *ScraperExtendido) ImprimirBotonesDisponibles(page *rod.Page) {
+func (s *SUNATScraper) ImprimirBotonesDisponibles(ctx context.Context, page *rod.Page) {
+	page = page.Context(ctx)
+
 	fmt.Println("\n🔍 === ANÁLISIS DE BOTONES DISPONIBLES ===")
 
 	// Mapa de botones conocidos con sus selectores y nombres amigables
-	botonesConocidos := map[string]string{
-		"btnInfHis":     "Información Histórica",
-		"btnInfDeuCoa":  "Deuda Coactiva",
-		"btnInfOmiTri":  "Omisiones Tributarias",
-		"btnInfNumTra":  "Cantidad de Trabajadores",
-		"btnInfActPro":  "Actas Probatorias",
-		"btnInfActCPF":  "Facturas Físicas",
-		"btnInfRepLeg":  "Representantes Legales",
-		"btnInfLocAnex": "Establecimientos Anexos",
-		"btnInfReaPer":  "Reactiva Perú",
-		"btnInfCovid":   "Programa COVID-19",
+	botonesConocidos := make(map[string]string)
+	for _, sec := range secciones.Todas() {
+		botonesConocidos[sec.Boton] = sec.Nombre
 	}
 
 	// Contador de botones encontrados
@@ -192,12 +189,16 @@ func (s *ScraperExtendido) ImprimirBotonesDisponibles(page *rod.Page) {
 	fmt.Printf("\n📊 === RESUMEN ===\n")
 	fmt.Printf("🔢 Total de botones conocidos encontrados: %d/%d\n", botonesEncontrados, len(botonesConocidos))
 	fmt.Printf("✅ Botones activos (disponibles para clic): %d\n", botonesActivos)
-	fmt.Printf("📍 Página actual: %s\n", page.MustInfo().URL)
+	if info, err := page.Info(); err == nil {
+		fmt.Printf("📍 Página actual: %s\n", info.URL)
+	}
 	fmt.Printf("=================================================\n\n")
 }
 
 // VerificarDisponibilidadBoton verifica si un botón específico está disponible para hacer clic
-func (s *ScraperExtendido) VerificarDisponibilidadBoton(page *rod.Page, claseBoton string) bool {
+func (s *SUNATScraper) VerificarDisponibilidadBoton(ctx context.Context, page *rod.Page, claseBoton string) bool {
+	page = page.Context(ctx)
+
 	xpath := fmt.Sprintf("//button[contains(@class, '%s')]", claseBoton)
 
 	boton, err := page.Timeout(2 * time.Second).ElementX(xpath)
@@ -225,106 +226,154 @@ func (s *ScraperExtendido) VerificarDisponibilidadBoton(page *rod.Page, claseBot
 	return true
 }
 
-// ObtenerBotonesDisponibles devuelve un mapa con los botones disponibles
-func (s *ScraperExtendido) ObtenerBotonesDisponibles(page *rod.Page) map[string]bool {
-	botonesDisponibles := make(map[string]bool)
+// ObtenerBotonesDisponibles devuelve un mapa con los botones disponibles de las secciones indicadas
+func (s *SUNATScraper) ObtenerBotonesDisponibles(ctx context.Context, page *rod.Page, lista []secciones.Seccion) map[string]bool {
+	page = page.Context(ctx)
 
-	botones := map[string]string{
-		"btnInfHis":     "Información Histórica",
-		"btnInfDeuCoa":  "Deuda Coactiva",
-		"btnInfOmiTri":  "Omisiones Tributarias",
-		"btnInfNumTra":  "Cantidad de Trabajadores",
-		"btnInfActPro":  "Actas Probatorias",
-		"btnInfActCPF":  "Facturas Físicas",
-		"btnInfRepLeg":  "Representantes Legales",
-		"btnInfLocAnex": "Establecimientos Anexos",
-		"btnInfReaPer":  "Reactiva Perú",
-		"btnInfCovid":   "Programa COVID-19",
-	}
+	botonesDisponibles := make(map[string]bool)
 
-	for clase := range botones {
-		botonesDisponibles[clase] = s.VerificarDisponibilidadBoton(page, clase)
+	for _, sec := range lista {
+		botonesDisponibles[sec.Boton] = s.VerificarDisponibilidadBoton(ctx, page, sec.Boton)
 	}
 
 	return botonesDisponibles
 }
 
-// retryScrapeWithPartialSave - Función mejorada que guarda datos parciales antes de terminar
-func (s *ScraperExtendido) retryScrapeWithPartialSave(maxRetries int, name string, scrapeFunc func() error, rucCompleto *models.RUCCompleto, dbService *database.DatabaseService, ruc string, page *rod.Page) {
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if err := scrapeFunc(); err == nil {
+// consultarConReintentos ejecuta scrapeFunc hasta config.MaxReintentos veces. Cada
+// intento recibe un contexto limitado por config.Timeout; si ctx se cancela no se
+// reintenta, ni tampoco si el navegador se cayó. scrapeFunc recibe el número de
+// intento, desde 1. Al agotar los intentos retorna un *ScrapeError con el error
+// clasificado.
+func (s *SUNATScraper) consultarConReintentos(ctx context.Context, name string, page *rod.Page, scrapeFunc func(ctx context.Context, intento int) error) error {
+	maxRetries := s.config.MaxReintentos
+	var err error
+	attempt := 1
+	for ; attempt <= maxRetries; attempt++ {
+		intentoCtx, cancel := ctx, context.CancelFunc(func() {})
+		if s.config.Timeout > 0 {
+			intentoCtx, cancel = context.WithTimeout(ctx, s.config.Timeout)
+		}
+		err = clasificar(scrapeFunc(intentoCtx, attempt))
+		cancel()
+		if err == nil {
 			fmt.Println("✓")
-			return
-		} else {
-			fmt.Printf("✗ intento %d/%d (%v)\n", attempt, maxRetries, err)
-			// Delay inteligente entre reintentos (aumenta con cada intento)
-			retryDelay := s.humanSim.generateLogNormalDelay(2000*float64(attempt), 800)
-			time.Sleep(retryDelay)
+			return nil
 		}
-	}
 
-	fmt.Printf("❌ Falló %s después de %d intentos.\n", name, maxRetries)
+		fmt.Printf("✗ intento %d/%d (%v)\n", attempt, maxRetries, err)
+		if ctx.Err() != nil {
+			return &ScrapeError{Seccion: name, Intento: attempt, Err: clasificar(ctx.Err())}
+		}
+		if attempt == maxRetries || !reintentable(err) {
+			break
+		}
 
-	// GUARDAR DATOS PARCIALES ANTES DE TERMINAR
-	if rucCompleto != nil && dbService != nil {
-		fmt.Printf("💾 Guardando datos parciales obtenidos hasta el momento para RUC %s...\n", ruc)
-		if err := dbService.InsertRUCCompleto(rucCompleto); err != nil {
-			fmt.Printf("❌ Error guardando datos parciales: %v\n", err)
-		} else {
-			fmt.Printf("✅ Datos parciales guardados exitosamente para RUC %s\n", ruc)
+		// Delay inteligente entre reintentos (aumenta con cada intento)
+		base := float64(s.config.DelayReintentos.Milliseconds())
+		retryDelay := s.humanSim.generateLogNormalDelay(base*float64(attempt), 800)
+		if errEspera := esperar(ctx, retryDelay); errEspera != nil {
+			return &ScrapeError{Seccion: name, Intento: attempt, Err: errEspera}
 		}
 	}
 
-	fmt.Printf("🛑 Terminando programa debido a falla en: %s\n", name)
-	fmt.Printf("📄 Último HTML obtenido: %s\n", page.MustHTML())
-	os.Exit(1)
+	fmt.Printf("❌ Falló %s después de %d intentos.\n", name, attempt)
+	if !errors.Is(err, ErrNavegadorCaido) {
+		if html, errHTML := page.HTML(); errHTML == nil {
+			fmt.Printf("📄 Último HTML obtenido: %s\n", html)
+		}
+	}
+	return &ScrapeError{Seccion: name, Intento: attempt, Err: err}
 }
 
-// ScrapeRUCCompleto obtiene toda la información disponible de un RUC usando detección de botones
-func (s *ScraperExtendido) ScrapeRUCCompleto(ruc string, dbService *database.DatabaseService) (*models.RUCCompleto, error) {
-	page := s.browser.MustPage(s.baseURL)
-	defer func() {
-		page.MustClose()
-		s.browser.MustClose() // Cierra el navegador entero
-	}()
+// abrirFicha carga el formulario de consulta en page y busca el RUC, dejando
+// page en su ficha
+func (s *SUNATScraper) abrirFicha(ctx context.Context, page *rod.Page, numero string) error {
+	page = page.Context(ctx)
+	if err := page.Navigate(s.config.BaseURL); err != nil {
+		return fmt.Errorf("error abriendo %s: %w", s.config.BaseURL, err)
+	}
 
 	// Carga humana de página
-	err := s.HumanPageLoad(page)
+	err := s.HumanPageLoad(ctx, page)
 	if err != nil {
-		return nil, fmt.Errorf("error en carga humana de página: %w", err)
+		return fmt.Errorf("error en carga humana de página: %w", err)
 	}
 
 	// Ingresar RUC
-	rucInput := page.MustElement("#txtRuc")
-	rucInput.MustWaitVisible()
-	err = s.HumanInput(rucInput, ruc)
+	rucInput, err := page.Element("#txtRuc")
+	if err == nil {
+		err = rucInput.WaitVisible()
+	}
+	if err != nil {
+		return fmt.Errorf("no se encontró el campo de RUC: %w", err)
+	}
+	err = s.HumanInput(ctx, rucInput, numero)
+	if err != nil {
+		return fmt.Errorf("error ingresando RUC: %w", err)
+	}
+	searchBtn, err := page.Element("#btnAceptar")
+	if err == nil {
+		err = searchBtn.WaitVisible()
+	}
 	if err != nil {
-		return nil, fmt.Errorf("error ingresando RUC: %w", err)
+		return fmt.Errorf("no se encontró el botón buscar: %w", err)
 	}
-	searchBtn := page.MustElement("#btnAceptar")
-	searchBtn.MustWaitVisible()
 
-	err = s.HumanClick(searchBtn, page)
+	err = s.HumanClick(ctx, searchBtn, page)
 	if err != nil {
-		return nil, fmt.Errorf("error haciendo clic en buscar: %w", err)
+		return fmt.Errorf("error haciendo clic en buscar: %w", err)
 	}
 
 	// Esperar resultados con comportamiento humano
-	err = s.HumanPageLoad(page)
+	err = s.HumanPageLoad(ctx, page)
+	if err != nil {
+		return fmt.Errorf("error cargando resultados: %w", err)
+	}
+	return nil
+}
+
+// Consultar obtiene toda la información disponible de un RUC usando detección de botones
+func (s *SUNATScraper) Consultar(ctx context.Context, numero string, opts OpcionesConsulta) (*models.RUCCompleto, error) {
+	// Se valida el número antes de abrir el navegador
+	rucValido, err := ruc.Parse(numero)
+	if err != nil {
+		return nil, &ScrapeError{Seccion: seccionFicha, Intento: 0, Err: fmt.Errorf("%w: %w", ErrRUCInvalido, err)}
+	}
+
+	seleccion, err := secciones.Seleccionar(opts.Secciones)
+	if err != nil {
+		return nil, err
+	}
+	solicitadas := make(map[string]bool)
+	for _, sec := range seleccion {
+		solicitadas[sec.Clave] = true
+	}
+
+	// Las fallas antes de tener la ficha se reportan como falla de Información General
+	fallaFicha := func(err error) error {
+		return &ScrapeError{Seccion: seccionFicha, Intento: 1, Err: clasificar(err)}
+	}
+
+	page, err := s.browser.Context(ctx).Page(proto.TargetCreateTarget{})
 	if err != nil {
-		return nil, fmt.Errorf("error cargando resultados: %w", err)
+		return nil, fallaFicha(fmt.Errorf("error abriendo pestaña: %w", err))
+	}
+	defer page.Close()
+
+	if err := s.abrirFicha(ctx, page, numero); err != nil {
+		return nil, fallaFicha(err)
 	}
 
 	// Consulta información general (siempre disponible)
 	fmt.Println(" 📋 Consultando información principal...")
 	fmt.Print(" - Información General: ")
-	infob, err := s.ScrapeRUC(ruc, page)
+	infob, err := s.ScrapeRUC(ctx, numero, page)
 	if err == nil {
 		fmt.Println("✓")
 	} else {
 		fmt.Printf("✗ (%v)\n", err)
 		// Si falla la información básica, no podemos continuar
-		return nil, fmt.Errorf("error obteniendo información básica: %w", err)
+		return nil, fallaFicha(err)
 	}
 
 	// Crear estructura completa
@@ -333,179 +382,73 @@ func (s *ScraperExtendido) ScrapeRUCCompleto(ruc string, dbService *database.Dat
 		InformacionBasica:   *infob,
 		VersionAPI:          "1.0.0",
 		DeteccionPaginacion: make(map[string]bool),
+		Paginacion:          make(map[string]models.PaginacionSeccion),
+		EstadoSecciones:     make(map[string]models.EstadoSeccion),
 	}
 
 	// Determinar tipo de RUC
-	esPersonaJuridica := strings.HasPrefix(ruc, "20")
-	fmt.Printf(" ℹ️ RUC %s es: %s (Fatiga: %.2f)\n", ruc,
-		map[bool]string{true: "Persona Jurídica", false: "Persona Natural"}[esPersonaJuridica],
-		s.humanSim.fatigueLevel)
+	tipo := rucValido.Tipo()
+	fmt.Printf(" ℹ️ RUC %s es: %s (Fatiga: %.2f)\n", numero, tipo, s.humanSim.fatigueLevel)
 
 	// Analizar botones disponibles
-	s.ImprimirBotonesDisponibles(page)
-	botonesDisponibles := s.ObtenerBotonesDisponibles(page)
+	s.ImprimirBotonesDisponibles(ctx, page)
+	botonesDisponibles := s.ObtenerBotonesDisponibles(ctx, page, seleccion)
 
 	// ========================================
 	// CONSULTAS BASADAS EN DISPONIBILIDAD DE BOTONES
 	// ========================================
 	fmt.Println(" 📋 Iniciando scraping basado en botones disponibles...")
 
-	// 1. Información Histórica
-	if botonesDisponibles["btnInfHis"] {
-		fmt.Print(" - Información Histórica: ")
-		s.retryScrapeWithPartialSave(3, "Información Histórica", func() error {
-			infoHist, tienePaginacion, err := s.ScrapeInformacionHistorica(ruc, page)
-			if err == nil {
-				rucCompleto.InformacionHistorica = infoHist
-				rucCompleto.DeteccionPaginacion["Información Histórica"] = tienePaginacion
-			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Información Histórica: ❌ Botón no disponible")
-	}
-
-	// 2. Deuda Coactiva
-	if botonesDisponibles["btnInfDeuCoa"] {
-		fmt.Print(" - Deuda Coactiva: ")
-		s.retryScrapeWithPartialSave(3, "Deuda Coactiva", func() error {
-			deuda, tienePaginacion, err := s.ScrapeDeudaCoactiva(ruc, page)
-			if err == nil {
-				rucCompleto.DeudaCoactiva = deuda
-				rucCompleto.DeteccionPaginacion["Deuda Coactiva"] = tienePaginacion
-			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Deuda Coactiva: ❌ Botón no disponible")
-	}
-
-	// 3. Omisiones Tributarias
-	if botonesDisponibles["btnInfOmiTri"] {
-		fmt.Print(" - Omisiones Tributarias: ")
-		s.retryScrapeWithPartialSave(3, "Omisiones Tributarias", func() error {
-			omis, tienePaginacion, err := s.ScrapeOmisionesTributarias(ruc, page)
-			if err == nil {
-				rucCompleto.OmisionesTributarias = omis
-				rucCompleto.DeteccionPaginacion["Omisiones Tributarias"] = tienePaginacion
-			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Omisiones Tributarias: ❌ Botón no disponible")
-	}
-
-	// 4. Cantidad de Trabajadores
-	if botonesDisponibles["btnInfNumTra"] {
-		fmt.Print(" - Cantidad de Trabajadores: ")
-		s.retryScrapeWithPartialSave(3, "Cantidad de Trabajadores", func() error {
-			trab, tienePaginacion, err := s.ScrapeCantidadTrabajadores(ruc, page)
-			if err == nil {
-				rucCompleto.CantidadTrabajadores = trab
-				rucCompleto.DeteccionPaginacion["Cantidad de Trabajadores"] = tienePaginacion
-			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Cantidad de Trabajadores: ❌ Botón no disponible")
-	}
-
-	// 5. Actas Probatorias
-	if botonesDisponibles["btnInfActPro"] {
-		fmt.Print(" - Actas Probatorias: ")
-		s.retryScrapeWithPartialSave(3, "Actas Probatorias", func() error {
-			actas, tienePaginacion, err := s.ScrapeActasProbatorias(ruc, page)
-			if err == nil {
-				rucCompleto.ActasProbatorias = actas
-				rucCompleto.DeteccionPaginacion["Actas Probatorias"] = tienePaginacion
-			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Actas Probatorias: ❌ Botón no disponible")
-	}
-
-	// 6. Facturas Físicas
-	if botonesDisponibles["btnInfActCPF"] {
-		fmt.Print(" - Facturas Físicas: ")
-		s.retryScrapeWithPartialSave(3, "Facturas Físicas", func() error {
-			fact, tienePaginacion, err := s.ScrapeFacturasFisicas(ruc, page)
-			if err == nil {
-				rucCompleto.FacturasFisicas = fact
-				rucCompleto.DeteccionPaginacion["Facturas Físicas"] = tienePaginacion
-			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Facturas Físicas: ❌ Botón no disponible")
-	}
+	for _, sec := range secciones.Todas() {
+		if !solicitadas[sec.Clave] {
+			fmt.Printf(" - %s: ⏭️  No solicitada\n", sec.Nombre)
+			rucCompleto.EstadoSecciones[sec.Clave] = models.SeccionNoSolicitada
+			continue
+		}
+		if !botonesDisponibles[sec.Boton] {
+			fmt.Printf(" - %s: ❌ Botón no disponible\n", sec.Nombre)
+			rucCompleto.EstadoSecciones[sec.Clave] = models.SeccionNoDisponible
+			continue
+		}
 
-	// 7. Representantes Legales
-	if botonesDisponibles["btnInfRepLeg"] {
-		fmt.Print(" - Representantes Legales: ")
-		s.retryScrapeWithPartialSave(3, "Representantes Legales", func() error {
-			reps, tienePaginacion, err := s.ScrapeRepresentantesLegales(ruc, page)
-			if err == nil {
-				rucCompleto.RepresentantesLegales = reps
-				rucCompleto.DeteccionPaginacion["Representantes Legales"] = tienePaginacion
+		fmt.Printf(" - %s: ", sec.Nombre)
+		err := s.consultarConReintentos(ctx, sec.Nombre, page, func(ctx context.Context, intento int) error {
+			// Un intento fallido puede dejar page fuera de la ficha: se vuelve a buscar el RUC
+			if intento > 1 {
+				if err := s.abrirFicha(ctx, page, numero); err != nil {
+					return err
+				}
 			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Representantes Legales: ❌ Botón no disponible")
-	}
-
-	// 8. Establecimientos Anexos
-	if botonesDisponibles["btnInfLocAnex"] {
-		fmt.Print(" - Establecimientos Anexos: ")
-		s.retryScrapeWithPartialSave(3, "Establecimientos Anexos", func() error {
-			estab, tienePaginacion, err := s.ScrapeEstablecimientosAnexos(ruc, page)
-			if err == nil {
-				rucCompleto.EstablecimientosAnexos = estab
-				rucCompleto.DeteccionPaginacion["Establecimientos Anexos"] = tienePaginacion
+			res, err := s.ScrapeSeccion(ctx, sec, page)
+			if err != nil {
+				return err
 			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Establecimientos Anexos: ❌ Botón no disponible")
-	}
-
-	// 9. Reactiva Perú
-	if botonesDisponibles["btnInfReaPer"] {
-		fmt.Print(" - Reactiva Perú: ")
-		s.retryScrapeWithPartialSave(3, "Reactiva Perú", func() error {
-			react, err := s.ScrapeReactivaPeru(ruc, page)
-			if err == nil {
-				rucCompleto.ReactivaPeru = react
+			sec.Asignar(rucCompleto, res.Valor)
+			if sec.Paginacion != secciones.SinPaginacion {
+				rucCompleto.DeteccionPaginacion[sec.Nombre] = res.TienePaginacion
 			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Reactiva Perú: ❌ Botón no disponible")
-	}
-
-	// 10. Programa COVID-19
-	if botonesDisponibles["btnInfCovid"] {
-		fmt.Print(" - Programa COVID-19: ")
-		s.retryScrapeWithPartialSave(3, "Programa COVID-19", func() error {
-			covid, err := s.ScrapeProgramaCovid19(ruc, page)
-			if err == nil {
-				rucCompleto.ProgramaCovid19 = covid
+			if sec.Paginacion == secciones.RecorrerPaginacion {
+				rucCompleto.Paginacion[sec.Nombre] = res.Paginacion
 			}
-			return err
-		}, rucCompleto, dbService, ruc, page)
-	} else {
-		fmt.Println(" - Programa COVID-19: ❌ Botón no disponible")
+			rucCompleto.EstadoSecciones[sec.Clave] = models.SeccionConsultada
+			return nil
+		})
+		if err != nil {
+			// Se devuelve lo obtenido hasta aquí para que el llamador guarde los datos parciales
+			return rucCompleto, err
+		}
 	}
 
-	fmt.Printf("\n✅ Scraping completado para RUC %s\n", ruc)
-	fmt.Printf("📊 Tipo de RUC: %s\n", map[bool]string{true: "Persona Jurídica (20)", false: "Persona Natural (10)"}[esPersonaJuridica])
+	fmt.Printf("\n✅ Scraping completado para RUC %s\n", numero)
+	fmt.Printf("📊 Tipo de RUC: %s (%d)\n", tipo, int(tipo))
 
 	return rucCompleto, nil
 }
 
 // VERSIÓN OPTIMIZADA - Reduce tiempo de ejecución significativamente
-func (s *ScraperExtendido) VerificarPaginaCorrecta(page *rod.Page, tipoSeccion string) bool {
+func (s *SUNATScraper) VerificarPaginaCorrecta(ctx context.Context, page *rod.Page, tipoSeccion string) bool {
+	page = page.Context(ctx)
+
 	// 1. ELIMINAR SLEEP INNECESARIO - ya tienes WaitLoad()
 	// time.Sleep(1 * time.Second) // ❌ ELIMINADO
 
@@ -594,59 +537,19 @@ func (s *ScraperExtendido) VerificarPaginaCorrecta(page *rod.Page, tipoSeccion s
 	return false
 }
 
-// MOVER MAPA A MÉTODO SEPARADO O VARIABLE GLOBAL
-func (s *ScraperExtendido) obtenerPatronesSeccion(tipoSeccion string) []string {
-	// Usar map estático - considerar hacerlo variable global para mejor performance
-	patronesPorSeccion := map[string][]string{
-		"informacion_historica": {
-			"información histórica de",
-			"informacion historica de",
-		},
-		"deuda_coactiva": {
-			"deuda coactiva remitida a centrales de riesgo de",
-			"deuda coactiva",
-		},
-		"omisiones_tributarias": {
-			"omisiones tributarias de",
-			"omisiones tributarias",
-		},
-		"cantidad_trabajadores": {
-			"cantidad de trabajadores",
-			"número de trabajadores",
-			"numero de trabajadores",
-		},
-		"actas_probatorias": {
-			"actas probatorias de",
-			"actas probatorias",
-		},
-		"facturas_fisicas": {
-			"facturas físicas de",
-			"facturas fisicas de",
-		},
-		"reactiva_peru": {
-			"reactiva perú de",
-			"reactiva peru de",
-			"programa reactiva",
-		},
-		"garantias_covid19": {
-			"programa de garantías covid",
-			"programa de garantias covid",
-			"garantías covid-19",
-			"garantias covid-19",
-		},
-		"representantes_legales": {
-			"representantes legales de",
-		},
-		"establecimientos_anexos": {
-			"establecimientos anexos de",
-		},
+// obtenerPatronesSeccion retorna los patrones declarados en pkg/secciones
+func (s *SUNATScraper) obtenerPatronesSeccion(tipoSeccion string) []string {
+	sec, ok := secciones.Buscar(tipoSeccion)
+	if !ok {
+		return nil
 	}
-
-	return patronesPorSeccion[tipoSeccion]
+	return sec.Patrones
 }
 
 // ALTERNATIVA MÁS RÁPIDA - Versión ultra optimizada
-func (s *ScraperExtendido) VerificarPaginaCorrectaRapida(page *rod.Page, tipoSeccion string) bool {
+func (s *SUNATScraper) VerificarPaginaCorrectaRapida(ctx context.Context, page *rod.Page, tipoSeccion string) bool {
+	page = page.Context(ctx)
+
 	// Solo esperar carga, sin sleep
 	page.WaitLoad()
 
@@ -687,7 +590,7 @@ func (s *ScraperExtendido) VerificarPaginaCorrectaRapida(page *rod.Page, tipoSec
 	return false
 }
 
-func (s *ScraperExtendido) coincidePatron(texto, tipoSeccion string) bool {
+func (s *SUNATScraper) coincidePatron(texto, tipoSeccion string) bool {
 	patrones := s.obtenerPatronesSeccion(strings.ToLower(tipoSeccion))
 	for _, patron := range patrones {
 		if strings.Contains(texto, patron) {
@@ -698,13 +601,18 @@ func (s *ScraperExtendido) coincidePatron(texto, tipoSeccion string) bool {
 }
 
 // VerificarErroresPagina detecta mensajes de error y maneja botones de retroceso
-func (s *ScraperExtendido) VerificarErroresPagina(page *rod.Page) error {
+func (s *SUNATScraper) VerificarErroresPagina(ctx context.Context, page *rod.Page) error {
+	page = page.Context(ctx)
+
 	// Buscar mensajes de error comunes
-	mensajesError := []string{
-		"La aplicación ha retornado el siguiente problema",
-		"The requested URL was rejected. Please consult with your administrator",
-		"error en la aplicación",
-		"página no encontrada",
+	mensajesError := []struct {
+		texto     string
+		categoria *ErrorConsulta
+	}{
+		{"La aplicación ha retornado el siguiente problema", ErrAplicacionSUNAT},
+		{"The requested URL was rejected. Please consult with your administrator", ErrURLRechazada},
+		{"error en la aplicación", ErrAplicacionSUNAT},
+		{"página no encontrada", ErrAplicacionSUNAT},
 	}
 
 	// Verificar texto de la página
@@ -714,8 +622,8 @@ func (s *ScraperExtendido) VerificarErroresPagina(page *rod.Page) error {
 		if err == nil {
 			textoLower := strings.ToLower(texto)
 			for _, mensajeError := range mensajesError {
-				if strings.Contains(textoLower, strings.ToLower(mensajeError)) {
-					log.Printf("❌ Error detectado en página: %s", mensajeError)
+				if strings.Contains(textoLower, strings.ToLower(mensajeError.texto)) {
+					log.Printf("❌ Error detectado en página: %s", mensajeError.texto)
 
 					// 📌 Imprimir HTML actual antes de retroceder
 					if html, err := page.HTML(); err == nil {
@@ -725,9 +633,9 @@ func (s *ScraperExtendido) VerificarErroresPagina(page *rod.Page) error {
 					}
 
 					// Intentar hacer clic en botón de retroceso
-					s.intentarRetroceso(page)
+					s.intentarRetroceso(ctx, page)
 
-					return fmt.Errorf("página retornó error: %s", mensajeError)
+					return fmt.Errorf("%w: %s", mensajeError.categoria, mensajeError.texto)
 				}
 			}
 		}
@@ -737,18 +645,20 @@ func (s *ScraperExtendido) VerificarErroresPagina(page *rod.Page) error {
 }
 
 // intentarRetroceso busca y hace clic en botones de retroceso
-func (s *ScraperExtendido) intentarRetroceso(page *rod.Page) {
+func (s *SUNATScraper) intentarRetroceso(ctx context.Context, page *rod.Page) {
+	page = page.Context(ctx)
+
 	// Botón tipo: <input class="form-button" type="button" value="Anterior" onclick="history.go(-1)">
 	if btn, err := page.Timeout(2 * time.Second).ElementX("//input[@type='button' and @value='Anterior']"); err == nil {
 		log.Println("🔙 Haciendo clic en botón 'Anterior'")
-		s.HumanClick(btn, page)
+		s.HumanClick(ctx, btn, page)
 		return
 	}
 
 	// Link tipo: <a href="javascript:history.back();">[Go Back]</a>
 	if link, err := page.Timeout(2 * time.Second).ElementX("//a[contains(@href, 'history.back')]"); err == nil {
 		log.Println("🔙 Haciendo clic en link 'Go Back'")
-		s.HumanClick(link, page)
+		s.HumanClick(ctx, link, page)
 		return
 	}
 
@@ -762,7 +672,7 @@ func (s *ScraperExtendido) intentarRetroceso(page *rod.Page) {
 	for _, xpath := range botones {
 		if btn, err := page.Timeout(2 * time.Second).ElementX(xpath); err == nil {
 			log.Println("🔙 Haciendo clic en botón de retroceso encontrado")
-			s.HumanClick(btn, page)
+			s.HumanClick(ctx, btn, page)
 			return
 		}
 	}
@@ -771,931 +681,141 @@ func (s *ScraperExtendido) intentarRetroceso(page *rod.Page) {
 	page.Eval("() => history.back()")
 }
 
-// ScrapeInformacionHistorica SIMPLIFICADO - retorna información de paginación
-func (s *ScraperExtendido) ScrapeInformacionHistorica(ruc string, page *rod.Page) (*models.InformacionHistorica, bool, error) {
-	// Buscar y hacer clic en botón
-	histBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfHis')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de información histórica: %w", err)
-	}
-
-	visible, err := histBtn.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de información histórica no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(histBtn, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-
-	var targetPage *rod.Page
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "informacion_historica") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de información histórica - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	info := &models.InformacionHistorica{}
-	s.extractHistoricalInfo(targetPage, info)
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Información Histórica")
-	log.Printf("🔍 Información Histórica - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	// RETORNAR CON INFORMACIÓN DE PAGINACIÓN
-	return info, tienePaginacion, nil
+// ResultadoSeccion es lo extraído de una consulta adicional
+type ResultadoSeccion struct {
+	Valor any
+	// TienePaginacion indica si la tabla ocupaba más de una página
+	TienePaginacion bool
+	// Paginacion se llena solo en secciones con RecorrerPaginacion
+	Paginacion models.PaginacionSeccion
 }
 
-// ScrapeDeudaCoactiva obtiene información de deuda coactiva
-func (s *ScraperExtendido) ScrapeDeudaCoactiva(ruc string, page *rod.Page) (*models.DeudaCoactiva, bool, error) {
+// ScrapeSeccion abre la consulta sec desde la ficha, extrae su contenido y vuelve a la ficha
+func (s *SUNATScraper) ScrapeSeccion(ctx context.Context, sec secciones.Seccion, page *rod.Page) (*ResultadoSeccion, error) {
+	page = page.Context(ctx)
+
 	// Buscar el botón usando ElementX (sin Must) con timeout
-	deudaBtn, err := page.Timeout(10 * time.Second).ElementX("//button[contains(@class, 'btnInfDeuCoa')]")
+	xpath := fmt.Sprintf("//button[contains(@class, '%s')]", sec.Boton)
+	btn, err := page.Timeout(10 * time.Second).ElementX(xpath)
 	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de deuda coactiva: %w", err)
+		return nil, fmt.Errorf("%w: no se encontró el botón de %s: %w", ErrBotonNoDisponible, sec.Nombre, err)
 	}
 
 	// Verificar si el botón está visible
-	visible, err := deudaBtn.Visible()
+	visible, err := btn.Visible()
 	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de deuda coactiva no está visible o disponible")
-	}
+		return nil, fmt.Errorf("%w: el botón de %s no está visible", ErrBotonNoDisponible, sec.Nombre)
+	}
+
+	// La pestaña que abra el clic se reconoce por su evento de creación con page
+	// como opener; una pestaña que ya existía (de otra sección o de otro RUC en el
+	// mismo navegador) no se toma ni se cierra. Se escucha antes del clic para no
+	// perder el evento.
+	var nueva proto.TargetTargetID
+	esperaCtx, cancelEspera := context.WithTimeout(ctx, 2*time.Second)
+	defer cancelEspera()
+	esperarPestana := s.browser.Context(esperaCtx).EachEvent(func(e *proto.TargetTargetCreated) bool {
+		if e.TargetInfo.Type == proto.TargetTargetInfoTypePage && e.TargetInfo.OpenerID == page.TargetID {
+			nueva = e.TargetInfo.TargetID
+			return true
+		}
+		return false
+	})
 
 	// Click humano
-	err = s.HumanClick(deudaBtn, page)
+	err = s.HumanClick(ctx, btn, page)
 	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
+		return nil, fmt.Errorf("error en click humano: %w", err)
 	}
 
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
+	// Esperar respuesta: hasta 2 segundos por una pestaña nueva; si no se abre,
+	// la consulta cargó en la misma pestaña
+	esperarPestana()
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
+	targetPage := page
+	if nueva != "" {
+		nuevaPage, err := s.browser.PageFromTarget(nueva)
+		if err != nil {
+			return nil, fmt.Errorf("error abriendo la pestaña de %s: %w", sec.Nombre, err)
 		}
-		return nil, false, errorPagina
+		targetPage = nuevaPage.Context(ctx)
+		targetPage.Activate()
 	}
 
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "deuda_coactiva") {
-		// Cleanup si era nueva pestaña
+	// Cleanup si era nueva pestaña, también cuando se sale con error. La
+	// pestaña se cierra sin ctx para que se cierre aunque ctx ya se haya cancelado.
+	defer func() {
 		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
+			targetPage.Context(context.Background()).Close()
+			page.Activate()
 		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de deuda coactiva - página incorrecta o no cargada")
-	}
-
-	deuda := &models.DeudaCoactiva{}
-	s.extractDeudaInfo(targetPage, deuda)
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Deuda coactiva")
-	log.Printf("🔍 Deuda coactiva - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return deuda, tienePaginacion, nil
-}
-
-// ScrapeRepresentantesLegales SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeRepresentantesLegales(ruc string, page *rod.Page) (*models.RepresentantesLegales, bool, error) {
-	// Buscar y hacer clic en botón
-	repButton, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfRepLeg')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de representantes legales: %w", err)
-	}
-
-	visible, err := repButton.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de representantes legales no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(repButton, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
+	}()
 
 	// Carga humana
-	err = s.HumanPageLoad(targetPage)
+	err = s.HumanPageLoad(ctx, targetPage)
 	if err != nil {
 		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
 	}
 
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, errorPagina
+	// Verificar errores de página ANTES de validar contenido
+	if errorPagina := s.VerificarErroresPagina(ctx, targetPage); errorPagina != nil {
+		return nil, errorPagina
 	}
 
 	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "representantes_legales") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de representantes legales - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	html, err := targetPage.HTML()
-	if err != nil {
-		return nil, false, fmt.Errorf("error al obtener HTML: %w", err)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-	if err != nil {
-		return nil, false, fmt.Errorf("error al parsear HTML: %w", err)
-	}
-
-	representantes := extraerRepresentantes(doc)
-	representantesLegales := &models.RepresentantesLegales{
-		Representantes: representantes,
-	}
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Representantes Legales")
-	log.Printf("🔍 Representantes Legales - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return representantesLegales, tienePaginacion, nil
-}
-
-// ScrapeCantidadTrabajadores SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeCantidadTrabajadores(ruc string, page *rod.Page) (*models.CantidadTrabajadores, bool, error) {
-	// Buscar y hacer clic en botón
-	trabBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfNumTra')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de cantidad de trabajadores: %w", err)
-	}
-
-	visible, err := trabBtn.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de cantidad de trabajadores no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(trabBtn, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
+	if !s.VerificarPaginaCorrecta(ctx, targetPage, sec.Clave) {
+		return nil, fmt.Errorf("%w: no se pudo acceder a la página de %s", ErrPaginaIncorrecta, strings.ToLower(sec.Nombre))
 	}
 
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
+	res := &ResultadoSeccion{}
+	if sec.Paginacion == secciones.RecorrerPaginacion {
+		// Extraer información de todas las páginas de la tabla
+		res.Paginacion, err = s.recorrerPaginas(ctx, targetPage, sec.Nombre, func(html io.Reader) (int, error) {
+			valor, filas, err := sec.Parsear(html, res.Valor)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %s: %w", ErrParseo, strings.ToLower(sec.Nombre), err)
+			}
+			res.Valor = valor
+			return filas, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		res.TienePaginacion = res.Paginacion.TienePaginacion()
+		log.Printf("🔍 %s - Paginación: %d página(s), %d filas de %d reportadas", sec.Nombre, res.Paginacion.Paginas, res.Paginacion.Filas, res.Paginacion.TotalReportado)
 	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
+		html, err := htmlPagina(targetPage)
+		if err != nil {
+			return nil, err
+		}
+		res.Valor, _, err = sec.Parsear(html, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrParseo, strings.ToLower(sec.Nombre), err)
 		}
-		return nil, false, errorPagina
-	}
 
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "cantidad_trabajadores") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
+		if sec.Paginacion == secciones.DetectarPaginacion {
+			var contexto string
+			res.TienePaginacion, contexto = s.DetectarPaginacionConContexto(ctx, targetPage, sec.Nombre)
+			log.Printf("🔍 %s - Paginación: %v (%s)", sec.Nombre, res.TienePaginacion, contexto)
 		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de cantidad de trabajadores - página incorrecta o no cargada")
 	}
 
-	// Extraer información
-	cantidadTrabajadores := &models.CantidadTrabajadores{}
-	s.extractTrabajadoresInfo(targetPage, cantidadTrabajadores)
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Cantidad Trabajadores")
-	log.Printf("🔍 Cantidad Trabajadores - Paginación: %v (%s)", tienePaginacion, contexto)
-
 	// Buscar y hacer clic en volver
 	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
 	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
+		return nil, fmt.Errorf("no se encontró el botón volver: %w", err)
 	}
 
-	err = s.HumanClick(volver, targetPage)
+	err = s.HumanClick(ctx, volver, targetPage)
 	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
+		return nil, fmt.Errorf("error en click volver: %w", err)
 	}
 
 	// Esperar que página original esté lista
 	page.WaitLoad()
 	page.WaitStable(2 * time.Second)
 
-	return cantidadTrabajadores, tienePaginacion, nil
-}
-
-// ScrapeEstablecimientosAnexos SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeEstablecimientosAnexos(ruc string, page *rod.Page) (*models.EstablecimientosAnexos, bool, error) {
-	// Buscar y hacer clic en botón
-	estabBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfLocAnex')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de establecimientos anexos: %w", err)
-	}
-
-	visible, err := estabBtn.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de establecimientos anexos no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(estabBtn, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "establecimientos_anexos") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de establecimientos anexos - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	establecimientosAnexos := &models.EstablecimientosAnexos{}
-	err = s.extractEstablecimientosInfo(targetPage, establecimientosAnexos)
-	if err != nil {
-		return nil, false, fmt.Errorf("error al extraer información de establecimientos: %w", err)
-	}
-
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Establemientos anexos")
-	log.Printf("🔍 Establemientos anexos - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return establecimientosAnexos, tienePaginacion, nil
-}
-
-// Métodos adicionales para las demás consultas...
-
-// ScrapeOmisionesTributarias SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeOmisionesTributarias(ruc string, page *rod.Page) (*models.OmisionesTributarias, bool, error) {
-	// Buscar y hacer clic en botón
-	omisBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfOmiTri')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de omisiones tributarias: %w", err)
-	}
-
-	visible, err := omisBtn.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de omisiones tributarias no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(omisBtn, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "omisiones_tributarias") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de omisiones tributarias - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	omisionesTributarias := &models.OmisionesTributarias{}
-	s.extractOmisionesInfo(targetPage, omisionesTributarias)
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Omisiones Tributarias")
-	log.Printf("🔍 Omisiones tributarias - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return omisionesTributarias, tienePaginacion, nil
-}
-
-// ScrapeActasProbatorias SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeActasProbatorias(ruc string, page *rod.Page) (*models.ActasProbatorias, bool, error) {
-	// Buscar y hacer clic en botón
-	actasBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfActPro')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de actas probatorias: %w", err)
-	}
-
-	visible, err := actasBtn.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de actas probatorias no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(actasBtn, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "actas_probatorias") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de actas probatorias - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	actasProbatorias := &models.ActasProbatorias{}
-	s.extractActasProbatoriasInfo(targetPage, actasProbatorias)
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Actas Probatorias")
-	log.Printf("🔍 Actas probatorias - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return actasProbatorias, tienePaginacion, nil
-}
-
-// ScrapeFacturasFisicas SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeFacturasFisicas(ruc string, page *rod.Page) (*models.FacturasFisicas, bool, error) {
-	// Buscar y hacer clic en botón
-	facturasBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfActCPF')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón de facturas físicas: %w", err)
-	}
-
-	visible, err := facturasBtn.Visible()
-	if err != nil || !visible {
-		return nil, false, fmt.Errorf("el botón de facturas físicas no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(facturasBtn, page)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "facturas_fisicas") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, false, fmt.Errorf("no se pudo acceder a la página de facturas físicas - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	facturasFisicas := &models.FacturasFisicas{}
-	s.extractFacturasFisicasInfo(targetPage, facturasFisicas)
-
-	// DETECTAR PAGINACIÓN
-	tienePaginacion, contexto := s.DetectarPaginacionConContexto(targetPage, "Facturas fisicas")
-	log.Printf("🔍 Facturas fisicas - Paginación: %v (%s)", tienePaginacion, contexto)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, false, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, false, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return facturasFisicas, tienePaginacion, nil
-}
-
-// ScrapeReactivaPeru SIMPLIFICADO - solo lo esencial
-func (s *ScraperExtendido) ScrapeReactivaPeru(ruc string, page *rod.Page) (*models.ReactivaPeru, error) {
-	// Buscar y hacer clic en botón
-	reactivaBtn, err := page.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnInfReaPer')]")
-	if err != nil {
-		return nil, fmt.Errorf("no se encontró el botón de Reactiva Perú: %w", err)
-	}
-
-	visible, err := reactivaBtn.Visible()
-	if err != nil || !visible {
-		return nil, fmt.Errorf("el botón de Reactiva Perú no está visible")
-	}
-
-	// Click humano
-	err = s.HumanClick(reactivaBtn, page)
-	if err != nil {
-		return nil, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "reactiva_peru") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, fmt.Errorf("no se pudo acceder a la página de reactiva perú - página incorrecta o no cargada")
-	}
-
-	// Extraer información
-	reactivaPeru := &models.ReactivaPeru{}
-	s.extractReactivaPeruInfo(targetPage, reactivaPeru)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	// Esperar que página original esté lista
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return reactivaPeru, nil
-}
-
-// ScrapeProgramaCovid19 obtiene información de programas COVID-19
-func (s *ScraperExtendido) ScrapeProgramaCovid19(ruc string, page *rod.Page) (*models.ProgramaCovid19, error) {
-
-	// Buscar el botón usando ElementX (sin Must) con timeout
-	covidBtn, err := page.Timeout(10 * time.Second).ElementX("//button[contains(@class, 'btnInfCovid')]")
-	if err != nil {
-		return nil, fmt.Errorf("no se encontró el botón de Programa COVID-19: %w", err)
-	}
-
-	// Verificar si el botón está visible
-	visible, err := covidBtn.Visible()
-	if err != nil || !visible {
-		return nil, fmt.Errorf("el botón de Programa COVID-19 no está visible o disponible")
-	}
-
-	// Click humano
-	err = s.HumanClick(covidBtn, page)
-	if err != nil {
-		return nil, fmt.Errorf("error en click humano: %w", err)
-	}
-
-	// Esperar respuesta y detectar si se abrió nueva pestaña
-	time.Sleep(2 * time.Second)
-	pages := s.browser.MustPages()
-	var targetPage *rod.Page
-
-	if len(pages) > 1 {
-		// Nueva pestaña
-		targetPage = pages[len(pages)-1]
-		targetPage.MustActivate()
-	} else {
-		// Misma pestaña
-		targetPage = page
-	}
-
-	// Carga humana
-	err = s.HumanPageLoad(targetPage)
-	if err != nil {
-		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
-	}
-
-	// ✅ AÑADIR AQUÍ - Verificar errores de página ANTES de validar contenido
-	if errorPagina := s.VerificarErroresPagina(targetPage); errorPagina != nil {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, errorPagina
-	}
-
-	// VALIDAR QUE ESTAMOS EN LA PÁGINA CORRECTA
-	if !s.VerificarPaginaCorrecta(targetPage, "garantias_covid19") {
-		// Cleanup si era nueva pestaña
-		if targetPage != page {
-			targetPage.MustClose()
-			page.MustActivate()
-		}
-		return nil, fmt.Errorf("no se pudo acceder a la página de programa covid-19 - página incorrecta o no cargada")
-	}
-
-	programaCovid := &models.ProgramaCovid19{}
-	// Extraer información del Programa COVID-19
-	s.extractProgramaCovid19Info(page, programaCovid)
-
-	// Buscar y hacer clic en volver
-	volver, err := targetPage.Timeout(8 * time.Second).ElementX("//button[contains(@class, 'btnNuevaConsulta')]")
-	if err != nil {
-		return nil, fmt.Errorf("no se encontró el botón volver: %w", err)
-	}
-
-	err = s.HumanClick(volver, targetPage)
-	if err != nil {
-		return nil, fmt.Errorf("error en click volver: %w", err)
-	}
-
-	// Cleanup si era nueva pestaña
-	if targetPage != page {
-		targetPage.MustClose()
-		time.Sleep(500 * time.Millisecond)
-		page.MustActivate()
-	}
-
-	page.WaitLoad()
-	page.WaitStable(2 * time.Second)
-
-	return programaCovid, nil
+	return res, nil
 }
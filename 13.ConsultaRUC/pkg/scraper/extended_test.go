@@ -0,0 +1,109 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/sunatmock"
+)
+
+// TestConsultarMock recorre el flujo completo contra el mock de SUNAT.
+// Requiere un navegador, por lo que solo se ejecuta con SUNAT_E2E=1.
+func TestConsultarMock(t *testing.T) {
+	if os.Getenv("SUNAT_E2E") == "" {
+		t.Skip("defina SUNAT_E2E=1 para ejecutar la prueba con navegador")
+	}
+
+	fixtures, err := sunatmock.CargarFixtures("../parser/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, _ := sunatmock.NewServer(fixtures...)
+	defer srv.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = sunatmock.URLFormulario(srv.URL)
+	s, err := NewScraper(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	rc, err := s.Consultar(context.Background(), "20606316977", OpcionesConsulta{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rc.InformacionBasica.RazonSocial != "FERNANDEZ CONSULTORES SG & ASOCIADOS EIRL" {
+		t.Errorf("RazonSocial = %q", rc.InformacionBasica.RazonSocial)
+	}
+	if rc.DeudaCoactiva == nil || rc.DeudaCoactiva.CantidadDocumentos != 3 {
+		t.Errorf("DeudaCoactiva = %+v", rc.DeudaCoactiva)
+	}
+	if rc.RepresentantesLegales == nil || len(rc.RepresentantesLegales.Representantes) != 2 {
+		t.Errorf("RepresentantesLegales = %+v", rc.RepresentantesLegales)
+	}
+}
+
+// TestConsultarFallaSeccion verifica que una sección que agota sus reintentos
+// devuelve los datos parciales y un *ScrapeError en lugar de terminar el proceso
+func TestConsultarFallaSeccion(t *testing.T) {
+	if os.Getenv("SUNAT_E2E") == "" {
+		t.Skip("defina SUNAT_E2E=1 para ejecutar la prueba con navegador")
+	}
+
+	fixtures, err := sunatmock.CargarFixtures("../parser/testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, mock := sunatmock.NewServer(fixtures...)
+	defer srv.Close()
+	mock.FallarSeccion("20606316977", "btnInfDeuCoa", sunatmock.FallaAplicacion, 10)
+
+	config := DefaultConfig()
+	config.BaseURL = sunatmock.URLFormulario(srv.URL)
+	config.MaxReintentos = 2
+	config.DelayReintentos = time.Millisecond
+	s, err := NewScraper(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	rc, err := s.Consultar(context.Background(), "20606316977", OpcionesConsulta{Secciones: []string{"historica", "deuda"}})
+	var scrapeErr *ScrapeError
+	if !errors.As(err, &scrapeErr) || scrapeErr.Seccion != "Deuda Coactiva" || scrapeErr.Intento != 2 {
+		t.Fatalf("err = %v, se esperaba ScrapeError de Deuda Coactiva", err)
+	}
+	if !errors.Is(err, ErrAplicacionSUNAT) || Estado(err) != EstadoFallido {
+		t.Errorf("err = %v no se clasificó como ErrAplicacionSUNAT", err)
+	}
+	if rc == nil || rc.InformacionHistorica == nil {
+		t.Errorf("no se devolvieron los datos parciales: %+v", rc)
+	}
+}
+
+func TestConsultarRUCInexistente(t *testing.T) {
+	if os.Getenv("SUNAT_E2E") == "" {
+		t.Skip("defina SUNAT_E2E=1 para ejecutar la prueba con navegador")
+	}
+
+	srv, _ := sunatmock.NewServer()
+	defer srv.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = sunatmock.URLFormulario(srv.URL)
+	s, err := NewScraper(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	rc, err := s.Consultar(context.Background(), "20999999990", OpcionesConsulta{})
+	if !errors.Is(err, ErrRUCNoEncontrado) || Estado(err) != EstadoInexistente || rc != nil {
+		t.Fatalf("rc = %v, err = %v; se esperaba ErrRUCNoEncontrado", rc, err)
+	}
+}
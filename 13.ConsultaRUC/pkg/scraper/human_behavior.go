@@ -26,21 +26,9 @@ type HumanBehaviorSimulator struct {
 	typingSpeed    float64 // caracteres por minuto
 }
 
-// ScraperExtendido incluye métodos para todas las consultas adicionales
-type ScraperExtendido struct {
-	*SUNATScraper
-	humanSim *HumanBehaviorSimulator
-}
-
-// NewScraperExtendido crea una nueva instancia del scraper extendido
-func NewScraperExtendido() (*ScraperExtendido, error) {
-	base, err := NewSUNATScraper()
-	if err != nil {
-		return nil, err
-	}
-
-	// Inicializar simulador de comportamiento humano
-	humanSim := &HumanBehaviorSimulator{
+// newHumanBehaviorSimulator inicializa el simulador con velocidades aleatorias
+func newHumanBehaviorSimulator() *HumanBehaviorSimulator {
+	return &HumanBehaviorSimulator{
 		startTime:    time.Now(),
 		actionCount:  0,
 		fatigueLevel: 0.0,
@@ -55,11 +43,6 @@ func NewScraperExtendido() (*ScraperExtendido, error) {
 		readingSpeed:   200 + rand.Float64()*100, // 200-300 WPM
 		typingSpeed:    180 + rand.Float64()*120, // 180-300 CPM (3-5 CPS)
 	}
-
-	return &ScraperExtendido{
-		SUNATScraper: base,
-		humanSim:     humanSim,
-	}, nil
 }
 
 // updateFatigue actualiza el nivel de fatiga basado en actividad
@@ -93,7 +76,7 @@ func (h *HumanBehaviorSimulator) shouldTakeBreak() bool {
 }
 
 // takeBreak simula un descanso humano
-func (h *HumanBehaviorSimulator) takeBreak() {
+func (h *HumanBehaviorSimulator) takeBreak(ctx context.Context) error {
 	breakType := rand.Intn(4)
 	var breakDuration time.Duration
 
@@ -117,16 +100,20 @@ func (h *HumanBehaviorSimulator) takeBreak() {
 		}
 	}
 
-	time.Sleep(breakDuration)
+	return esperar(ctx, breakDuration)
 }
 
 // HumanClick CORREGIDO - múltiples errores solucionados
-func (s *ScraperExtendido) HumanClick(element *rod.Element, page *rod.Page) error {
+func (s *SUNATScraper) HumanClick(ctx context.Context, element *rod.Element, page *rod.Page) error {
+	page = page.Context(ctx)
+
 	// Actualizar fatiga y verificar descansos
 	s.humanSim.updateFatigue()
 
 	if s.humanSim.shouldTakeBreak() {
-		s.humanSim.takeBreak()
+		if err := s.humanSim.takeBreak(ctx); err != nil {
+			return err
+		}
 	}
 
 	// Rotar user agent ocasionalmente
@@ -134,7 +121,9 @@ func (s *ScraperExtendido) HumanClick(element *rod.Element, page *rod.Page) erro
 
 	// Delay pre-acción con distribución log-normal
 	preDelay := s.humanSim.generateLogNormalDelay(200, 80)
-	time.Sleep(preDelay)
+	if err := esperar(ctx, preDelay); err != nil {
+		return err
+	}
 
 	// VERIFICAR QUE EL ELEMENTO SIGUE SIENDO VÁLIDO
 	if element == nil {
@@ -154,7 +143,9 @@ func (s *ScraperExtendido) HumanClick(element *rod.Element, page *rod.Page) erro
 	}
 
 	// Esperar un poco después del scroll
-	time.Sleep(200 * time.Millisecond)
+	if err := esperar(ctx, 200*time.Millisecond); err != nil {
+		return err
+	}
 
 	// Obtener coordenadas del elemento (CON VALIDACIÓN)
 	box, err := element.Shape()
@@ -207,12 +198,16 @@ func (s *ScraperExtendido) HumanClick(element *rod.Element, page *rod.Page) erro
 
 		// Delay más corto entre movimientos
 		moveDelay := time.Duration(20+rand.Intn(30)) * time.Millisecond
-		time.Sleep(moveDelay)
+		if err := esperar(ctx, moveDelay); err != nil {
+			return err
+		}
 	}
 
 	// Tiempo de reacción humano antes del clic (MÁS CORTO)
 	reactionTime := time.Duration(100+rand.Intn(100)) * time.Millisecond
-	time.Sleep(reactionTime)
+	if err := esperar(ctx, reactionTime); err != nil {
+		return err
+	}
 
 	// CLICK SIMPLE Y CONFIABLE
 	err = page.Mouse.MoveTo(proto.Point{X: targetX, Y: targetY})
@@ -234,16 +229,20 @@ func (s *ScraperExtendido) HumanClick(element *rod.Element, page *rod.Page) erro
 
 	// Delay post-clic (MÁS CORTO)
 	postDelay := time.Duration(200+rand.Intn(200)) * time.Millisecond
-	time.Sleep(postDelay)
+	if err := esperar(ctx, postDelay); err != nil {
+		return err
+	}
 
 	log.Printf(" 🖱️ Click humano exitoso en (%.1f, %.1f)", targetX, targetY)
 	return nil
 }
 
 // HumanPageLoad CORREGIDO - timeouts más razonables
-func (s *ScraperExtendido) HumanPageLoad(page *rod.Page) error {
+func (s *SUNATScraper) HumanPageLoad(ctx context.Context, page *rod.Page) error {
+	page = page.Context(ctx)
+
 	// Esperar carga técnica CON TIMEOUT
-	loadCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	loadCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	err := page.Context(loadCtx).WaitLoad()
@@ -253,7 +252,7 @@ func (s *ScraperExtendido) HumanPageLoad(page *rod.Page) error {
 	}
 
 	// WaitStable con timeout más corto
-	stableCtx, cancel2 := context.WithTimeout(context.Background(), 8*time.Second)
+	stableCtx, cancel2 := context.WithTimeout(ctx, 8*time.Second)
 	defer cancel2()
 
 	err = page.Context(stableCtx).WaitStable(3 * time.Second)
@@ -283,7 +282,9 @@ func (s *ScraperExtendido) HumanPageLoad(page *rod.Page) error {
 	}
 
 	log.Printf(" 📖 Simulando lectura de página: %v", readingTime)
-	time.Sleep(readingTime)
+	if err := esperar(ctx, readingTime); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -366,7 +367,9 @@ func (h *HumanBehaviorSimulator) generateLogNormalDelay(meanMs, stdMs float64) t
 }
 
 // HumanInput simula escritura humana avanzada con características realistas
-func (s *ScraperExtendido) HumanInput(element *rod.Element, text string) error {
+func (s *SUNATScraper) HumanInput(ctx context.Context, element *rod.Element, text string) error {
+	element = element.Context(ctx)
+
 	// Limpiar campo con delay humano
 	err := element.SelectAllText()
 	if err == nil {
@@ -378,7 +381,9 @@ func (s *ScraperExtendido) HumanInput(element *rod.Element, text string) error {
 
 	// Delay inicial antes de empezar a escribir
 	startDelay := s.humanSim.generateLogNormalDelay(400, 200)
-	time.Sleep(startDelay)
+	if err := esperar(ctx, startDelay); err != nil {
+		return err
+	}
 
 	// Calcular velocidad de escritura base (variable por fatiga)
 	baseSpeed := s.humanSim.typingSpeed * (1.0 - s.humanSim.fatigueLevel*0.3)
@@ -406,7 +411,9 @@ func (s *ScraperExtendido) HumanInput(element *rod.Element, text string) error {
 		// Pausas ocasionales como si pensara
 		if rand.Float64() < 0.15 { // 15% probabilidad de pausa
 			thinkPause := s.humanSim.generateLogNormalDelay(800, 400)
-			time.Sleep(thinkPause)
+			if err := esperar(ctx, thinkPause); err != nil {
+				return err
+			}
 		}
 
 		// Errores de escritura ocasionales (más frecuentes con fatiga)
@@ -418,7 +425,9 @@ func (s *ScraperExtendido) HumanInput(element *rod.Element, text string) error {
 
 			// Pausa de "darse cuenta del error"
 			errorRealizationDelay := s.humanSim.generateLogNormalDelay(300, 100)
-			time.Sleep(errorRealizationDelay)
+			if err := esperar(ctx, errorRealizationDelay); err != nil {
+				return err
+			}
 
 			// Borrar carácter incorrecto
 			page := element.Page()
@@ -426,17 +435,23 @@ func (s *ScraperExtendido) HumanInput(element *rod.Element, text string) error {
 
 			// Pausa antes de escribir el carácter correcto
 			correctionDelay := s.humanSim.generateLogNormalDelay(200, 80)
-			time.Sleep(correctionDelay)
+			if err := esperar(ctx, correctionDelay); err != nil {
+				return err
+			}
 		}
 
 		// Aplicar delay principal entre caracteres
 		finalDelay := s.humanSim.generateLogNormalDelay(charDelay, charDelay*0.3)
-		time.Sleep(finalDelay)
+		if err := esperar(ctx, finalDelay); err != nil {
+			return err
+		}
 	}
 
 	// Pausa final después de escribir
 	endDelay := s.humanSim.generateLogNormalDelay(400, 200)
-	time.Sleep(endDelay)
+	if err := esperar(ctx, endDelay); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1,17 +1,24 @@
 package scraper
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/parser"
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 )
 
-func (s *ScraperExtendido) DetectarPaginacion(page *rod.Page) bool {
+func (s *SUNATScraper) DetectarPaginacion(ctx context.Context, page *rod.Page) bool {
+	page = page.Context(ctx)
+
 	// DETECTORES ESPECÍFICOS PARA SUNAT - MEJORADOS
 
 	// 1. Buscar el patrón más específico de SUNAT: "Páginas:" seguido de enlaces
@@ -123,7 +130,7 @@ func (s *ScraperExtendido) DetectarPaginacion(page *rod.Page) bool {
 	}
 
 	// Búsqueda por texto con patrones MUY específicos
-	pageText, err := page.MustElement("body").Text()
+	pageText, err := textoBody(page)
 	if err == nil {
 		// PATRONES ESPECÍFICOS DE SUNAT - CON VALIDACIÓN MEJORADA
 		if strings.Contains(pageText, "Páginas:") {
@@ -228,7 +235,7 @@ func (s *ScraperExtendido) DetectarPaginacion(page *rod.Page) bool {
 	// Si solo tiene "Siguiente", también puede ser paginación (primera página)
 	if hasSiguiente {
 		// Buscar indicios adicionales de que es primera página - CON VALIDACIÓN MEJORADA
-		pageText, err := page.MustElement("body").Text()
+		pageText, err := textoBody(page)
 		if err == nil {
 			if strings.Contains(pageText, "Páginas:") {
 				// Validar que no sea "1 a 1 de 1"
@@ -254,7 +261,9 @@ func (s *ScraperExtendido) DetectarPaginacion(page *rod.Page) bool {
 }
 
 // Nueva función para debugging específico y detallado
-func (s *ScraperExtendido) DebugPaginacionDetallado(page *rod.Page) {
+func (s *SUNATScraper) DebugPaginacionDetallado(ctx context.Context, page *rod.Page) {
+	page = page.Context(ctx)
+
 	log.Printf("🔍 === DEBUG DETECCIÓN DE PAGINACIÓN ===")
 
 	// 1. Verificar elemento "Páginas:"
@@ -307,7 +316,7 @@ func (s *ScraperExtendido) DebugPaginacionDetallado(page *rod.Page) {
 	}
 
 	// 6. Búsqueda en texto de la página
-	pageText, err := page.MustElement("body").Text()
+	pageText, err := textoBody(page)
 	if err == nil {
 		hasPages := strings.Contains(pageText, "Páginas:")
 		log.Printf("6. Texto página contiene 'Páginas:': %v", hasPages)
@@ -329,8 +338,10 @@ func min(a, b int) int {
 }
 
 // DetectarPaginacionConContexto detecta paginación y proporciona información adicional
-func (s *ScraperExtendido) DetectarPaginacionConContexto(page *rod.Page, seccion string) (bool, string) {
-	tienePaginacion := s.DetectarPaginacion(page)
+func (s *SUNATScraper) DetectarPaginacionConContexto(ctx context.Context, page *rod.Page, seccion string) (bool, string) {
+	page = page.Context(ctx)
+
+	tienePaginacion := s.DetectarPaginacion(ctx, page)
 
 	if !tienePaginacion {
 		return false, "No se detectó paginación"
@@ -359,7 +370,7 @@ func (s *ScraperExtendido) DetectarPaginacionConContexto(page *rod.Page, seccion
 
 	// Si no es SUNAT, usar patrones generales
 	if contexto == "" {
-		pageText, err := page.MustElement("body").Text()
+		pageText, err := textoBody(page)
 		if err == nil {
 			// Verificar si contiene "Páginas:"
 			if strings.Contains(pageText, "Páginas:") {
@@ -408,7 +419,9 @@ func (s *ScraperExtendido) DetectarPaginacionConContexto(page *rod.Page, seccion
 }
 
 // ValidarPaginacionEnSeccion - función auxiliar para validar en secciones específicas
-func (s *ScraperExtendido) ValidarPaginacionEnSeccion(page *rod.Page, seccion string) (bool, string) {
+func (s *SUNATScraper) ValidarPaginacionEnSeccion(ctx context.Context, page *rod.Page, seccion string) (bool, string) {
+	page = page.Context(ctx)
+
 	// Log para debugging
 	log.Printf("🔍 Verificando paginación en sección: %s", seccion)
 
@@ -419,11 +432,13 @@ func (s *ScraperExtendido) ValidarPaginacionEnSeccion(page *rod.Page, seccion st
 		log.Printf("📄 Tamaño del HTML: %d caracteres", len(pageHTML))
 	}
 
-	return s.DetectarPaginacionConContexto(page, seccion)
+	return s.DetectarPaginacionConContexto(ctx, page, seccion)
 }
 
 // DebugPaginacionSUNAT - función para debugging específico
-func (s *ScraperExtendido) DebugPaginacionSUNAT(page *rod.Page) {
+func (s *SUNATScraper) DebugPaginacionSUNAT(ctx context.Context, page *rod.Page) {
+	page = page.Context(ctx)
+
 	// Buscar elementos específicos
 	paginasElement, err1 := page.ElementX("//td[contains(text(), 'Páginas:')]")
 	paginacionLinks, err2 := page.ElementsX("//a[contains(@href, 'javascript:paginacion')]")
@@ -441,3 +456,95 @@ func (s *ScraperExtendido) DebugPaginacionSUNAT(page *rod.Page) {
 		log.Printf("   - HTML tabla paginación: %s", html)
 	}
 }
+
+// maxPaginas limita el recorrido de una tabla para no quedar en un ciclo si el
+// paginador de SUNAT no avanza
+const maxPaginas = 200
+
+// recorrerPaginas procesa la página actual y sigue los enlaces
+// javascript:paginacion(n) hasta la última. procesar recibe el HTML de cada
+// página y devuelve la cantidad de filas extraídas, que se contrasta con el
+// contador "X a Y de Z" de SUNAT.
+func (s *SUNATScraper) recorrerPaginas(ctx context.Context, page *rod.Page, seccion string, procesar func(html io.Reader) (int, error)) (models.PaginacionSeccion, error) {
+	page = page.Context(ctx)
+
+	var resultado models.PaginacionSeccion
+	visitadas := map[int]bool{1: true}
+
+	for {
+		html, err := page.HTML()
+		if err != nil {
+			return resultado, fmt.Errorf("error al obtener HTML: %w", err)
+		}
+
+		filas, err := procesar(strings.NewReader(html))
+		if err != nil {
+			return resultado, err
+		}
+
+		pag, err := parser.ParsePaginacion(strings.NewReader(html))
+		if err != nil {
+			return resultado, err
+		}
+		resultado.AgregarPagina(filas, pag.Total)
+
+		if !pag.TieneMasPaginas() || visitadas[pag.Siguiente] {
+			break
+		}
+		if resultado.Paginas >= maxPaginas {
+			log.Printf("⚠️ %s - Se alcanzó el límite de %d páginas", seccion, maxPaginas)
+			break
+		}
+		visitadas[pag.Siguiente] = true
+
+		log.Printf("📄 %s - Cargando página %d (%d filas de %d)", seccion, pag.Siguiente, resultado.Filas, pag.Total)
+		if err := s.irAPagina(ctx, page, pag.Siguiente); err != nil {
+			return resultado, err
+		}
+		if errorPagina := s.VerificarErroresPagina(ctx, page); errorPagina != nil {
+			return resultado, errorPagina
+		}
+	}
+
+	if !resultado.Verificado {
+		log.Printf("⚠️ %s - Se extrajeron %d filas pero SUNAT reporta %d", seccion, resultado.Filas, resultado.TotalReportado)
+	}
+
+	return resultado, nil
+}
+
+// textoBody retorna el texto visible de la página
+func textoBody(page *rod.Page) (string, error) {
+	body, err := page.Element("body")
+	if err != nil {
+		return "", err
+	}
+	return body.Text()
+}
+
+// irAPagina navega a la página n de la tabla con el enlace javascript:paginacion(n)
+func (s *SUNATScraper) irAPagina(ctx context.Context, page *rod.Page, n int) error {
+	page = page.Context(ctx)
+
+	navegacion := page.Timeout(30 * time.Second)
+	defer navegacion.CancelTimeout()
+	esperarCarga := navegacion.WaitNavigation(proto.PageLifecycleEventNameLoad)
+
+	enlace, err := page.Timeout(5 * time.Second).Element(fmt.Sprintf(`a[href*="paginacion(%d)"]`, n))
+	if err == nil {
+		err = s.HumanClick(ctx, enlace, page)
+	} else {
+		// Sin enlace visible se invoca directamente la función de la página
+		_, err = page.Eval(`n => paginacion(n)`, n)
+	}
+	if err != nil {
+		return fmt.Errorf("no se pudo ir a la página %d: %w", n, err)
+	}
+
+	esperarCarga()
+
+	if err := s.HumanPageLoad(ctx, page); err != nil {
+		log.Printf("⚠️ Warning: Error en carga humana: %v", err)
+	}
+	return nil
+}
@@ -1,244 +1,211 @@
 package scraper
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/parser"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 )
 
-type SUNATScraper struct {
-	browser *rod.Browser
-	baseURL string
+// URLConsultaRUC es el formulario de consulta RUC de SUNAT. Se puede
+// reemplazar con la variable de entorno SUNAT_BASE_URL (por ejemplo, para
+// apuntar al servidor de cmd/sunat-mock).
+const URLConsultaRUC = "https://e-consultaruc.sunat.gob.pe/cl-ti-itmrconsruc/FrameCriterioBusquedaWeb.jsp"
+
+// Scraper es la API pública para consultar un RUC en SUNAT
+type Scraper interface {
+	// Consultar obtiene la ficha del RUC y todas las consultas adicionales disponibles.
+	// ctx limita toda la consulta; si una sección falla retorna lo obtenido hasta
+	// ese momento junto con un *ScrapeError.
+	Consultar(ctx context.Context, ruc string, opts OpcionesConsulta) (*models.RUCCompleto, error)
+	// Close libera el navegador
+	Close() error
 }
 
-func NewSUNATScraper() (*SUNATScraper, error) {
-	l := launcher.New().
-		Headless(true).
-		Devtools(false).
-		Set("no-sandbox").
-		Set("disable-dev-shm-usage").
-		Set("disable-gpu")
-	url := l.MustLaunch()
-	browser := rod.New().
-		ControlURL(url).
-		MustConnect()
-	return &SUNATScraper{
-		browser: browser,
-		baseURL: "https://e-consultaruc.sunat.gob.pe/cl-ti-itmrconsruc/FrameCriterioBusquedaWeb.jsp",
-	}, nil
+// OpcionesConsulta ajusta una consulta individual
+type OpcionesConsulta struct {
+	// Secciones limita las consultas adicionales (clave o alias de pkg/secciones,
+	// p. ej. "deuda", "representantes"); vacío consulta todas. La ficha siempre se consulta.
+	Secciones []string
 }
 
-func (s *SUNATScraper) Close() {
-	s.browser.MustClose()
+// ConfigScraper contiene la configuración del scraper
+type ConfigScraper struct {
+	Headless bool
+	// BaseURL es el formulario de consulta; por defecto URLConsultaRUC o SUNAT_BASE_URL
+	BaseURL string
+	// BrowserBin es el ejecutable de Chrome/Chromium; vacío usa el que encuentre rod
+	BrowserBin string
+	// ControlURL apunta a un navegador remoto por CDP (ws://... o http://host:9222);
+	// si se define no se lanza un navegador local
+	ControlURL string
+	// SlowMotion agrega una pausa antes de cada acción del navegador
+	SlowMotion time.Duration
+	// Timeout limita cada intento de consulta de una sección
+	Timeout         time.Duration
+	MaxReintentos   int
+	DelayReintentos time.Duration
 }
 
-func (s *SUNATScraper) ScrapeRUC(ruc string, page *rod.Page) (*models.RUCInfo, error) {
-	// Wait for results to load
-	time.Sleep(5 * time.Second)
-
-	info := &models.RUCInfo{
-		RUC: ruc,
+// DefaultConfig retorna la configuración por defecto
+func DefaultConfig() ConfigScraper {
+	baseURL := os.Getenv("SUNAT_BASE_URL")
+	if baseURL == "" {
+		baseURL = URLConsultaRUC
 	}
-
-	err := s.extractRUCInfo(page, info)
-	if err != nil {
-		return nil, fmt.Errorf("error extracting RUC info: %v", err)
+	return ConfigScraper{
+		Headless:        true,
+		BaseURL:         baseURL,
+		Timeout:         2 * time.Minute,
+		MaxReintentos:   3,
+		DelayReintentos: 2 * time.Second,
 	}
+}
 
-	return info, nil
+// SUNATScraper implementa Scraper sobre un navegador controlado con rod
+type SUNATScraper struct {
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+	config   ConfigScraper
+	humanSim *HumanBehaviorSimulator
 }
 
-func (s *SUNATScraper) extractRUCInfo(page *rod.Page, info *models.RUCInfo) error {
-	// Extract data from Bootstrap list-group structure
-	listItems := page.MustElements(".list-group-item")
+var _ Scraper = (*SUNATScraper)(nil)
 
-	for _, item := range listItems {
-		// Check if item has the standard structure
-		rows := item.MustElements(".row")
-		if len(rows) == 0 {
-			continue
-		}
+// conDefectos completa los campos sin valor con los de DefaultConfig. Un
+// Timeout cero dejaría cada intento sin plazo.
+func (c ConfigScraper) conDefectos() ConfigScraper {
+	defecto := DefaultConfig()
+	if c.BaseURL == "" {
+		c.BaseURL = defecto.BaseURL
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defecto.Timeout
+	}
+	if c.MaxReintentos < 1 {
+		c.MaxReintentos = 1
+	}
+	if c.DelayReintentos <= 0 {
+		c.DelayReintentos = defecto.DelayReintentos
+	}
+	return c
+}
+
+// NewScraper lanza (o se conecta a) un navegador según config; los campos
+// sin valor toman los de DefaultConfig
+func NewScraper(config ConfigScraper) (*SUNATScraper, error) {
+	config = config.conDefectos()
 
-		row := rows[0]
-		cols := row.MustElements("[class*='col-sm']")
-
-		if len(cols) >= 2 {
-			// Extract heading
-			headings := cols[0].MustElements(".list-group-item-heading")
-
-			if len(headings) > 0 {
-				label := strings.TrimSpace(headings[0].MustText())
-
-				// Check for tables FIRST (before extracting text)
-				tables := cols[1].MustElements("table")
-				if len(tables) > 0 {
-					s.extractTableData(tables[0], label, info)
-				} else {
-					// If no table, extract text normally
-					texts := cols[1].MustElements(".list-group-item-text, .list-group-item-heading")
-
-					if len(texts) > 0 {
-						value := strings.TrimSpace(texts[0].MustText())
-
-						// Handle special case for RUC number
-						if strings.Contains(label, "Número de RUC") {
-							// Extract just the RUC number
-							parts := strings.Split(value, " - ")
-							if len(parts) > 0 {
-								info.RUC = parts[0]
-								if len(parts) > 1 {
-									info.RazonSocial = parts[1]
-								}
-							}
-						} else {
-							s.mapFieldToStruct(label, value, info)
-						}
-					}
-				}
-			}
+	s := &SUNATScraper{
+		config:   config,
+		humanSim: newHumanBehaviorSimulator(),
+	}
+
+	controlURL := config.ControlURL
+	if controlURL != "" {
+		u, err := launcher.ResolveURL(controlURL)
+		if err != nil {
+			return nil, fmt.Errorf("error resolviendo ControlURL %s: %w", controlURL, err)
 		}
+		controlURL = u
+	} else {
+		l := launcher.New().
+			Headless(config.Headless).
+			Devtools(false).
+			Set("no-sandbox").
+			Set("disable-dev-shm-usage").
+			Set("disable-gpu")
+		if config.BrowserBin != "" {
+			l = l.Bin(config.BrowserBin)
+		}
+		u, err := l.Launch()
+		if err != nil {
+			return nil, fmt.Errorf("error lanzando navegador: %w", err)
+		}
+		controlURL = u
+		s.launcher = l
+	}
 
-		// Handle rows with 4 columns (2 label-value pairs)
-		if len(cols) == 4 {
-			// First pair
-			headings1 := cols[0].MustElements(".list-group-item-heading")
-			texts1 := cols[1].MustElements(".list-group-item-text")
-
-			if len(headings1) > 0 && len(texts1) > 0 {
-				label1 := strings.TrimSpace(headings1[0].MustText())
-				value1 := strings.TrimSpace(texts1[0].MustText())
-				s.mapFieldToStruct(label1, value1, info)
-			}
-
-			// Second pair
-			headings2 := cols[2].MustElements(".list-group-item-heading")
-			texts2 := cols[3].MustElements(".list-group-item-text")
-
-			if len(headings2) > 0 && len(texts2) > 0 {
-				label2 := strings.TrimSpace(headings2[0].MustText())
-				value2 := strings.TrimSpace(texts2[0].MustText())
-				s.mapFieldToStruct(label2, value2, info)
-			}
+	browser := rod.New().ControlURL(controlURL)
+	if config.SlowMotion > 0 {
+		browser = browser.SlowMotion(config.SlowMotion)
+	}
+	if err := browser.Connect(); err != nil {
+		if s.launcher != nil {
+			s.launcher.Kill()
 		}
+		return nil, fmt.Errorf("error conectando al navegador: %w", err)
 	}
+	s.browser = browser
 
-	return nil
+	return s, nil
 }
 
-func (s *SUNATScraper) mapFieldToStruct(label, value string, info *models.RUCInfo) {
-	label = strings.ToLower(label)
-
-	switch {
-	case strings.Contains(label, "razón social") || strings.Contains(label, "razon social"):
-		info.RazonSocial = value
-	case strings.Contains(label, "tipo contribuyente"):
-		info.TipoContribuyente = value
-	case strings.Contains(label, "tipo de documento"):
-		// Extraer información del documento
-		// Formato esperado: "DNI  76366932  - TARRILLO MARRUFO, YAMELITH MARILYN"
-		s.parseTipoDocumento(value, info)
-	case strings.Contains(label, "nombre comercial"):
-		info.NombreComercial = value
-	case strings.Contains(label, "fecha de inscripción") || strings.Contains(label, "fecha de inscripcion"):
-		info.FechaInscripcion = value
-	case strings.Contains(label, "fecha de inicio de actividades"):
-		info.FechaInicioActividades = value
-	case strings.Contains(label, "estado del contribuyente"):
-		info.Estado = value
-	case strings.Contains(label, "condición del contribuyente") || strings.Contains(label, "condicion del contribuyente"):
-		info.Condicion = value
-	case strings.Contains(label, "domicilio fiscal"):
-		info.DomicilioFiscal = value
-	case strings.Contains(label, "sistema emisión de comprobante") || strings.Contains(label, "sistema emision de comprobante"):
-		info.SistemaEmision = value
-	case strings.Contains(label, "actividad comercio exterior"):
-		info.ActividadComercioExterior = value
-	case strings.Contains(label, "sistema contabilidad"):
-		info.SistemaContabilidad = value
-	case strings.Contains(label, "emisor electrónico desde") || strings.Contains(label, "emisor electronico desde"):
-		info.EmisorElectronicoDesde = value
-	case strings.Contains(label, "comprobantes electrónicos") || strings.Contains(label, "comprobantes electronicos"):
-		// Separar por comas y convertir a array
-		if value != "" {
-			items := strings.Split(value, ",")
-			// Limpiar cada item
-			for i, item := range items {
-				items[i] = strings.TrimSpace(item)
-			}
-			info.ComprobantesElectronicos = items
-		}
-	case strings.Contains(label, "afiliado al ple desde"):
-		info.AfiliadoPLE = value
+// Close cierra el navegador
+func (s *SUNATScraper) Close() error {
+	err := s.browser.Close()
+	if s.launcher != nil {
+		s.launcher.Cleanup()
 	}
+	return err
 }
 
-// Nueva función para parsear el tipo de documento
-func (s *SUNATScraper) parseTipoDocumento(value string, info *models.RUCInfo) {
-	// Limpiar espacios extra
-	cleanValue := strings.ReplaceAll(value, "  ", " ")
-	cleanValue = strings.TrimSpace(cleanValue)
-
-	// Ejemplo: "DNI  76366932 - TARRILLO MARRUFO, YAMELITH MARILYN"
-	if strings.Contains(cleanValue, "DNI") {
-		// Extraer número de DNI y nombre
-		parts := strings.Split(cleanValue, " - ")
-		if len(parts) >= 2 {
-			// Primera parte contiene "DNI" y el número
-			dniPart := strings.TrimSpace(parts[0])
-			dniFields := strings.Fields(dniPart) // Divide por espacios
-
-			if len(dniFields) >= 2 {
-				tipoDoc := dniFields[0]                       // "DNI"
-				numeroDoc := dniFields[1]                     // "76366932"
-				nombreCompleto := strings.TrimSpace(parts[1]) // "TARRILLO MARRUFO, YAMELITH MARILYN"
-
-				// Formatear tipo de documento
-				info.TipoDocumento = fmt.Sprintf("%s %s", tipoDoc, numeroDoc)
-
-				// Si no se ha establecido RazonSocial desde el RUC, usar el nombre del documento
-				if info.RazonSocial == "" {
-					info.RazonSocial = nombreCompleto
-				}
-			}
-		}
-	} else {
-		// Para otros tipos de documento, guardar tal como está
-		info.TipoDocumento = cleanValue
+func (s *SUNATScraper) ScrapeRUC(ctx context.Context, ruc string, page *rod.Page) (*models.RUCInfo, error) {
+	page = page.Context(ctx)
+
+	// Wait for results to load
+	if err := esperar(ctx, 5*time.Second); err != nil {
+		return nil, err
 	}
-}
 
-func (s *SUNATScraper) extractTableData(table *rod.Element, label string, info *models.RUCInfo) {
-	rows := table.MustElements("tr")
-	items := []string{}
+	html, err := htmlPagina(page)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, row := range rows {
-		text := strings.TrimSpace(row.MustText())
-		if text != "" && text != "NINGUNO" {
-			items = append(items, text)
-		}
+	info, err := parser.ParseFicha(html)
+	if errors.Is(err, parser.ErrRUCNoExiste) {
+		return nil, fmt.Errorf("%w: %s", ErrRUCNoEncontrado, ruc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: ficha RUC: %w", ErrParseo, err)
+	}
+
+	if info.RUC == "" {
+		info.RUC = ruc
 	}
 
-	label = strings.ToLower(label)
+	return info, nil
+}
 
-	if strings.Contains(label, "actividad") && strings.Contains(label, "económica") {
-		if len(items) > 0 {
-			info.ActividadesEconomicas = items
-		}
-	} else if strings.Contains(label, "comprobantes de pago") {
-		if len(items) > 0 {
-			info.ComprobantesPago = items
-		}
-	} else if strings.Contains(label, "sistema de emisión electrónica") || strings.Contains(label, "sistema de emision electronica") {
-		if len(items) > 0 {
-			info.SistemaEmisionElectronica = items
-		}
-	} else if strings.Contains(label, "padrones") {
-		if len(items) > 0 {
-			info.Padrones = items
-		}
+// seccionFicha es el nombre con que se reporta la ficha RUC en ScrapeError
+const seccionFicha = "Información General"
+
+// esperar reemplaza a time.Sleep respetando la cancelación de ctx
+func esperar(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// htmlPagina obtiene el HTML actual de la página para entregarlo a pkg/parser
+func htmlPagina(page *rod.Page) (io.Reader, error) {
+	html, err := page.HTML()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener HTML: %w", err)
 	}
+	return strings.NewReader(html), nil
 }
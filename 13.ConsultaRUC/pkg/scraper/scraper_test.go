@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestEsperarCancelado(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	inicio := time.Now()
+	if err := esperar(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, se esperaba context.Canceled", err)
+	}
+	if time.Since(inicio) > time.Second {
+		t.Error("esperar no respetó la cancelación")
+	}
+}
+
+func TestConfigConDefectos(t *testing.T) {
+	// Una configuración armada a mano no queda sin plazo por intento
+	defecto := DefaultConfig()
+	c := ConfigScraper{Headless: true}.conDefectos()
+	if c.Timeout != defecto.Timeout || c.DelayReintentos != defecto.DelayReintentos ||
+		c.BaseURL != defecto.BaseURL || c.MaxReintentos != 1 {
+		t.Errorf("conDefectos = %+v", c)
+	}
+
+	c = ConfigScraper{Timeout: time.Second, MaxReintentos: 5}.conDefectos()
+	if c.Timeout != time.Second || c.MaxReintentos != 5 {
+		t.Errorf("conDefectos reemplazó valores definidos: %+v", c)
+	}
+}
+
+func TestScrapeErrorUnwrap(t *testing.T) {
+	err := error(&ScrapeError{Seccion: "Deuda Coactiva", Intento: 3, Err: context.DeadlineExceeded})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("ScrapeError no expone el error original")
+	}
+	if err.Error() != "Deuda Coactiva (intento 3): context deadline exceeded" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestEstado(t *testing.T) {
+	casos := []struct {
+		err  error
+		want EstadoConsulta
+	}{
+		{nil, EstadoExitoso},
+		{&ScrapeError{Seccion: "Deuda Coactiva", Intento: 3, Err: fmt.Errorf("%w: deuda coactiva", ErrPaginaIncorrecta)}, EstadoFallido},
+		{&ScrapeError{Seccion: "Representantes Legales", Intento: 1, Err: fmt.Errorf("%w: tabla: %w", ErrParseo, errors.New("sin filas"))}, EstadoRevision},
+		{&ScrapeError{Seccion: seccionFicha, Intento: 1, Err: ErrRUCNoEncontrado}, EstadoInexistente},
+		{&ScrapeError{Seccion: seccionFicha, Intento: 1, Err: context.Canceled}, EstadoErrorTerminal},
+		{errors.New("otro"), EstadoFallido},
+	}
+	for _, c := range casos {
+		if got := Estado(c.err); got != c.want {
+			t.Errorf("Estado(%v) = %s, se esperaba %s", c.err, got, c.want)
+		}
+	}
+}
+
+func TestClasificar(t *testing.T) {
+	err := clasificar(fmt.Errorf("error cargando resultados: %w", context.DeadlineExceeded))
+	if !errors.Is(err, ErrTimeoutNavegacion) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout no clasificado: %v", err)
+	}
+
+	err = clasificar(fmt.Errorf("error listando pestañas: %w", io.EOF))
+	if !errors.Is(err, ErrNavegadorCaido) || reintentable(err) {
+		t.Errorf("navegador caído no clasificado: %v", err)
+	}
+
+	// Un error ya clasificado no se vuelve a envolver
+	boton := fmt.Errorf("%w: deuda coactiva: %w", ErrBotonNoDisponible, context.DeadlineExceeded)
+	if clasificar(boton) != boton {
+		t.Error("clasificar reemplazó una categoría existente")
+	}
+}
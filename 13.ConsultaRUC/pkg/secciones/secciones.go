@@ -0,0 +1,214 @@
+// Package secciones mantiene el registro de consultas adicionales de la ficha
+// RUC. Cada sección declara el botón que la abre, los textos que identifican
+// su página, su parser, el campo de RUCCompleto donde se guarda y, de forma
+// opcional, cómo persistirla. El scraper y pkg/database recorren el registro
+// en lugar de conocer cada sección por separado.
+package secciones
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+// ModoPaginacion indica cómo se trata el paginador de la tabla de una sección
+type ModoPaginacion int
+
+const (
+	// SinPaginacion: la consulta no muestra tablas paginadas
+	SinPaginacion ModoPaginacion = iota
+	// DetectarPaginacion: se extrae la primera página y solo se informa si hay más
+	DetectarPaginacion
+	// RecorrerPaginacion: se recorren y fusionan todas las páginas
+	RecorrerPaginacion
+)
+
+// ParserSeccion extrae una página de la sección. previo es el valor acumulado
+// de las páginas anteriores (nil en la primera); retorna el nuevo acumulado y
+// las filas extraídas de esta página.
+type ParserSeccion func(html io.Reader, previo any) (valor any, filas int, err error)
+
+// EscritorSeccion persiste la sección dentro de la transacción de
+// InsertRUCCompleto. consultaID es la fila de ruc_consultas de esta consulta;
+// las filas que se escriban deben llevarla para no mezclarse con otras consultas.
+type EscritorSeccion func(tx *sql.Tx, rucID, consultaID int64, rc *models.RUCCompleto) error
+
+// LectorSeccion es la contraparte de EscritorSeccion: lee las filas que guardó
+// la consulta consultaID y las asigna en rc, como lo haría Asignar. Si la
+// consulta no guardó filas deja rc como está.
+type LectorSeccion func(tx *sql.Tx, consultaID int64, rc *models.RUCCompleto) error
+
+// Seccion declara una consulta adicional
+type Seccion struct {
+	// Nombre es la clave en DeteccionPaginacion/Paginacion y en los logs, p. ej. "Deuda Coactiva"
+	Nombre string
+	// Clave identifica la sección en código y base de datos, p. ej. "deuda_coactiva"
+	Clave string
+	// Alias es el nombre corto para seleccionarla, p. ej. "deuda" en --sections
+	Alias string
+	// Boton es la clase CSS del botón de la ficha que abre la consulta
+	Boton string
+	// Patrones son textos en minúsculas; basta uno en title/h1-h3 para aceptar la página
+	Patrones   []string
+	Paginacion ModoPaginacion
+	Parsear    ParserSeccion
+	// Asignar guarda el valor extraído en su campo de RUCCompleto
+	Asignar func(rc *models.RUCCompleto, valor any)
+	// Escritor y Lector son opcionales y van juntos; las secciones de SUNAT se
+	// guardan y se leen con las tablas de pkg/database
+	Escritor EscritorSeccion
+	Lector   LectorSeccion
+}
+
+var (
+	mu       sync.RWMutex
+	registro []Seccion
+)
+
+// Registrar agrega una sección al final del registro. Entra en pánico si la
+// sección está incompleta o si su clave o botón ya están registrados.
+func Registrar(sec Seccion) {
+	if sec.Nombre == "" || sec.Clave == "" || sec.Boton == "" {
+		panic("secciones: Nombre, Clave y Boton son obligatorios")
+	}
+	if sec.Parsear == nil || sec.Asignar == nil {
+		panic(fmt.Sprintf("secciones: %s sin Parsear o Asignar", sec.Clave))
+	}
+	if (sec.Escritor == nil) != (sec.Lector == nil) {
+		panic(fmt.Sprintf("secciones: %s necesita Escritor y Lector, o ninguno", sec.Clave))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, s := range registro {
+		if s.Clave == sec.Clave || s.Boton == sec.Boton || s.Nombre == sec.Nombre ||
+			(sec.Alias != "" && s.Alias == sec.Alias) {
+			panic(fmt.Sprintf("secciones: %s ya está registrada", sec.Clave))
+		}
+	}
+	registro = append(registro, sec)
+}
+
+// Todas retorna las secciones en orden de registro
+func Todas() []Seccion {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Seccion(nil), registro...)
+}
+
+// Buscar retorna la sección con la clave indicada
+func Buscar(clave string) (Seccion, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, s := range registro {
+		if s.Clave == clave {
+			return s, true
+		}
+	}
+	return Seccion{}, false
+}
+
+// Basica es el alias de la ficha RUC, que siempre se consulta porque de ella
+// salen los botones de las demás secciones
+const Basica = "basic"
+
+// Seleccionar resuelve nombres de sección (clave o alias, sin distinguir
+// mayúsculas) a secciones registradas. "basic" se acepta y se ignora. Una lista
+// vacía selecciona todas.
+func Seleccionar(nombres []string) ([]Seccion, error) {
+	todas := Todas()
+	if len(nombres) == 0 {
+		return todas, nil
+	}
+
+	elegidas := make(map[string]bool)
+	for _, nombre := range nombres {
+		nombre = strings.ToLower(strings.TrimSpace(nombre))
+		if nombre == "" || nombre == Basica || nombre == "ficha" {
+			continue
+		}
+
+		encontrada := false
+		for _, sec := range todas {
+			if nombre == sec.Clave || (sec.Alias != "" && nombre == sec.Alias) {
+				elegidas[sec.Clave] = true
+				encontrada = true
+				break
+			}
+		}
+		if !encontrada {
+			return nil, fmt.Errorf("sección desconocida %q (válidas: %s)", nombre, strings.Join(Nombres(), ", "))
+		}
+	}
+
+	// Se conserva el orden del registro, no el de la lista
+	var seleccion []Seccion
+	for _, sec := range todas {
+		if elegidas[sec.Clave] {
+			seleccion = append(seleccion, sec)
+		}
+	}
+	return seleccion, nil
+}
+
+// Nombres retorna "basic" y el alias (o la clave) de cada sección registrada
+func Nombres() []string {
+	nombres := []string{Basica}
+	for _, sec := range Todas() {
+		if sec.Alias != "" {
+			nombres = append(nombres, sec.Alias)
+		} else {
+			nombres = append(nombres, sec.Clave)
+		}
+	}
+	return nombres
+}
+
+// Tabla arma el ParserSeccion de una sección paginada: la primera página se
+// toma tal cual y las siguientes se fusionan sobre ella.
+func Tabla[T any](parse func(io.Reader) (*T, error), filas func(*T) int, fusionar func(acumulado, pagina *T)) ParserSeccion {
+	return func(html io.Reader, previo any) (any, int, error) {
+		pagina, err := parse(html)
+		if err != nil {
+			return nil, 0, err
+		}
+		if acumulado, ok := previo.(*T); ok && acumulado != nil {
+			fusionar(acumulado, pagina)
+			return acumulado, filas(pagina), nil
+		}
+		return pagina, filas(pagina), nil
+	}
+}
+
+// Unica arma el ParserSeccion de una sección de una sola página
+func Unica[T any](parse func(io.Reader) (*T, error)) ParserSeccion {
+	return func(html io.Reader, previo any) (any, int, error) {
+		valor, err := parse(html)
+		if err != nil {
+			return nil, 0, err
+		}
+		return valor, 0, nil
+	}
+}
+
+// Campo arma la función Asignar a partir del puntero al campo de RUCCompleto
+func Campo[T any](campo func(rc *models.RUCCompleto) **T) func(*models.RUCCompleto, any) {
+	return func(rc *models.RUCCompleto, valor any) {
+		*campo(rc), _ = valor.(*T)
+	}
+}
+
+// Adicional guarda el valor en RUCCompleto.Adicionales[clave]; pensado para
+// secciones propias que no tienen un campo en el modelo.
+func Adicional(clave string) func(*models.RUCCompleto, any) {
+	return func(rc *models.RUCCompleto, valor any) {
+		if rc.Adicionales == nil {
+			rc.Adicionales = make(map[string]any)
+		}
+		rc.Adicionales[clave] = valor
+	}
+}
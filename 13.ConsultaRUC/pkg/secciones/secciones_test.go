@@ -0,0 +1,106 @@
+package secciones
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/consulta-ruc-scraper/pkg/models"
+)
+
+func TestRegistroSUNAT(t *testing.T) {
+	todas := Todas()
+	if len(todas) != 10 {
+		t.Fatalf("%d secciones registradas, se esperaban 10", len(todas))
+	}
+	if todas[0].Clave != "informacion_historica" || todas[9].Clave != "garantias_covid19" {
+		t.Errorf("orden inesperado: %s ... %s", todas[0].Clave, todas[9].Clave)
+	}
+	for _, sec := range todas {
+		if len(sec.Patrones) == 0 {
+			t.Errorf("%s sin patrones", sec.Clave)
+		}
+	}
+
+	if _, ok := Buscar("deuda_coactiva"); !ok {
+		t.Error("Buscar(deuda_coactiva) no encontró la sección")
+	}
+}
+
+func TestRegistrarDuplicada(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Registrar aceptó una clave duplicada")
+		}
+	}()
+
+	sec, _ := Buscar("deuda_coactiva")
+	Registrar(sec)
+}
+
+func TestRegistrarEscritorSinLector(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Registrar aceptó un Escritor sin Lector")
+		}
+	}()
+
+	sec, _ := Buscar("deuda_coactiva")
+	sec.Nombre, sec.Clave, sec.Alias, sec.Boton = "Sin Lector", "sin_lector", "", "btnSinLector"
+	sec.Escritor = func(*sql.Tx, int64, int64, *models.RUCCompleto) error { return nil }
+	Registrar(sec)
+}
+
+// TestParsearPaginas fusiona las dos páginas guardadas de deuda coactiva
+func TestParsearPaginas(t *testing.T) {
+	sec, _ := Buscar("deuda_coactiva")
+
+	var valor any
+	filas := 0
+	for _, archivo := range []string{"deuda.html", "deuda_2.html"} {
+		html, err := os.ReadFile("../parser/testdata/paginado/" + archivo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		valor, n, err = sec.Parsear(bytes.NewReader(html), valor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filas += n
+	}
+
+	rc := &models.RUCCompleto{}
+	sec.Asignar(rc, valor)
+	if rc.DeudaCoactiva == nil || rc.DeudaCoactiva.CantidadDocumentos != 5 || filas != 5 {
+		t.Errorf("DeudaCoactiva = %+v, filas = %d", rc.DeudaCoactiva, filas)
+	}
+}
+
+func TestAdicional(t *testing.T) {
+	rc := &models.RUCCompleto{}
+	Adicional("propia")(rc, "valor")
+
+	if rc.Adicionales["propia"] != "valor" {
+		t.Errorf("Adicionales = %v", rc.Adicionales)
+	}
+}
+
+func TestSeleccionar(t *testing.T) {
+	sel, err := Seleccionar([]string{"basic", "Representantes", "deuda_coactiva"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sel) != 2 || sel[0].Clave != "deuda_coactiva" || sel[1].Clave != "representantes_legales" {
+		t.Errorf("selección = %v", sel)
+	}
+
+	if todas, _ := Seleccionar(nil); len(todas) != len(Todas()) {
+		t.Errorf("Seleccionar(nil) = %d secciones", len(todas))
+	}
+
+	if _, err := Seleccionar([]string{"deudas"}); err == nil {
+		t.Error("se aceptó una sección desconocida")
+	}
+}
@@ -0,0 +1,179 @@
+package secciones
+
+import (
+	"github.com/consulta-ruc-scraper/pkg/models"
+	"github.com/consulta-ruc-scraper/pkg/parser"
+)
+
+// Consultas adicionales de SUNAT, en el orden en que se consultan
+func init() {
+	Registrar(Seccion{
+		Nombre: "Información Histórica",
+		Clave:  "informacion_historica",
+		Alias:  "historica",
+		Boton:  "btnInfHis",
+		Patrones: []string{
+			"información histórica de",
+			"informacion historica de",
+		},
+		Paginacion: DetectarPaginacion,
+		Parsear:    Unica(parser.ParseInformacionHistorica),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.InformacionHistorica {
+			return &rc.InformacionHistorica
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Deuda Coactiva",
+		Clave:  "deuda_coactiva",
+		Alias:  "deuda",
+		Boton:  "btnInfDeuCoa",
+		Patrones: []string{
+			"deuda coactiva remitida a centrales de riesgo de",
+			"deuda coactiva",
+		},
+		Paginacion: RecorrerPaginacion,
+		Parsear: Tabla(parser.ParseDeudaCoactiva,
+			func(d *models.DeudaCoactiva) int { return len(d.Deudas) },
+			(*models.DeudaCoactiva).Fusionar),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.DeudaCoactiva {
+			return &rc.DeudaCoactiva
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Omisiones Tributarias",
+		Clave:  "omisiones_tributarias",
+		Alias:  "omisiones",
+		Boton:  "btnInfOmiTri",
+		Patrones: []string{
+			"omisiones tributarias de",
+			"omisiones tributarias",
+		},
+		Paginacion: RecorrerPaginacion,
+		Parsear: Tabla(parser.ParseOmisionesTributarias,
+			func(o *models.OmisionesTributarias) int { return len(o.Omisiones) },
+			(*models.OmisionesTributarias).Fusionar),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.OmisionesTributarias {
+			return &rc.OmisionesTributarias
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Cantidad de Trabajadores",
+		Clave:  "cantidad_trabajadores",
+		Alias:  "trabajadores",
+		Boton:  "btnInfNumTra",
+		Patrones: []string{
+			"cantidad de trabajadores",
+			"número de trabajadores",
+			"numero de trabajadores",
+		},
+		Paginacion: RecorrerPaginacion,
+		Parsear: Tabla(parser.ParseCantidadTrabajadores,
+			func(c *models.CantidadTrabajadores) int { return len(c.DetallePorPeriodo) },
+			(*models.CantidadTrabajadores).Fusionar),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.CantidadTrabajadores {
+			return &rc.CantidadTrabajadores
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Actas Probatorias",
+		Clave:  "actas_probatorias",
+		Alias:  "actas",
+		Boton:  "btnInfActPro",
+		Patrones: []string{
+			"actas probatorias de",
+			"actas probatorias",
+		},
+		Paginacion: DetectarPaginacion,
+		Parsear:    Unica(parser.ParseActasProbatorias),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.ActasProbatorias {
+			return &rc.ActasProbatorias
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Facturas Físicas",
+		Clave:  "facturas_fisicas",
+		Alias:  "facturas",
+		Boton:  "btnInfActCPF",
+		Patrones: []string{
+			"facturas físicas de",
+			"facturas fisicas de",
+		},
+		Paginacion: DetectarPaginacion,
+		Parsear:    Unica(parser.ParseFacturasFisicas),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.FacturasFisicas {
+			return &rc.FacturasFisicas
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Representantes Legales",
+		Clave:  "representantes_legales",
+		Alias:  "representantes",
+		Boton:  "btnInfRepLeg",
+		Patrones: []string{
+			"representantes legales de",
+		},
+		Paginacion: RecorrerPaginacion,
+		Parsear: Tabla(parser.ParseRepresentantesLegales,
+			func(r *models.RepresentantesLegales) int { return len(r.Representantes) },
+			(*models.RepresentantesLegales).Fusionar),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.RepresentantesLegales {
+			return &rc.RepresentantesLegales
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Establecimientos Anexos",
+		Clave:  "establecimientos_anexos",
+		Alias:  "anexos",
+		Boton:  "btnInfLocAnex",
+		Patrones: []string{
+			"establecimientos anexos de",
+		},
+		Paginacion: RecorrerPaginacion,
+		Parsear: Tabla(parser.ParseEstablecimientosAnexos,
+			func(e *models.EstablecimientosAnexos) int { return len(e.Establecimientos) },
+			(*models.EstablecimientosAnexos).Fusionar),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.EstablecimientosAnexos {
+			return &rc.EstablecimientosAnexos
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Reactiva Perú",
+		Clave:  "reactiva_peru",
+		Alias:  "reactiva",
+		Boton:  "btnInfReaPer",
+		Patrones: []string{
+			"reactiva perú de",
+			"reactiva peru de",
+			"programa reactiva",
+		},
+		Parsear: Unica(parser.ParseReactivaPeru),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.ReactivaPeru {
+			return &rc.ReactivaPeru
+		}),
+	})
+
+	Registrar(Seccion{
+		Nombre: "Programa COVID-19",
+		Clave:  "garantias_covid19",
+		Alias:  "covid",
+		Boton:  "btnInfCovid",
+		Patrones: []string{
+			"programa de garantías covid",
+			"programa de garantias covid",
+			"garantías covid-19",
+			"garantias covid-19",
+		},
+		Parsear: Unica(parser.ParseProgramaCovid19),
+		Asignar: Campo(func(rc *models.RUCCompleto) **models.ProgramaCovid19 {
+			return &rc.ProgramaCovid19
+		}),
+	})
+}
@@ -0,0 +1,396 @@
+// Package sunatmock implementa un servidor HTTP que reproduce el flujo de la
+// consulta RUC de SUNAT a partir de páginas HTML guardadas, para ejecutar el
+// scraper sin conexión (pruebas, desarrollo local).
+//
+// Cada RUC se configura con un Fixture: la ficha de resultados y, por cada
+// consulta adicional, una o más páginas HTML. Los fixtures se cargan desde un
+// directorio con la misma estructura que pkg/parser/testdata:
+//
+//	<caso>/ficha.html
+//	<caso>/deuda.html
+//	<caso>/deuda_2.html   (segunda página de la tabla, opcional)
+//	...
+package sunatmock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/consulta-ruc-scraper/pkg/parser"
+)
+
+const (
+	// RutaFormulario es la ruta del formulario de búsqueda (FrameCriterioBusquedaWeb.jsp)
+	RutaFormulario = "/cl-ti-itmrconsruc/FrameCriterioBusquedaWeb.jsp"
+	// RutaConsulta es la ruta que atiende los resultados y las consultas adicionales
+	RutaConsulta = "/cl-ti-itmrconsruc/jcrS00Alias"
+
+	accionConsulta = "consPorRuc"
+)
+
+// Seccion describe una consulta adicional: el botón que la abre en la ficha
+// y el nombre base de su archivo en el directorio de fixtures
+type Seccion struct {
+	Boton   string
+	Archivo string
+}
+
+// Secciones lista las consultas adicionales que sirve el mock
+var Secciones = []Seccion{
+	{"btnInfHis", "historica"},
+	{"btnInfDeuCoa", "deuda"},
+	{"btnInfOmiTri", "omisiones"},
+	{"btnInfNumTra", "trabajadores"},
+	{"btnInfActPro", "actas"},
+	{"btnInfActCPF", "facturas"},
+	{"btnInfReaPer", "reactiva"},
+	{"btnInfCovid", "covid"},
+	{"btnInfRepLeg", "representantes"},
+	{"btnInfLocAnex", "anexos"},
+}
+
+// accion devuelve el valor del parámetro accion para el botón de una sección
+func accion(boton string) string {
+	return "get" + strings.TrimPrefix(boton, "btn")
+}
+
+// Fixture contiene las páginas de un RUC
+type Fixture struct {
+	RUC   string
+	Ficha []byte
+	// Paginas por clase de botón; una entrada por página de la tabla
+	Paginas map[string][][]byte
+}
+
+// CargarFixture lee un directorio de fixtures. El RUC se obtiene de la ficha.
+func CargarFixture(dir string) (*Fixture, error) {
+	ficha, err := os.ReadFile(filepath.Join(dir, "ficha.html"))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo ficha: %w", err)
+	}
+
+	info, err := parser.ParseFicha(bytes.NewReader(ficha))
+	if err != nil {
+		return nil, fmt.Errorf("error parseando ficha de %s: %w", dir, err)
+	}
+
+	f := &Fixture{
+		RUC:     info.RUC,
+		Ficha:   ficha,
+		Paginas: make(map[string][][]byte),
+	}
+
+	for _, s := range Secciones {
+		for n := 1; ; n++ {
+			nombre := s.Archivo + ".html"
+			if n > 1 {
+				nombre = fmt.Sprintf("%s_%d.html", s.Archivo, n)
+			}
+
+			pagina, err := os.ReadFile(filepath.Join(dir, nombre))
+			if errors.Is(err, os.ErrNotExist) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("error leyendo %s: %w", nombre, err)
+			}
+			f.Paginas[s.Boton] = append(f.Paginas[s.Boton], pagina)
+		}
+	}
+
+	return f, nil
+}
+
+// CargarFixtures carga cada subdirectorio de dir que contenga una ficha.html
+func CargarFixtures(dir string) ([]*Fixture, error) {
+	entradas, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*Fixture
+	for _, e := range entradas {
+		if !e.IsDir() {
+			continue
+		}
+		sub := filepath.Join(dir, e.Name())
+		if _, err := os.Stat(filepath.Join(sub, "ficha.html")); err != nil {
+			continue
+		}
+		f, err := CargarFixture(sub)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// Falla indica qué página de error devuelve una sección
+type Falla int
+
+const (
+	// FallaAplicacion es la página "La aplicación ha retornado el siguiente problema"
+	FallaAplicacion Falla = iota
+	// FallaURLRechazada es la página "The requested URL was rejected"
+	FallaURLRechazada
+)
+
+type fallaPendiente struct {
+	falla Falla
+	veces int
+}
+
+// Mock es el http.Handler que imita la consulta RUC
+type Mock struct {
+	mu       sync.Mutex
+	fixtures map[string]*Fixture
+	fallas   map[string]*fallaPendiente
+}
+
+// New crea un mock con los fixtures indicados
+func New(fixtures ...*Fixture) *Mock {
+	m := &Mock{
+		fixtures: make(map[string]*Fixture),
+		fallas:   make(map[string]*fallaPendiente),
+	}
+	for _, f := range fixtures {
+		m.Agregar(f)
+	}
+	return m
+}
+
+// NewServer inicia un httptest.Server con el mock. La URL base para el
+// scraper es URLFormulario(srv.URL).
+func NewServer(fixtures ...*Fixture) (*httptest.Server, *Mock) {
+	m := New(fixtures...)
+	return httptest.NewServer(m), m
+}
+
+// URLFormulario devuelve la URL del formulario de búsqueda para un servidor
+func URLFormulario(base string) string {
+	return strings.TrimSuffix(base, "/") + RutaFormulario
+}
+
+// Agregar registra o reemplaza el fixture de un RUC
+func (m *Mock) Agregar(f *Fixture) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fixtures[f.RUC] = f
+}
+
+// FallarSeccion hace que las próximas `veces` solicitudes a la sección del
+// botón indicado respondan con una página de error en lugar del fixture
+func (m *Mock) FallarSeccion(ruc, boton string, falla Falla, veces int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallas[ruc+"/"+boton] = &fallaPendiente{falla: falla, veces: veces}
+}
+
+// consumirFalla indica si la solicitud actual debe fallar
+func (m *Mock) consumirFalla(ruc, boton string) (Falla, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.fallas[ruc+"/"+boton]
+	if !ok || p.veces <= 0 {
+		return 0, false
+	}
+	p.veces--
+	return p.falla, true
+}
+
+func (m *Mock) fixture(ruc string) (*Fixture, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.fixtures[ruc]
+	return f, ok
+}
+
+// ServeHTTP atiende el formulario, los resultados y las consultas adicionales
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case RutaFormulario, "/":
+		escribir(w, http.StatusOK, paginaFormulario)
+	case RutaConsulta:
+		m.consulta(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *Mock) consulta(w http.ResponseWriter, r *http.Request) {
+	ruc := strings.TrimSpace(r.FormValue("nroRuc"))
+	acc := r.FormValue("accion")
+
+	f, ok := m.fixture(ruc)
+	if !ok {
+		escribir(w, http.StatusOK, fmt.Sprintf(paginaRUCInexistente, html.EscapeString(ruc)))
+		return
+	}
+
+	if acc == accionConsulta {
+		escribir(w, http.StatusOK, inyectarScript(f.Ficha, scriptFicha(ruc)))
+		return
+	}
+
+	for _, s := range Secciones {
+		if accion(s.Boton) != acc {
+			continue
+		}
+
+		if falla, ok := m.consumirFalla(ruc, s.Boton); ok {
+			if falla == FallaURLRechazada {
+				escribir(w, http.StatusOK, paginaURLRechazada)
+			} else {
+				escribir(w, http.StatusOK, paginaErrorAplicacion)
+			}
+			return
+		}
+
+		paginas := f.Paginas[s.Boton]
+		n, _ := strconv.Atoi(r.FormValue("pagina"))
+		if n < 1 {
+			n = 1
+		}
+		if n > len(paginas) {
+			escribir(w, http.StatusOK, paginaErrorAplicacion)
+			return
+		}
+
+		escribir(w, http.StatusOK, inyectarScript(paginas[n-1], scriptSeccion(ruc, acc)))
+		return
+	}
+
+	escribir(w, http.StatusOK, paginaErrorAplicacion)
+}
+
+func escribir(w http.ResponseWriter, status int, cuerpo string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprint(w, cuerpo)
+}
+
+// inyectarScript agrega el JavaScript de navegación antes de </body>, de modo
+// que los fixtures se mantienen idénticos a las páginas guardadas de SUNAT
+func inyectarScript(pagina []byte, script string) string {
+	s := string(pagina)
+	if i := strings.LastIndex(s, "</body>"); i >= 0 {
+		return s[:i] + script + s[i:]
+	}
+	return s + script
+}
+
+func scriptFicha(ruc string) string {
+	var acciones []string
+	for _, s := range Secciones {
+		acciones = append(acciones, fmt.Sprintf("%q: %q", s.Boton, accion(s.Boton)))
+	}
+
+	return fmt.Sprintf(`<script>
+(function () {
+  var ruc = %q;
+  var acciones = {%s};
+  Object.keys(acciones).forEach(function (clase) {
+    document.querySelectorAll("button." + clase).forEach(function (b) {
+      b.addEventListener("click", function () {
+        location.href = "jcrS00Alias?accion=" + acciones[clase] + "&nroRuc=" + ruc;
+      });
+    });
+  });
+  document.querySelectorAll("button.btnNuevaConsulta").forEach(function (b) {
+    b.addEventListener("click", function () { location.href = "FrameCriterioBusquedaWeb.jsp"; });
+  });
+})();
+</script>
+`, ruc, strings.Join(acciones, ", "))
+}
+
+func scriptSeccion(ruc, acc string) string {
+	return fmt.Sprintf(`<script>
+function paginacion(n) {
+  location.href = "jcrS00Alias?accion=%[2]s&nroRuc=%[1]s&pagina=" + n;
+}
+document.querySelectorAll("button.btnNuevaConsulta").forEach(function (b) {
+  b.addEventListener("click", function () {
+    location.href = "jcrS00Alias?accion=%[3]s&nroRuc=%[1]s";
+  });
+});
+</script>
+`, ruc, acc, accionConsulta)
+}
+
+const paginaFormulario = `<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>Consulta RUC</title>
+</head>
+<body>
+  <div class="container">
+    <div class="panel panel-primary">
+      <div class="panel-heading">Consulta RUC</div>
+      <div class="panel-body">
+        <form id="form01" method="get" action="jcrS00Alias">
+          <input type="hidden" name="accion" value="consPorRuc">
+          <label for="txtRuc">Por RUC</label>
+          <input type="text" id="txtRuc" name="nroRuc" class="form-control" maxlength="11">
+          <button type="submit" id="btnAceptar" class="btn btn-primary">Buscar</button>
+        </form>
+      </div>
+    </div>
+  </div>
+</body>
+</html>
+`
+
+const paginaRUCInexistente = `<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>Consulta RUC</title>
+</head>
+<body>
+  <div class="container">
+    <div class="panel panel-primary">
+      <div class="panel-heading">Resultado de la Búsqueda</div>
+      <div class="panel-body">
+        <div class="alert alert-danger">El número de RUC %s consultado no existe.</div>
+      </div>
+      <div class="panel-footer text-center">
+        <button type="button" class="btn btn-danger btnNuevaConsulta" onclick="location.href='FrameCriterioBusquedaWeb.jsp'">Volver</button>
+      </div>
+    </div>
+  </div>
+</body>
+</html>
+`
+
+const paginaErrorAplicacion = `<html>
+<head><title>Error</title></head>
+<body>
+  <p class="error">La aplicación ha retornado el siguiente problema:</p>
+  <p>Error al procesar la consulta. Por favor intente nuevamente.</p>
+  <input class="form-button" type="button" value="Anterior" onclick="history.go(-1)">
+</body>
+</html>
+`
+
+const paginaURLRechazada = `<html>
+<head><title>Request Rejected</title></head>
+<body>
+  The requested URL was rejected. Please consult with your administrator.<br><br>
+  Your support ID is: 0000000000000000000<br><br>
+  <a href="javascript:history.back();">[Go Back]</a>
+</body>
+</html>
+`
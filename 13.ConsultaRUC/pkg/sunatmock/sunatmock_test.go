@@ -0,0 +1,142 @@
+package sunatmock
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/consulta-ruc-scraper/pkg/parser"
+)
+
+const dirFixtures = "../parser/testdata"
+
+func iniciar(t *testing.T) (string, *Mock) {
+	t.Helper()
+
+	fixtures, err := CargarFixtures(dirFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, m := NewServer(fixtures...)
+	t.Cleanup(srv.Close)
+	return srv.URL, m
+}
+
+func obtener(t *testing.T, base, ruta string, params url.Values) string {
+	t.Helper()
+
+	resp, err := http.Get(base + ruta + "?" + params.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	cuerpo, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", ruta, resp.StatusCode)
+	}
+	return string(cuerpo)
+}
+
+func consulta(accion, ruc string, extra ...string) url.Values {
+	v := url.Values{"accion": {accion}, "nroRuc": {ruc}}
+	for i := 0; i+1 < len(extra); i += 2 {
+		v.Set(extra[i], extra[i+1])
+	}
+	return v
+}
+
+func TestFormulario(t *testing.T) {
+	base, _ := iniciar(t)
+
+	html := obtener(t, base, RutaFormulario, nil)
+	for _, id := range []string{`id="txtRuc"`, `id="btnAceptar"`} {
+		if !strings.Contains(html, id) {
+			t.Errorf("el formulario no contiene %s", id)
+		}
+	}
+}
+
+func TestFichaYSecciones(t *testing.T) {
+	base, _ := iniciar(t)
+
+	html := obtener(t, base, RutaConsulta, consulta("consPorRuc", "20606316977"))
+	info, err := parser.ParseFicha(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.RUC != "20606316977" {
+		t.Errorf("RUC = %q", info.RUC)
+	}
+	if !strings.Contains(html, "btnInfDeuCoa") || !strings.Contains(html, "jcrS00Alias?accion=") {
+		t.Error("la ficha no incluye los botones de consultas adicionales")
+	}
+
+	html = obtener(t, base, RutaConsulta, consulta(accion("btnInfDeuCoa"), "20606316977"))
+	deuda, err := parser.ParseDeudaCoactiva(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deuda.CantidadDocumentos != 3 {
+		t.Errorf("CantidadDocumentos = %d, se esperaba 3", deuda.CantidadDocumentos)
+	}
+	if !strings.Contains(html, "btnNuevaConsulta") {
+		t.Error("la sección no incluye el botón volver")
+	}
+}
+
+func TestPaginacion(t *testing.T) {
+	base, _ := iniciar(t)
+
+	html := obtener(t, base, RutaConsulta, consulta(accion("btnInfDeuCoa"), "20100070970", "pagina", "2"))
+	if !strings.Contains(html, "4 a 5 de 5") {
+		t.Error("la página 2 no contiene el contador esperado")
+	}
+	if !strings.Contains(html, "function paginacion") {
+		t.Error("falta la función paginacion")
+	}
+
+	html = obtener(t, base, RutaConsulta, consulta(accion("btnInfDeuCoa"), "20100070970", "pagina", "9"))
+	if !strings.Contains(html, "La aplicación ha retornado el siguiente problema") {
+		t.Error("una página inexistente debería devolver la página de error")
+	}
+}
+
+func TestFallarSeccion(t *testing.T) {
+	base, m := iniciar(t)
+	m.FallarSeccion("20606316977", "btnInfHis", FallaURLRechazada, 1)
+
+	params := consulta(accion("btnInfHis"), "20606316977")
+	if html := obtener(t, base, RutaConsulta, params); !strings.Contains(html, "The requested URL was rejected") {
+		t.Error("la primera solicitud debería ser rechazada")
+	}
+	if html := obtener(t, base, RutaConsulta, params); !strings.Contains(html, "Información Histórica de") {
+		t.Error("la segunda solicitud debería devolver el fixture")
+	}
+}
+
+func TestRUCDesconocido(t *testing.T) {
+	base, _ := iniciar(t)
+
+	html := obtener(t, base, RutaConsulta, consulta("consPorRuc", "20000000001"))
+	if _, err := parser.ParseFicha(strings.NewReader(html)); err == nil {
+		t.Error("un RUC sin fixture no debería devolver una ficha")
+	}
+}
+
+func TestInyectarScript(t *testing.T) {
+	pagina := []byte("<html><body><p>x</p></body></html>")
+	got := inyectarScript(pagina, "<script></script>")
+	if want := "<html><body><p>x</p><script></script></body></html>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !bytes.Equal(pagina, []byte("<html><body><p>x</p></body></html>")) {
+		t.Error("inyectarScript no debe modificar el fixture")
+	}
+}
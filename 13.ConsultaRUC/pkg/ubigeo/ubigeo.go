@@ -0,0 +1,187 @@
+// Package ubigeo contiene el catálogo de ubicaciones geográficas del INEI
+// (departamento, provincia y distrito) y resuelve los nombres que muestra SUNAT
+// a su código de 6 dígitos, el mismo de empresas_sunat.ubigeo.
+//
+// Los datos van embebidos en datos/ubigeo.tsv (código<TAB>nombre). El nivel
+// se deduce del largo del código: 2 dígitos el departamento, 4 la provincia y
+// 6 el distrito; cada entrada aparece después de la que la contiene.
+//
+// El archivo es el listado completo del INEI: 25 departamentos, 196 provincias
+// y 1874 distritos. Los códigos de distrito no siempre son correlativos: los
+// que pasaron a otra provincia dejaron su número libre (Huancayo, Otuzco,
+// Maynas, Piura, ...).
+package ubigeo
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//go:embed datos/ubigeo.tsv
+var datos embed.FS
+
+// Nivel es la posición de la ubicación en la jerarquía
+type Nivel int
+
+const (
+	NivelDepartamento Nivel = iota + 1 // 2 dígitos, p. ej. "15"
+	NivelProvincia                     // 4 dígitos, p. ej. "1501"
+	NivelDistrito                      // 6 dígitos, p. ej. "150114"
+)
+
+// ErrUbigeoDesconocido indica un código o nombre que no está en el catálogo
+var ErrUbigeoDesconocido = errors.New("ubigeo desconocido")
+
+// Lugar es una entrada del catálogo
+type Lugar struct {
+	Codigo string
+	Nombre string
+	Nivel  Nivel
+}
+
+// String retorna "150114 - LA MOLINA"
+func (l Lugar) String() string {
+	return l.Codigo + " - " + l.Nombre
+}
+
+// alias son los nombres que SUNAT usa además del oficial del INEI
+var alias = map[string]string{
+	"prov. const. del callao":             "callao",
+	"provincia constitucional del callao": "callao",
+	"nazca":                               "nasca",
+	"cercado de lima":                     "lima",
+}
+
+var (
+	cargarUnaVez sync.Once
+	lugares      map[string]Lugar
+	porNombre    map[string]string // código del padre + "/" + nombre simplificado → código
+)
+
+// cargar lee el catálogo embebido la primera vez que se usa. Un archivo mal
+// formado es un error de programación, por eso termina en panic.
+func cargar() {
+	cargarUnaVez.Do(func() {
+		if err := leerCatalogo(); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func leerCatalogo() error {
+	const nombre = "datos/ubigeo.tsv"
+	f, err := datos.Open(nombre)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	lugares = map[string]Lugar{}
+	porNombre = map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		linea := strings.TrimSpace(scanner.Text())
+		if linea == "" {
+			continue
+		}
+		codigo, nombreLugar, ok := strings.Cut(linea, "\t")
+		if !ok {
+			return fmt.Errorf("%s:%d: falta el tabulador", nombre, n)
+		}
+		l := Lugar{Codigo: strings.TrimSpace(codigo), Nombre: strings.TrimSpace(nombreLugar)}
+		switch len(l.Codigo) {
+		case 2:
+			l.Nivel = NivelDepartamento
+		case 4:
+			l.Nivel = NivelProvincia
+		case 6:
+			l.Nivel = NivelDistrito
+		}
+		if l.Nivel == 0 || !soloDigitos(l.Codigo) {
+			return fmt.Errorf("%s:%d: código mal formado %q", nombre, n, l.Codigo)
+		}
+		padre := l.Codigo[:len(l.Codigo)-2]
+		if _, ok := lugares[padre]; padre != "" && !ok {
+			return fmt.Errorf("%s:%d: %s aparece antes de %s", nombre, n, l.Codigo, padre)
+		}
+		if _, repetido := lugares[l.Codigo]; repetido {
+			return fmt.Errorf("%s:%d: código repetido %s", nombre, n, l.Codigo)
+		}
+		clave := padre + "/" + simplificar(l.Nombre)
+		if otro, repetido := porNombre[clave]; repetido {
+			return fmt.Errorf("%s:%d: %s tiene el mismo nombre que %s", nombre, n, l.Codigo, otro)
+		}
+		lugares[l.Codigo] = l
+		porNombre[clave] = l.Codigo
+	}
+	return scanner.Err()
+}
+
+// Obtener busca un departamento, provincia o distrito por código
+func Obtener(codigo string) (Lugar, error) {
+	cargar()
+	l, ok := lugares[strings.TrimSpace(codigo)]
+	if !ok {
+		return Lugar{}, fmt.Errorf("%q: %w", codigo, ErrUbigeoDesconocido)
+	}
+	return l, nil
+}
+
+// Departamento busca un departamento por nombre, sin distinguir mayúsculas ni tildes
+func Departamento(nombre string) (Lugar, error) {
+	cargar()
+	return hijo("", nombre)
+}
+
+// Buscar resuelve departamento, provincia y distrito (nombres como los muestra
+// SUNAT) al distrito del catálogo. Si alguno no está, el error indica cuál.
+func Buscar(departamento, provincia, distrito string) (Lugar, error) {
+	cargar()
+	padre := ""
+	var l Lugar
+	for _, nombre := range []string{departamento, provincia, distrito} {
+		var err error
+		if l, err = hijo(padre, nombre); err != nil {
+			return Lugar{}, err
+		}
+		padre = l.Codigo
+	}
+	return l, nil
+}
+
+func hijo(padre, nombre string) (Lugar, error) {
+	clave := simplificar(nombre)
+	if oficial, ok := alias[clave]; ok {
+		clave = oficial
+	}
+	codigo, ok := porNombre[padre+"/"+clave]
+	if !ok {
+		if padre != "" {
+			return Lugar{}, fmt.Errorf("%q en %s: %w", nombre, lugares[padre].Nombre, ErrUbigeoDesconocido)
+		}
+		return Lugar{}, fmt.Errorf("%q: %w", nombre, ErrUbigeoDesconocido)
+	}
+	return lugares[codigo], nil
+}
+
+var sinTildes = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+
+// simplificar pasa a minúsculas, quita las tildes y une los espacios
+// ("Jesús  María" → "jesus maria"). La ñ se compara como n porque SUNAT a
+// veces la pierde ("FERRENAFE").
+func simplificar(s string) string {
+	return strings.Join(strings.Fields(sinTildes.Replace(strings.ToLower(s))), " ")
+}
+
+func soloDigitos(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
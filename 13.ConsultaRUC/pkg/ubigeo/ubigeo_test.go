@@ -0,0 +1,68 @@
+package ubigeo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCatalogoCompleto(t *testing.T) {
+	cargar()
+	cantidad := map[Nivel]int{}
+	for _, l := range lugares {
+		cantidad[l.Nivel]++
+	}
+	if cantidad[NivelDepartamento] != 25 || cantidad[NivelProvincia] != 196 || cantidad[NivelDistrito] != 1874 {
+		t.Errorf("departamentos = %d, provincias = %d, distritos = %d; se esperaba 25, 196 y 1874",
+			cantidad[NivelDepartamento], cantidad[NivelProvincia], cantidad[NivelDistrito])
+	}
+	for codigo, l := range lugares {
+		if l.Nivel == NivelProvincia {
+			if _, ok := lugares[codigo+"01"]; !ok {
+				t.Errorf("falta el distrito capital de %s", l)
+			}
+		}
+	}
+}
+
+func TestBuscar(t *testing.T) {
+	casos := []struct {
+		departamento, provincia, distrito string
+		codigo                            string
+	}{
+		{"LIMA", "LIMA", "LA MOLINA", "150114"},
+		{"Lima", "Lima", "Jesús María", "150113"},
+		{"AREQUIPA", "AREQUIPA", "YANAHUARA", "040126"},
+		{"PROV. CONST. DEL CALLAO", "PROV. CONST. DEL CALLAO", "BELLAVISTA", "070102"},
+		{"LAMBAYEQUE", "FERRENAFE", "FERREÑAFE", "140201"},
+		{"SAN MARTIN", "BELLAVISTA", "BELLAVISTA", "220201"},
+		{"PIURA", "SULLANA", "BELLAVISTA", "200602"},
+		{"JUNIN", "HUANCAYO", "EL TAMBO", "120114"},
+		{"AMAZONAS", "LUYA", "SAN JERONIMO", "010518"},
+	}
+	for _, c := range casos {
+		l, err := Buscar(c.departamento, c.provincia, c.distrito)
+		if err != nil || l.Codigo != c.codigo || l.Nivel != NivelDistrito {
+			t.Errorf("Buscar(%q, %q, %q) = %v, %v; se esperaba %s", c.departamento, c.provincia, c.distrito, l, err, c.codigo)
+		}
+	}
+
+	for _, c := range [][3]string{
+		{"LIMA", "AREQUIPA", "YANAHUARA"},
+		{"LIMA", "LIMA", "YANAHUARA"},
+		{"ATLANTIDA", "LIMA", "LIMA"},
+	} {
+		if _, err := Buscar(c[0], c[1], c[2]); !errors.Is(err, ErrUbigeoDesconocido) {
+			t.Errorf("Buscar(%q) aceptado: %v", c, err)
+		}
+	}
+}
+
+func TestObtener(t *testing.T) {
+	l, err := Obtener("0701")
+	if err != nil || l.Nombre != "CALLAO" || l.Nivel != NivelProvincia {
+		t.Errorf("Obtener(0701) = %+v, %v", l, err)
+	}
+	if _, err := Obtener("999999"); !errors.Is(err, ErrUbigeoDesconocido) {
+		t.Errorf("Obtener(999999) aceptado: %v", err)
+	}
+}
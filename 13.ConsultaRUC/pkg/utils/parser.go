@@ -7,6 +7,9 @@ import (
 
 // ParseMonto convierte un string de monto a float64
 // Ejemplo: "S/ 1,234.56" -> 1234.56
+//
+// Deprecated: retorna 0 ante cualquier error, igual que una deuda cero. Usar
+// models.ParseMonto, que es exacto y retorna error.
 func ParseMonto(montoStr string) float64 {
 	// Remover símbolo de moneda
 	cleaned := strings.ReplaceAll(montoStr, "S/", "")
@@ -59,6 +62,10 @@ func ParseFecha(fechaStr string) string {
 
 // ExtractPeriodo extrae el periodo en formato YYYYMM
 // Ejemplo: "ENERO 2023" -> "202301"
+//
+// Deprecated: solo reconoce "MES aaaa" y retorna el texto sin cambios ante
+// cualquier otro formato. Usar models.ParsePeriodo, que acepta todas las
+// variantes de SUNAT y retorna error.
 func ExtractPeriodo(periodoStr string) string {
 	meses := map[string]string{
 		"ENERO": "01", "FEBRERO": "02", "MARZO": "03", "ABRIL": "04",
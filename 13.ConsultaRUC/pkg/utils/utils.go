@@ -9,6 +9,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/consulta-ruc-scraper/pkg/ruc"
 )
 
 // GetRandomProxyFromFile obtiene un proxy aleatorio del archivo
@@ -73,18 +75,9 @@ func ReadProxiesFromFile(filename string) ([]string, error) {
 	return validProxies, nil
 }
 
-// IsValidRUC valida que el RUC tenga el formato correcto
-func IsValidRUC(ruc string) bool {
-	if len(ruc) != 11 {
-		return false
-	}
-	// Verificar que solo contenga dígitos
-	for _, char := range ruc {
-		if char < '0' || char > '9' {
-			return false
-		}
-	}
-	return true
+// IsValidRUC valida el formato, el prefijo y el dígito verificador del RUC
+func IsValidRUC(numero string) bool {
+	return ruc.Valido(numero)
 }
 
 // IsValidProxyFormat valida que el proxy tenga formato IP:PORT
@@ -9,25 +9,18 @@ DB_PASSWORD="admin123"
 DB_HOST="192.168.18.16"
 DB_PORT="5432"
 
-# Ruta del archivo SQL
-SQL_FILE="./database/prueba.sql"
+# El esquema son las migraciones embebidas en pkg/database/migraciones;
+# cmd/migrate aplica las que falten y las registra en schema_migrations
+export DATABASE_URL="postgres://$DB_USER:$DB_PASSWORD@$DB_HOST:$DB_PORT/$DB_NAME?sslmode=disable"
 
-# Verificar si el archivo SQL existe
-if [ ! -f "$SQL_FILE" ]; then
-    echo "Error: El archivo $SQL_FILE no existe"
-    exit 1
-fi
+echo "Aplicando migraciones en la base de datos: $DB_NAME en $DB_HOST:$DB_PORT"
 
-echo "Ejecutando script SQL: $SQL_FILE"
-echo "Conectando a la base de datos: $DB_NAME en $DB_HOST:$DB_PORT"
-
-# Ejecutar el script SQL
-PGPASSWORD=$DB_PASSWORD psql -h $DB_HOST -p $DB_PORT -U $DB_USER -d $DB_NAME -f $SQL_FILE
+go run ./cmd/migrate up
 
 # Verificar si la ejecución fue exitosa
 if [ $? -eq 0 ]; then
-    echo "Script ejecutado exitosamente!"
+    go run ./cmd/migrate status
 else
-    echo "Error al ejecutar el script SQL"
+    echo "Error al aplicar las migraciones"
     exit 1
-fi
\ No newline at end of file
+fi
@@ -12,17 +12,7 @@ DB_PORT="5432"
 # Exportar la contraseña para psql
 export PGPASSWORD="$DB_PASS"
 
-# Crear la tabla si no existe
-psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" <<'EOF'
-CREATE TABLE IF NOT EXISTS log_consultas (
-    id SERIAL PRIMARY KEY,
-    ruc VARCHAR(11) NOT NULL,
-    estado VARCHAR(50) NOT NULL,
-    mensaje TEXT,
-    fecha_registro TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-);
-ALTER TABLE log_consultas ADD COLUMN especificacion TEXT;
-EOF
+# log_consultas la crea la migración 1 (./scripts/crear_tablas.sh)
 
 # Insertar datos iniciales desde empresas_sunat
 psql -h "$DB_HOST" -p "$DB_PORT" -U "$DB_USER" -d "$DB_NAME" <<'EOF'